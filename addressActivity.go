@@ -0,0 +1,166 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+)
+
+const (
+	activityDirectionSender   = "sender"
+	activityDirectionReceiver = "receiver"
+	activityDirectionRelayer  = "relayer"
+)
+
+// addressActivitiesBuilder collects the address activity entries of a block. Only the addresses of the self shard get
+// entries, so each party of a cross shard transfer is recorded by the block of its own shard
+type addressActivitiesBuilder struct {
+	tdp        *txDatabaseProcessor
+	timestamp  time.Duration
+	shardID    uint32
+	blockNonce uint64
+	activities []*data.AddressActivity
+}
+
+// getAddressActivities returns the address activity entries of the transactions of a block, of their smart contract
+// results and of the smart contract results of previous transactions
+func (tdp *txDatabaseProcessor) getAddressActivities(
+	header coreData.HeaderHandler,
+	transactions []*data.Transaction,
+	scrsOfPreviousTxs map[string][]data.ScResult,
+	selfShardID uint32,
+) []*data.AddressActivity {
+	builder := &addressActivitiesBuilder{
+		tdp:        tdp,
+		timestamp:  time.Duration(header.GetTimeStamp()),
+		shardID:    selfShardID,
+		blockNonce: header.GetNonce(),
+		activities: make([]*data.AddressActivity, 0),
+	}
+
+	for _, tx := range transactions {
+		builder.addTransaction(tx)
+		for _, scr := range tx.SmartContractResults {
+			builder.addScResult(scr)
+		}
+	}
+	for _, scrs := range scrsOfPreviousTxs {
+		for _, scr := range scrs {
+			builder.addScResult(scr)
+		}
+	}
+
+	return builder.activities
+}
+
+func (aab *addressActivitiesBuilder) addTransaction(tx *data.Transaction) {
+	isRelayed := tx.InnerSender != ""
+	switch {
+	case tx.Operation == operationReward:
+		// the rewards are sent by the metachain itself, not by an address
+	case isRelayed:
+		aab.add(tx.Sender, tx.Hash, "", activityDirectionRelayer, tx.Value, nil, nil)
+	default:
+		aab.add(tx.Sender, tx.Hash, "", activityDirectionSender, tx.Value, tx.Tokens, tx.ESDTValues)
+	}
+
+	// the receivers of invalid transactions are not reached
+	if tx.Status == transaction.TxStatusInvalid.String() {
+		return
+	}
+
+	receiver, value := tx.Receiver, tx.Value
+	if isRelayed {
+		receiver, value = tx.InnerReceiver, tx.InnerValue
+		aab.add(tx.InnerSender, tx.Hash, "", activityDirectionSender, value, tx.Tokens, tx.ESDTValues)
+	}
+
+	for _, address := range getActivityReceivers(receiver, tx.Receivers) {
+		aab.add(address, tx.Hash, "", activityDirectionReceiver, value, tx.Tokens, tx.ESDTValues)
+	}
+}
+
+func (aab *addressActivitiesBuilder) addScResult(scr data.ScResult) {
+	aab.add(scr.Sender, scr.Hash, scr.OriginalTxHash, activityDirectionSender, scr.Value, scr.Tokens, scr.ESDTValues)
+	aab.add(scr.RelayerAddr, scr.Hash, scr.OriginalTxHash, activityDirectionRelayer, scr.RelayedValue, nil, nil)
+
+	for _, address := range getActivityReceivers(scr.Receiver, scr.Receivers) {
+		aab.add(address, scr.Hash, scr.OriginalTxHash, activityDirectionReceiver, scr.Value, scr.Tokens, scr.ESDTValues)
+	}
+}
+
+// add records an entry for each transferred token, or a single entry with the native value if there is no token
+func (aab *addressActivitiesBuilder) add(
+	address string,
+	hash string,
+	originalTxHash string,
+	direction string,
+	value string,
+	tokens []string,
+	esdtValues []string,
+) {
+	if !aab.isSelfShardAddress(address) {
+		return
+	}
+
+	if len(tokens) == 0 {
+		aab.activities = append(aab.activities, aab.newActivity(address, hash, originalTxHash, direction, "", value, 0))
+		return
+	}
+
+	for index, token := range tokens {
+		esdtValue := ""
+		if index < len(esdtValues) {
+			esdtValue = esdtValues[index]
+		}
+		aab.activities = append(aab.activities, aab.newActivity(address, hash, originalTxHash, direction, token, esdtValue, index))
+	}
+}
+
+func (aab *addressActivitiesBuilder) newActivity(
+	address string,
+	hash string,
+	originalTxHash string,
+	direction string,
+	token string,
+	value string,
+	index int,
+) *data.AddressActivity {
+	return &data.AddressActivity{
+		Address:        address,
+		TxHash:         hash,
+		OriginalTxHash: originalTxHash,
+		Direction:      direction,
+		Token:          token,
+		Value:          value,
+		Timestamp:      aab.timestamp,
+		ShardID:        aab.shardID,
+		BlockNonce:     aab.blockNonce,
+		Index:          index,
+	}
+}
+
+func (aab *addressActivitiesBuilder) isSelfShardAddress(encodedAddress string) bool {
+	if encodedAddress == "" {
+		return false
+	}
+
+	address, err := aab.tdp.addressPubkeyConverter.Decode(encodedAddress)
+	if err != nil {
+		return false
+	}
+
+	return aab.tdp.shardCoordinator.ComputeId(address) == aab.shardID
+}
+
+// getActivityReceivers returns the receivers of the transferred values. The transfers of NFTs are sent to the sender
+// itself, the actual receivers being decoded from the data field
+func getActivityReceivers(receiver string, receivers []string) []string {
+	if len(receivers) > 0 {
+		return receivers
+	}
+
+	return []string{receiver}
+}
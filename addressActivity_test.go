@@ -0,0 +1,195 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func createActivityTxDatabaseProcessor() *txDatabaseProcessor {
+	return createTestTxDatabaseProcessor(&mock.ShardCoordinatorMock{
+		SelfID: 0,
+		ComputeIdCalled: func(address []byte) uint32 {
+			if bytes.HasPrefix(address, []byte("other")) {
+				return 1
+			}
+			return 0
+		},
+	})
+}
+
+func encodedAddress(address string) string {
+	return hex.EncodeToString([]byte(address))
+}
+
+func TestTxDatabaseProcessor_GetAddressActivities(t *testing.T) {
+	t.Parallel()
+
+	txDbProc := createActivityTxDatabaseProcessor()
+	transactions := []*data.Transaction{
+		{
+			Hash:     "transfer",
+			Sender:   encodedAddress("alice"),
+			Receiver: encodedAddress("otherBob"),
+			Value:    "10",
+			SmartContractResults: []data.ScResult{
+				{Hash: "scr", OriginalTxHash: "transfer", Sender: encodedAddress("otherBob"), Receiver: encodedAddress("alice"), Value: "1"},
+			},
+		},
+		{
+			Hash:       "esdtTransfer",
+			Sender:     encodedAddress("alice"),
+			Receiver:   encodedAddress("alice"),
+			Value:      "0",
+			Tokens:     []string{"TKN-1", "TKN-2"},
+			ESDTValues: []string{"5", "6"},
+			Receivers:  []string{encodedAddress("carol")},
+		},
+		{
+			Hash:          "relayed",
+			Sender:        encodedAddress("relayer"),
+			Receiver:      encodedAddress("alice"),
+			Value:         "0",
+			InnerSender:   encodedAddress("alice"),
+			InnerReceiver: encodedAddress("carol"),
+			InnerValue:    "7",
+		},
+		{
+			Hash:     "invalid",
+			Sender:   encodedAddress("alice"),
+			Receiver: encodedAddress("carol"),
+			Value:    "3",
+			Status:   transaction.TxStatusInvalid.String(),
+		},
+		{
+			Hash:      "reward",
+			Sender:    "4294967295",
+			Receiver:  encodedAddress("carol"),
+			Value:     "2",
+			Operation: operationReward,
+		},
+	}
+	scrsOfPreviousTxs := map[string][]data.ScResult{
+		"previous": {
+			{Hash: "previousScr", OriginalTxHash: "previous", Sender: encodedAddress("otherBob"), Receiver: encodedAddress("carol"), Value: "4", RelayerAddr: encodedAddress("relayer"), RelayedValue: "0"},
+		},
+	}
+
+	activities := txDbProc.getAddressActivities(&dataBlock.Header{Nonce: 5, TimeStamp: 100}, transactions, scrsOfPreviousTxs, 0)
+
+	type activityKey struct {
+		address, hash, direction, token, value string
+	}
+	keys := make(map[activityKey]struct{})
+	for _, activity := range activities {
+		require.Equal(t, uint32(0), activity.ShardID)
+		require.Equal(t, uint64(5), activity.BlockNonce)
+		require.Equal(t, uint64(100), uint64(activity.Timestamp))
+		keys[activityKey{activity.Address, activity.TxHash, activity.Direction, activity.Token, activity.Value}] = struct{}{}
+	}
+
+	expectedKeys := []activityKey{
+		{encodedAddress("alice"), "transfer", activityDirectionSender, "", "10"},
+		{encodedAddress("alice"), "scr", activityDirectionReceiver, "", "1"},
+		{encodedAddress("alice"), "esdtTransfer", activityDirectionSender, "TKN-1", "5"},
+		{encodedAddress("alice"), "esdtTransfer", activityDirectionSender, "TKN-2", "6"},
+		{encodedAddress("carol"), "esdtTransfer", activityDirectionReceiver, "TKN-1", "5"},
+		{encodedAddress("carol"), "esdtTransfer", activityDirectionReceiver, "TKN-2", "6"},
+		{encodedAddress("relayer"), "relayed", activityDirectionRelayer, "", "0"},
+		{encodedAddress("alice"), "relayed", activityDirectionSender, "", "7"},
+		{encodedAddress("carol"), "relayed", activityDirectionReceiver, "", "7"},
+		{encodedAddress("alice"), "invalid", activityDirectionSender, "", "3"},
+		{encodedAddress("carol"), "reward", activityDirectionReceiver, "", "2"},
+		{encodedAddress("carol"), "previousScr", activityDirectionReceiver, "", "4"},
+		{encodedAddress("relayer"), "previousScr", activityDirectionRelayer, "", "0"},
+	}
+	require.Len(t, activities, len(expectedKeys))
+	for _, key := range expectedKeys {
+		_, found := keys[key]
+		require.True(t, found, key)
+	}
+}
+
+func TestSerializeAddressActivities(t *testing.T) {
+	t.Parallel()
+
+	activities := []*data.AddressActivity{
+		{Address: "alice", TxHash: "hash", Direction: activityDirectionReceiver, Token: "TKN-2", Value: "6", ShardID: 1, BlockNonce: 5, Index: 1},
+	}
+
+	buffSlice := data.NewBufferSlice()
+	err := serializeAddressActivities(activities, buffSlice)
+	require.Nil(t, err)
+
+	expected := `{"index":{"_id":"alice_hash_receiver_1"}}` + "\n" +
+		`{"address":"alice","txHash":"hash","direction":"receiver","token":"TKN-2","value":"6","timestamp":0,"shardId":1,"blockNonce":5}` + "\n"
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestElasticProcessor_RevertAddressActivity(t *testing.T) {
+	t.Parallel()
+
+	var deleteQuery map[string]interface{}
+	deleteIndex := ""
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoDeleteByQueryCalled: func(query map[string]interface{}, index string) error {
+			deleteQuery = query
+			deleteIndex = index
+			return nil
+		},
+	}
+
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.RevertAddressActivity(&dataBlock.Header{ShardID: 1, Nonce: 5})
+	require.Nil(t, err)
+	require.Empty(t, deleteIndex)
+
+	args.EnabledIndexes[addressActivityIndex] = struct{}{}
+	elasticProc, err = NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.RevertAddressActivity(&dataBlock.Header{ShardID: 1, Nonce: 5})
+	require.Nil(t, err)
+	require.Equal(t, addressActivityIndex, deleteIndex)
+	require.Equal(t, getBlockEntriesQuery(1, 5), objectsMap(deleteQuery))
+}
+
+func TestElasticProcessor_SaveAddressActivitiesWithBatching(t *testing.T) {
+	t.Parallel()
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.BlocksBatchSize = 2
+	args.EnabledIndexes[addressActivityIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] += buff.String()
+			return nil
+		},
+	}
+
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	activities := []*data.AddressActivity{
+		{Address: "alice", TxHash: "hash", Direction: activityDirectionSender, Value: "1"},
+	}
+	err = elasticProc.saveAddressActivities(activities)
+	require.Nil(t, err)
+	require.Empty(t, bulkRequests)
+
+	elasticProc.blocksBatcher.blockAdded()
+	err = elasticProc.FlushBatch(true)
+	require.Nil(t, err)
+	require.Contains(t, bulkRequests[addressActivityIndex], `{"index":{"_id":"alice_hash_sender_0"}}`)
+}
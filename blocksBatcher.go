@@ -0,0 +1,269 @@
+package indexer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// batchedIndexes holds the indexes that can be batched, in the order in which they have to be flushed
+var batchedIndexes = []string{blockIndex, miniblocksIndex, txIndex, addressActivityIndex}
+
+type pendingBulk struct {
+	index string
+	buff  *bytes.Buffer
+}
+
+// blocksBatcher accumulates the bulk operations of several consecutive blocks so they can be sent to the
+// elasticsearch server in fewer requests. The operations of each index are kept in insertion order and the indexes
+// are always flushed in the order defined by batchedIndexes
+type blocksBatcher struct {
+	mutex             sync.Mutex
+	maxBlocks         int
+	maxBytes          int
+	maxAge            time.Duration
+	buffers           map[string]bulkBuffer
+	inFlight          []pendingBulk
+	pendingIDs        map[string]map[string]struct{}
+	batchedOperations map[string]map[string]struct{}
+	numBlocks         int
+	firstBlockAt      time.Time
+	getTimeNow        func() time.Time
+	newBuffer         func() bulkBuffer
+	flushHandlers     []func()
+}
+
+// batchedBuffer is the buffer that accumulates the operations of an index in the batch. An operation identical to one
+// that is already batched is skipped, so the operations of a block indexed again after a failed flush are sent once
+type batchedBuffer struct {
+	bulkBuffer
+	index   string
+	batcher *blocksBatcher
+}
+
+// PutIndex adds an index operation, unless it is already batched
+func (buff *batchedBuffer) PutIndex(id string, doc []byte) error {
+	if !buff.batcher.addOperation(buff.index, id, "index", doc) {
+		return nil
+	}
+
+	return buff.bulkBuffer.PutIndex(id, doc)
+}
+
+// PutUpdateDoc adds a partial update operation, unless it is already batched
+func (buff *batchedBuffer) PutUpdateDoc(id string, partialDoc []byte) error {
+	if !buff.batcher.addOperation(buff.index, id, "updateDoc", partialDoc) {
+		return nil
+	}
+
+	return buff.bulkBuffer.PutUpdateDoc(id, partialDoc)
+}
+
+// PutUpdateScript adds a scripted update operation, unless it is already batched
+func (buff *batchedBuffer) PutUpdateScript(id string, script []byte) error {
+	if !buff.batcher.addOperation(buff.index, id, "updateScript", script) {
+		return nil
+	}
+
+	return buff.bulkBuffer.PutUpdateScript(id, script)
+}
+
+// PutUpsert adds an upsert operation, unless it is already batched
+func (buff *batchedBuffer) PutUpsert(id string, script []byte, doc []byte) error {
+	if !buff.batcher.addOperation(buff.index, id, "upsert", script, doc) {
+		return nil
+	}
+
+	return buff.bulkBuffer.PutUpsert(id, script, doc)
+}
+
+// PutDelete adds a delete operation, unless it is already batched
+func (buff *batchedBuffer) PutDelete(id string) error {
+	if !buff.batcher.addOperation(buff.index, id, "delete") {
+		return nil
+	}
+
+	return buff.bulkBuffer.PutDelete(id)
+}
+
+func newBlocksBatcher(maxBlocks int, maxBytes int, maxAge time.Duration, newBuffer func() bulkBuffer) *blocksBatcher {
+	return &blocksBatcher{
+		maxBlocks:  maxBlocks,
+		maxBytes:   maxBytes,
+		maxAge:     maxAge,
+		buffers:    make(map[string]bulkBuffer),
+		inFlight:   make([]pendingBulk, 0),
+		pendingIDs: make(map[string]map[string]struct{}),
+		getTimeNow: time.Now,
+		newBuffer:  newBuffer,
+
+		batchedOperations: make(map[string]map[string]struct{}),
+	}
+}
+
+// isEnabled returns true if the blocks should be accumulated before being sent
+func (bb *blocksBatcher) isEnabled() bool {
+	return bb.maxBlocks > 1
+}
+
+// getBuffer returns the buffer that accumulates the operations of the provided index
+func (bb *blocksBatcher) getBuffer(index string) bulkBuffer {
+	buff, ok := bb.buffers[index]
+	if !ok {
+		buff = &batchedBuffer{
+			bulkBuffer: bb.newBuffer(),
+			index:      index,
+			batcher:    bb,
+		}
+		bb.buffers[index] = buff
+	}
+
+	return buff
+}
+
+// addOperation records an operation of the batch and returns false if an identical operation is already batched. The
+// operations are identified by the document ID and by the digest of their content
+func (bb *blocksBatcher) addOperation(index string, id string, operation string, contents ...[]byte) bool {
+	hash := sha256.New()
+	hash.Write([]byte(operation))
+	for _, content := range contents {
+		hash.Write(content)
+	}
+	key := id + string(hash.Sum(nil))
+
+	operations, ok := bb.batchedOperations[index]
+	if !ok {
+		operations = make(map[string]struct{})
+		bb.batchedOperations[index] = operations
+	}
+
+	_, isBatched := operations[key]
+	if isBatched {
+		return false
+	}
+
+	operations[key] = struct{}{}
+
+	return true
+}
+
+// isPending returns true if the provided document is waiting in the batch
+func (bb *blocksBatcher) isPending(index string, id string) bool {
+	_, isPending := bb.pendingIDs[index][id]
+	return isPending
+}
+
+// addPendingIDs marks the provided documents as already written, even if they were not flushed yet
+func (bb *blocksBatcher) addPendingIDs(index string, ids []string) {
+	pendingIDs, ok := bb.pendingIDs[index]
+	if !ok {
+		pendingIDs = make(map[string]struct{})
+		bb.pendingIDs[index] = pendingIDs
+	}
+
+	for _, id := range ids {
+		pendingIDs[id] = struct{}{}
+	}
+}
+
+// mergePendingIDs will mark as existing all the documents that are waiting in the batch
+func (bb *blocksBatcher) mergePendingIDs(index string, ids []string, existsInDb map[string]bool) map[string]bool {
+	pendingIDs := bb.pendingIDs[index]
+	for _, id := range ids {
+		_, isPending := pendingIDs[id]
+		if isPending {
+			existsInDb[id] = true
+		}
+	}
+
+	return existsInDb
+}
+
+// blockAdded will update the counters used to decide if the batch has to be flushed
+func (bb *blocksBatcher) blockAdded() {
+	if bb.numBlocks == 0 {
+		bb.firstBlockAt = bb.getTimeNow()
+	}
+	bb.numBlocks++
+}
+
+// shouldFlush returns true if the batch reached one of its limits
+func (bb *blocksBatcher) shouldFlush() bool {
+	if len(bb.inFlight) > 0 {
+		return true
+	}
+	if bb.numBlocks == 0 {
+		return false
+	}
+	if bb.numBlocks >= bb.maxBlocks {
+		return true
+	}
+	if bb.maxBytes > 0 && bb.numBytes() >= bb.maxBytes {
+		return true
+	}
+
+	return bb.maxAge > 0 && bb.getTimeNow().Sub(bb.firstBlockAt) >= bb.maxAge
+}
+
+func (bb *blocksBatcher) numBytes() int {
+	numBytes := 0
+	for _, buff := range bb.buffers {
+		for _, b := range buff.Buffers() {
+			numBytes += b.Len()
+		}
+	}
+
+	return numBytes
+}
+
+// notifyWhenFlushed records a handler that is called after the next successful flush. The handler is called right
+// away if there is nothing to flush
+func (bb *blocksBatcher) notifyWhenFlushed(handler func()) {
+	if len(bb.inFlight) == 0 && bb.numBytes() == 0 {
+		handler()
+		return
+	}
+
+	bb.flushHandlers = append(bb.flushHandlers, handler)
+}
+
+// flush will send all the accumulated operations using the provided handler. The operations that were not sent
+// because of an error are kept and will be sent, in the same order, on the next flush
+func (bb *blocksBatcher) flush(doBulkRequest func(buff *bytes.Buffer, index string) error) error {
+	for _, index := range batchedIndexes {
+		buff, ok := bb.buffers[index]
+		if !ok {
+			continue
+		}
+
+		for _, b := range buff.Buffers() {
+			if b.Len() == 0 {
+				continue
+			}
+			bb.inFlight = append(bb.inFlight, pendingBulk{index: index, buff: b})
+		}
+	}
+	bb.buffers = make(map[string]bulkBuffer)
+
+	for len(bb.inFlight) > 0 {
+		bulk := bb.inFlight[0]
+		err := doBulkRequest(bulk.buff, bulk.index)
+		if err != nil {
+			return err
+		}
+
+		bb.inFlight = bb.inFlight[1:]
+	}
+
+	bb.pendingIDs = make(map[string]map[string]struct{})
+	bb.batchedOperations = make(map[string]map[string]struct{})
+	bb.numBlocks = 0
+
+	for _, handler := range bb.flushHandlers {
+		handler()
+	}
+	bb.flushHandlers = nil
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package indexer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocksBatcher_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, newBlocksBatcher(0, 0, 0, createBulkBuffer).isEnabled())
+	require.False(t, newBlocksBatcher(1, 0, 0, createBulkBuffer).isEnabled())
+	require.True(t, newBlocksBatcher(2, 0, 0, createBulkBuffer).isEnabled())
+}
+
+func TestBlocksBatcher_ShouldFlush(t *testing.T) {
+	t.Parallel()
+
+	currentTime := time.Unix(1000, 0)
+	bb := newBlocksBatcher(3, 100, time.Second, createBulkBuffer)
+	bb.getTimeNow = func() time.Time {
+		return currentTime
+	}
+	require.False(t, bb.shouldFlush())
+
+	bb.blockAdded()
+	require.False(t, bb.shouldFlush())
+
+	currentTime = currentTime.Add(time.Second)
+	require.True(t, bb.shouldFlush())
+
+	bb.numBlocks = 0
+	bb.blockAdded()
+	bb.blockAdded()
+	bb.blockAdded()
+	require.True(t, bb.shouldFlush())
+
+	bb.numBlocks = 1
+	bb.firstBlockAt = currentTime
+	_ = bb.getBuffer(blockIndex).PutIndex("meta", bytes.Repeat([]byte("a"), 100))
+	require.True(t, bb.shouldFlush())
+}
+
+func TestBlocksBatcher_FlushKeepsIndexesOrder(t *testing.T) {
+	t.Parallel()
+
+	bb := newBlocksBatcher(2, 0, 0, createBulkBuffer)
+	_ = bb.getBuffer(txIndex).PutIndex("tx", []byte("1"))
+	_ = bb.getBuffer(miniblocksIndex).PutIndex("mb", []byte("1"))
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("1"))
+	bb.addPendingIDs(miniblocksIndex, []string{"mb1"})
+	bb.blockAdded()
+
+	flushedIndexes := make([]string, 0)
+	err := bb.flush(func(buff *bytes.Buffer, index string) error {
+		flushedIndexes = append(flushedIndexes, index)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{blockIndex, miniblocksIndex, txIndex}, flushedIndexes)
+	require.Equal(t, 0, bb.numBlocks)
+	require.Len(t, bb.pendingIDs, 0)
+}
+
+func TestBlocksBatcher_FlushErrorShouldResendRemainingBuffersFirst(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	bb := newBlocksBatcher(2, 0, 0, createBulkBuffer)
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("1"))
+	_ = bb.getBuffer(txIndex).PutIndex("tx", []byte("1"))
+	bb.addPendingIDs(blockIndex, []string{"block1"})
+
+	err := bb.flush(func(buff *bytes.Buffer, index string) error {
+		if index == txIndex {
+			return localErr
+		}
+		return nil
+	})
+	require.Equal(t, localErr, err)
+	require.Len(t, bb.inFlight, 1)
+	require.True(t, bb.shouldFlush())
+	require.Equal(t, map[string]bool{"block1": true}, bb.mergePendingIDs(blockIndex, []string{"block1"}, map[string]bool{}))
+
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("2"))
+	flushed := make([]string, 0)
+	err = bb.flush(func(buff *bytes.Buffer, index string) error {
+		flushed = append(flushed, buff.String())
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, flushed, 2)
+	require.Contains(t, flushed[0], `"_id":"tx"`)
+	require.Contains(t, flushed[1], `"_id":"block"`)
+}
+
+func TestBlocksBatcher_RetriedOperationsShouldNotBeBatchedTwice(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	bb := newBlocksBatcher(2, 0, 0, createBulkBuffer)
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("1"))
+	_ = bb.getBuffer(txIndex).PutUpsert("tx", []byte("script"), []byte("1"))
+
+	err := bb.flush(func(buff *bytes.Buffer, index string) error {
+		return localErr
+	})
+	require.Equal(t, localErr, err)
+	require.Len(t, bb.inFlight, 2)
+
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("1"))
+	_ = bb.getBuffer(txIndex).PutUpsert("tx", []byte("script"), []byte("1"))
+	_ = bb.getBuffer(txIndex).PutUpsert("tx", []byte("other script"), []byte("1"))
+
+	flushed := make([]string, 0)
+	err = bb.flush(func(buff *bytes.Buffer, index string) error {
+		flushed = append(flushed, buff.String())
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, flushed, 3)
+	require.Contains(t, flushed[0], `"_id":"block"`)
+	require.Contains(t, flushed[1], `"script" : script`)
+	require.Contains(t, flushed[2], `"script" : other script`)
+	require.Len(t, bb.batchedOperations, 0)
+
+	require.True(t, bb.addOperation(blockIndex, "block", "index", []byte("1")))
+}
+
+func createBulkBuffer() bulkBuffer {
+	return data.NewBufferSlice()
+}
+
+func TestBlocksBatcher_NotifyWhenFlushed(t *testing.T) {
+	t.Parallel()
+
+	bb := newBlocksBatcher(2, 0, 0, createBulkBuffer)
+	numCalls := 0
+	bb.notifyWhenFlushed(func() {
+		numCalls++
+	})
+	require.Equal(t, 1, numCalls)
+
+	_ = bb.getBuffer(blockIndex).PutIndex("block", []byte("1"))
+	bb.blockAdded()
+	bb.notifyWhenFlushed(func() {
+		numCalls++
+	})
+	require.Equal(t, 1, numCalls)
+
+	localErr := errors.New("local err")
+	err := bb.flush(func(_ *bytes.Buffer, _ string) error {
+		return localErr
+	})
+	require.Equal(t, localErr, err)
+	require.Equal(t, 1, numCalls)
+
+	err = bb.flush(func(_ *bytes.Buffer, _ string) error {
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, numCalls)
+
+	err = bb.flush(func(_ *bytes.Buffer, _ string) error {
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, numCalls)
+}
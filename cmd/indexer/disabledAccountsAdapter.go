@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
+)
+
+var errAccountsNotAvailable = errors.New("the accounts state is not available in the indexer service")
+
+// indexesRequiringAccountsState holds the indexes that are populated from the state of the accounts altered by each
+// block, which includes the details of the accounts and the rolled back balances of the reverted blocks
+var indexesRequiringAccountsState = map[string]struct{}{
+	"accounts":        {},
+	"accountshistory": {},
+}
+
+// disabledAccountsAdapter is used because the indexer service has no access to the state of the node. As a result,
+// the indexes that require the accounts state are disabled and the nfts index holds no NFT details
+type disabledAccountsAdapter struct {
+}
+
+// removeIndexesRequiringAccountsState returns the provided indexes without the ones that can not be populated
+// without the accounts state
+func removeIndexesRequiringAccountsState(indexes []string) []string {
+	result := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		_, requiresState := indexesRequiringAccountsState[index]
+		if requiresState {
+			log.Warn("index disabled as the accounts state is not available in the indexer service", "index", index)
+			continue
+		}
+		if index == "nfts" {
+			log.Warn("the nfts index will hold no NFT details as the accounts state is not available in the indexer service")
+		}
+
+		result = append(result, index)
+	}
+
+	return result
+}
+
+// LoadAccount returns an error as the accounts state is not available
+func (daa *disabledAccountsAdapter) LoadAccount(_ []byte) (vmcommon.AccountHandler, error) {
+	return nil, errAccountsNotAvailable
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (daa *disabledAccountsAdapter) IsInterfaceNil() bool {
+	return daa == nil
+}
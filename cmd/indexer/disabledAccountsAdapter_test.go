@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisabledAccountsAdapter_LoadAccount(t *testing.T) {
+	t.Parallel()
+
+	account, err := (&disabledAccountsAdapter{}).LoadAccount([]byte("address"))
+	require.Nil(t, account)
+	require.Equal(t, errAccountsNotAvailable, err)
+}
+
+func TestRemoveIndexesRequiringAccountsState(t *testing.T) {
+	t.Parallel()
+
+	indexes := removeIndexesRequiringAccountsState([]string{"blocks", "accounts", "nfts", "accountshistory"})
+	require.Equal(t, []string{"blocks", "nfts"}, indexes)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math/big"
+
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+)
+
+// feeComputer computes the transactions fees based on the economics configuration of the network
+type feeComputer struct {
+	minGasLimit      uint64
+	gasPerDataByte   uint64
+	gasPriceModifier float64
+}
+
+// ComputeGasLimit returns the gas needed by the move balance part of the transaction
+func (fc *feeComputer) ComputeGasLimit(tx coreData.TransactionWithFeeHandler) uint64 {
+	return fc.minGasLimit + uint64(len(tx.GetData()))*fc.gasPerDataByte
+}
+
+// ComputeTxFeeBasedOnGasUsed returns the fee of a transaction that used the provided amount of gas
+func (fc *feeComputer) ComputeTxFeeBasedOnGasUsed(tx coreData.TransactionWithFeeHandler, gasUsed uint64) *big.Int {
+	moveBalanceGasLimit := fc.ComputeGasLimit(tx)
+	if gasUsed <= moveBalanceGasLimit {
+		return fc.multiply(gasUsed, tx.GetGasPrice())
+	}
+
+	moveBalanceFee := fc.multiply(moveBalanceGasLimit, tx.GetGasPrice())
+	processingFee := fc.multiply(gasUsed-moveBalanceGasLimit, fc.processingGasPrice(tx))
+
+	return moveBalanceFee.Add(moveBalanceFee, processingFee)
+}
+
+// ComputeGasUsedAndFeeBasedOnRefundValue returns the gas used and the fee of a transaction for which the provided
+// value was refunded
+func (fc *feeComputer) ComputeGasUsedAndFeeBasedOnRefundValue(tx coreData.TransactionWithFeeHandler, refundValue *big.Int) (uint64, *big.Int) {
+	txFee := fc.ComputeTxFeeBasedOnGasUsed(tx, tx.GetGasLimit())
+	if refundValue == nil || refundValue.Sign() == 0 {
+		return tx.GetGasLimit(), txFee
+	}
+
+	processingGasPrice := fc.processingGasPrice(tx)
+	if processingGasPrice == 0 {
+		return tx.GetGasLimit(), txFee
+	}
+
+	gasUnitsRefunded := big.NewInt(0).Div(refundValue, big.NewInt(0).SetUint64(processingGasPrice)).Uint64()
+	if gasUnitsRefunded > tx.GetGasLimit() {
+		gasUnitsRefunded = tx.GetGasLimit()
+	}
+
+	fee := txFee.Sub(txFee, refundValue)
+	if fee.Sign() < 0 {
+		fee.SetInt64(0)
+	}
+
+	return tx.GetGasLimit() - gasUnitsRefunded, fee
+}
+
+func (fc *feeComputer) processingGasPrice(tx coreData.TransactionWithFeeHandler) uint64 {
+	return uint64(fc.gasPriceModifier * float64(tx.GetGasPrice()))
+}
+
+func (fc *feeComputer) multiply(gas uint64, gasPrice uint64) *big.Int {
+	result := big.NewInt(0).SetUint64(gas)
+	return result.Mul(result, big.NewInt(0).SetUint64(gasPrice))
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fc *feeComputer) IsInterfaceNil() bool {
+	return fc == nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func createFeeComputer() *feeComputer {
+	return &feeComputer{
+		minGasLimit:      50000,
+		gasPerDataByte:   1500,
+		gasPriceModifier: 0.01,
+	}
+}
+
+func TestFeeComputer_ComputeGasLimit(t *testing.T) {
+	t.Parallel()
+
+	fc := createFeeComputer()
+	require.Equal(t, uint64(50000), fc.ComputeGasLimit(&transaction.Transaction{}))
+	require.Equal(t, uint64(56000), fc.ComputeGasLimit(&transaction.Transaction{Data: []byte("test")}))
+}
+
+func TestFeeComputer_ComputeTxFeeBasedOnGasUsed(t *testing.T) {
+	t.Parallel()
+
+	fc := createFeeComputer()
+	tx := &transaction.Transaction{GasPrice: 1000000000, GasLimit: 100000}
+
+	require.Equal(t, big.NewInt(50000000000000), fc.ComputeTxFeeBasedOnGasUsed(tx, 50000))
+	// 50000 gas at full price plus 50000 gas at 1% of the price
+	require.Equal(t, big.NewInt(50500000000000), fc.ComputeTxFeeBasedOnGasUsed(tx, 100000))
+}
+
+func TestFeeComputer_ComputeGasUsedAndFeeBasedOnRefundValue(t *testing.T) {
+	t.Parallel()
+
+	fc := createFeeComputer()
+	tx := &transaction.Transaction{GasPrice: 1000000000, GasLimit: 100000}
+
+	gasUsed, fee := fc.ComputeGasUsedAndFeeBasedOnRefundValue(tx, big.NewInt(0))
+	require.Equal(t, uint64(100000), gasUsed)
+	require.Equal(t, big.NewInt(50500000000000), fee)
+
+	// refund of 20000 gas units at the processing gas price
+	gasUsed, fee = fc.ComputeGasUsedAndFeeBasedOnRefundValue(tx, big.NewInt(200000000000))
+	require.Equal(t, uint64(80000), gasUsed)
+	require.Equal(t, big.NewInt(50300000000000), fee)
+}
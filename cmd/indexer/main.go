@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/factory"
+	"github.com/ElrondNetwork/elastic-indexer-go/payloads"
+	"github.com/ElrondNetwork/elrond-go-core/core/pubkeyConverter"
+	hasherFactory "github.com/ElrondNetwork/elrond-go-core/hashing/factory"
+	marshalFactory "github.com/ElrondNetwork/elrond-go-core/marshal/factory"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+)
+
+const (
+	addressLength       = 32
+	validatorKeyLength  = 96
+	shutdownGracePeriod = 10 * time.Second
+)
+
+var log = logger.GetOrCreate("indexer")
+
+type config struct {
+	listenAddress    string
+	logLevel         string
+	elasticURL       string
+	elasticUserName  string
+	elasticPassword  string
+	marshalizer      string
+	hasher           string
+	enabledIndexes   string
+	useKibana        bool
+	denomination     int
+	scaledValues     bool
+	cacheSize        int
+	refreshMode      string
+	numOfShards      uint
+	selfShardID      uint
+	minGasLimit      uint64
+	gasPerDataByte   uint64
+	gasPriceModifier float64
+	batchSize        int
+	batchMaxBytes    int
+	batchInterval    time.Duration
+}
+
+func main() {
+	cfg := parseFlags()
+
+	err := logger.SetLogLevel(cfg.logLevel)
+	if err != nil {
+		fmt.Println("cannot set the log level:", err)
+		os.Exit(1)
+	}
+
+	err = run(cfg)
+	if err != nil {
+		log.Error("indexer service stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags() *config {
+	cfg := &config{}
+	flag.StringVar(&cfg.listenAddress, "listen-address", "localhost:22111", "the address on which the payloads are received")
+	flag.StringVar(&cfg.logLevel, "log-level", "*:INFO", "the log level, as pattern:level pairs separated by commas")
+	flag.StringVar(&cfg.elasticURL, "elastic-url", "http://localhost:9200", "the url of the elasticsearch cluster")
+	flag.StringVar(&cfg.elasticUserName, "elastic-username", "", "the username of the elasticsearch cluster")
+	flag.StringVar(&cfg.elasticPassword, "elastic-password", "", "the password of the elasticsearch cluster")
+	flag.StringVar(&cfg.marshalizer, "marshalizer", marshalFactory.GogoProtobuf, "the marshalizer used by the node")
+	flag.StringVar(&cfg.hasher, "hasher", "blake2b", "the hasher used by the node")
+	flag.StringVar(&cfg.enabledIndexes, "enabled-indexes", "blocks,miniblocks,transactions,rounds,rating,validators,tokens,nfts,scdeploys,validatorsratinghistory,validatorsstatistics,delegators,providers,addressactivity", "the indexes to be populated, separated by commas. The accounts and accountshistory indexes are not supported")
+	flag.BoolVar(&cfg.useKibana, "use-kibana", false, "set if the elasticsearch cluster uses kibana and the opendistro plugins")
+	flag.IntVar(&cfg.denomination, "denomination", 18, "the number of decimals of the native token")
+	flag.BoolVar(&cfg.scaledValues, "scaled-numeric-values", false, "set if the amounts are also stored as scaled longs, for exact aggregations")
+	flag.IntVar(&cfg.cacheSize, "cache-size", 100, "the maximum number of items waiting to be indexed")
+	flag.StringVar(&cfg.refreshMode, "refresh-mode", "", "the refresh mode of the indexes: sync or tip")
+	flag.UintVar(&cfg.numOfShards, "num-shards", 3, "the number of shards of the network")
+	flag.UintVar(&cfg.selfShardID, "shard-id", 0, "the shard of the node that sends the payloads")
+	flag.Uint64Var(&cfg.minGasLimit, "min-gas-limit", 50000, "the minimum gas limit of a transaction")
+	flag.Uint64Var(&cfg.gasPerDataByte, "gas-per-data-byte", 1500, "the gas consumed for every byte of the transaction data")
+	flag.Float64Var(&cfg.gasPriceModifier, "gas-price-modifier", 0.01, "the modifier of the gas price for the gas used by the processing")
+	flag.IntVar(&cfg.batchSize, "blocks-batch-size", 10, "the maximum number of blocks sent in one bulk request. A value lower than 2 disables the batching")
+	flag.IntVar(&cfg.batchMaxBytes, "blocks-batch-max-bytes", 10*1024*1024, "the size of the batched operations after which the batch is sent. 0 means no limit")
+	flag.DurationVar(&cfg.batchInterval, "blocks-batch-interval", 6*time.Second, "the maximum time a block waits in the batch before being sent. 0 means no limit")
+	flag.Parse()
+
+	return cfg
+}
+
+func run(cfg *config) error {
+	indexerArgs, err := createIndexerFactoryArgs(cfg)
+	if err != nil {
+		return err
+	}
+
+	elasticIndexer, err := factory.NewIndexer(indexerArgs)
+	if err != nil {
+		return err
+	}
+
+	processor, err := payloads.NewPayloadsProcessor(indexerArgs.Marshalizer, elasticIndexer)
+	if err != nil {
+		return err
+	}
+
+	receiver, err := payloads.NewPayloadsReceiver(processor)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(payloads.PayloadsPath, receiver)
+	server := &http.Server{
+		Addr:    cfg.listenAddress,
+		Handler: mux,
+	}
+
+	chServerErr := make(chan error, 1)
+	go func() {
+		log.Info("indexer service started", "address", cfg.listenAddress, "elastic url", cfg.elasticURL)
+		chServerErr <- server.ListenAndServe()
+	}()
+
+	chSignal := make(chan os.Signal, 1)
+	signal.Notify(chSignal, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err = <-chServerErr:
+	case sig := <-chSignal:
+		log.Info("closing the indexer service", "signal", sig.String())
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+		err = server.Shutdown(ctx)
+		cancel()
+	}
+
+	closeErr := elasticIndexer.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func createIndexerFactoryArgs(cfg *config) (*factory.ArgsIndexerFactory, error) {
+	marshalizer, err := marshalFactory.NewMarshalizer(cfg.marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := hasherFactory.NewHasher(cfg.hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	addressConverter, err := pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorConverter, err := pubkeyConverter.NewHexPubkeyConverter(validatorKeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinator, err := newShardCoordinator(uint32(cfg.numOfShards), uint32(cfg.selfShardID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &factory.ArgsIndexerFactory{
+		Enabled:                  true,
+		IndexerCacheSize:         cfg.cacheSize,
+		ShardCoordinator:         coordinator,
+		Url:                      cfg.elasticURL,
+		UserName:                 cfg.elasticUserName,
+		Password:                 cfg.elasticPassword,
+		Marshalizer:              marshalizer,
+		Hasher:                   hasher,
+		AddressPubkeyConverter:   addressConverter,
+		ValidatorPubkeyConverter: validatorConverter,
+		UseKibana:                cfg.useKibana,
+		EnabledIndexes:           removeIndexesRequiringAccountsState(strings.Split(cfg.enabledIndexes, ",")),
+		Denomination:             cfg.denomination,
+		UseScaledNumericValues:   cfg.scaledValues,
+		AccountsDB:               &disabledAccountsAdapter{},
+		TransactionFeeCalculator: &feeComputer{
+			minGasLimit:      cfg.minGasLimit,
+			gasPerDataByte:   cfg.gasPerDataByte,
+			gasPriceModifier: cfg.gasPriceModifier,
+		},
+		RefreshMode:         cfg.refreshMode,
+		BlocksBatchSize:     cfg.batchSize,
+		BlocksBatchMaxBytes: cfg.batchMaxBytes,
+		BlocksBatchMaxAge:   cfg.batchInterval,
+	}, nil
+}
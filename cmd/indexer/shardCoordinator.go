@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+)
+
+var errInvalidShardsConfig = errors.New("invalid number of shards or self shard id")
+
+// shardCoordinator computes the shard of an address the same way the node does
+type shardCoordinator struct {
+	numberOfShards uint32
+	selfID         uint32
+	maskHigh       uint32
+	maskLow        uint32
+}
+
+func newShardCoordinator(numberOfShards uint32, selfID uint32) (*shardCoordinator, error) {
+	if numberOfShards == 0 {
+		return nil, errInvalidShardsConfig
+	}
+	if selfID >= numberOfShards && selfID != core.MetachainShardId {
+		return nil, errInvalidShardsConfig
+	}
+
+	n := math.Ceil(math.Log2(float64(numberOfShards)))
+	sc := &shardCoordinator{
+		numberOfShards: numberOfShards,
+		selfID:         selfID,
+		maskHigh:       (1 << uint(n)) - 1,
+	}
+	if n > 0 {
+		sc.maskLow = (1 << uint(n-1)) - 1
+	}
+
+	return sc, nil
+}
+
+// ComputeId returns the shard of the provided address
+func (sc *shardCoordinator) ComputeId(address []byte) uint32 {
+	bytesNeed := 4
+	switch {
+	case sc.numberOfShards <= 256:
+		bytesNeed = 1
+	case sc.numberOfShards <= 65536:
+		bytesNeed = 2
+	case sc.numberOfShards <= 16777216:
+		bytesNeed = 3
+	}
+
+	startingIndex := 0
+	if len(address) > bytesNeed {
+		startingIndex = len(address) - bytesNeed
+	}
+
+	buffNeeded := address[startingIndex:]
+	if core.IsSmartContractOnMetachain(buffNeeded, address) {
+		return core.MetachainShardId
+	}
+
+	addr := uint32(0)
+	for i := 0; i < len(buffNeeded); i++ {
+		addr = addr<<8 + uint32(buffNeeded[i])
+	}
+
+	shard := addr & sc.maskHigh
+	if shard > sc.numberOfShards-1 {
+		shard = addr & sc.maskLow
+	}
+
+	return shard
+}
+
+// NumberOfShards returns the number of shards of the network, without the metachain
+func (sc *shardCoordinator) NumberOfShards() uint32 {
+	return sc.numberOfShards
+}
+
+// SelfId returns the shard of the node that sends the payloads
+func (sc *shardCoordinator) SelfId() uint32 {
+	return sc.selfID
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sc *shardCoordinator) IsInterfaceNil() bool {
+	return sc == nil
+}
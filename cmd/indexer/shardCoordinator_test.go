@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShardCoordinator(t *testing.T) {
+	t.Parallel()
+
+	_, err := newShardCoordinator(0, 0)
+	require.Equal(t, errInvalidShardsConfig, err)
+
+	_, err = newShardCoordinator(3, 3)
+	require.Equal(t, errInvalidShardsConfig, err)
+
+	sc, err := newShardCoordinator(3, core.MetachainShardId)
+	require.Nil(t, err)
+	require.Equal(t, core.MetachainShardId, sc.SelfId())
+}
+
+func TestShardCoordinator_ComputeId(t *testing.T) {
+	t.Parallel()
+
+	sc, _ := newShardCoordinator(3, 0)
+	require.Equal(t, uint32(0), sc.ComputeId([]byte{1, 2, 0}))
+	require.Equal(t, uint32(1), sc.ComputeId([]byte{1, 2, 1}))
+	require.Equal(t, uint32(2), sc.ComputeId([]byte{1, 2, 2}))
+	require.Equal(t, uint32(1), sc.ComputeId([]byte{1, 2, 3}))
+
+	singleShard, _ := newShardCoordinator(1, 0)
+	require.Equal(t, uint32(0), singleShard.ComputeId([]byte{1, 2, 3}))
+
+	systemSCAddress := make([]byte, 32)
+	systemSCAddress[31] = 255
+	systemSCAddress[30] = 255
+	require.Equal(t, core.MetachainShardId, sc.ComputeId(systemSCAddress))
+}
@@ -40,8 +40,9 @@ func (cm *commonProcessor) buildTransaction(
 ) *data.Transaction {
 	gasUsed := cm.txFeeCalculator.ComputeGasLimit(tx)
 	fee := cm.txFeeCalculator.ComputeTxFeeBasedOnGasUsed(tx, gasUsed)
+	operation := cm.parseTxOperation(tx.RcvAddr, tx.Data)
 
-	return &data.Transaction{
+	dbTx := &data.Transaction{
 		Hash:                 hex.EncodeToString(txHash),
 		MBHash:               hex.EncodeToString(mbHash),
 		Nonce:                tx.Nonce,
@@ -62,7 +63,17 @@ func (cm *commonProcessor) buildTransaction(
 		ReceiverUserName:     tx.RcvUserName,
 		SenderUserName:       tx.SndUserName,
 		ReceiverAddressBytes: tx.RcvAddr,
+		Operation:            operation.operation,
+		Function:             operation.function,
+		Tokens:               operation.tokens,
+		ESDTValues:           operation.esdtValues,
+		Receivers:            operation.receivers,
+	}
+	if operation.operation == operationRelayed {
+		cm.setRelayedInnerTx(dbTx, tx)
 	}
+
+	return dbTx
 }
 
 func (cm *commonProcessor) buildRewardTransaction(
@@ -89,6 +100,7 @@ func (cm *commonProcessor) buildRewardTransaction(
 		Signature:     "",
 		Timestamp:     time.Duration(header.GetTimeStamp()),
 		Status:        txStatus,
+		Operation:     operationReward,
 	}
 }
 
@@ -97,6 +109,7 @@ func (cm *commonProcessor) convertScResultInDatabaseScr(scHash string, sc *smart
 	if len(sc.RelayerAddr) > 0 {
 		relayerAddr = cm.addressPubkeyConverter.Encode(sc.RelayerAddr)
 	}
+	operation := cm.parseTxOperation(sc.RcvAddr, sc.Data)
 
 	return data.ScResult{
 		Hash:           hex.EncodeToString([]byte(scHash)),
@@ -115,6 +128,11 @@ func (cm *commonProcessor) convertScResultInDatabaseScr(scHash string, sc *smart
 		CallType:       strconv.Itoa(int(sc.CallType)),
 		CodeMetadata:   sc.CodeMetadata,
 		ReturnMessage:  string(sc.ReturnMessage),
+		Operation:      operation.operation,
+		Function:       operation.function,
+		Tokens:         operation.tokens,
+		ESDTValues:     operation.esdtValues,
+		Receivers:      operation.receivers,
 	}
 }
 
@@ -122,232 +140,270 @@ func serializeBulkMiniBlocks(
 	hdrShardID uint32,
 	bulkMbs []*data.Miniblock,
 	getAlreadyIndexedItems func(hashes []string, index string) (map[string]bool, error),
-) (bytes.Buffer, map[string]bool) {
+	buffSlice bulkBuffer,
+) map[string]bool {
 	var err error
-	var buff bytes.Buffer
-
-	mbsHashes := make([]string, len(bulkMbs))
-	for idx := range bulkMbs {
-		mbsHashes[idx] = bulkMbs[idx].Hash
-	}
 
-	existsInDb, err := getAlreadyIndexedItems(mbsHashes, miniblocksIndex)
+	existsInDb, err := getAlreadyIndexedItems(getMiniblocksHashes(bulkMbs), miniblocksIndex)
 	if err != nil {
 		log.Warn("indexer get indexed items miniblocks",
 			"error", err.Error())
-		return buff, make(map[string]bool)
+		return make(map[string]bool)
 	}
 
 	for _, mb := range bulkMbs {
-		var meta, serializedData []byte
-		if !existsInDb[mb.Hash] {
-			//insert miniblock in database
-			meta = []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s", "_type" : "%s" } }%s`, mb.Hash, "_doc", "\n"))
-			serializedData, err = json.Marshal(mb)
-			if err != nil {
-				log.Debug("indexer: marshal",
-					"error", "could not serialize miniblock, will skip indexing",
-					"mb hash", mb.Hash)
-				continue
-			}
-		} else {
-			// update miniblock
-			meta = []byte(fmt.Sprintf(`{ "update" : { "_id" : "%s" } }%s`, mb.Hash, "\n"))
-			if hdrShardID == mb.SenderShardID {
-				// update sender block hash
-				serializedData = []byte(fmt.Sprintf(`{ "doc" : { "senderBlockHash" : "%s" } }`, mb.SenderBlockHash))
-			} else {
-				// update receiver block hash
-				serializedData = []byte(fmt.Sprintf(`{ "doc" : { "receiverBlockHash" : "%s" } }`, mb.ReceiverBlockHash))
-			}
+		// the miniblock is upserted even if it was not found, as the metachain can store its notarization meanwhile
+		err = putMiniblock(hdrShardID, mb, buffSlice)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk miniblocks, write", "error", err.Error())
 		}
-
-		buff = prepareBufferMiniblocks(buff, meta, serializedData)
 	}
 
-	return buff, existsInDb
+	return existsInDb
 }
 
-func prepareBufferMiniblocks(buff bytes.Buffer, meta, serializedData []byte) bytes.Buffer {
-	// append a newline for each element
-	serializedData = append(serializedData, "\n"...)
-	buff.Grow(len(meta) + len(serializedData))
-	_, err := buff.Write(meta)
+// putMiniblock adds the bulk operation that indexes the provided miniblock. A miniblock already indexed by the other
+// shard gets the block hash of this shard, while a miniblock that holds only its notarization gets all its fields
+func putMiniblock(hdrShardID uint32, mb *data.Miniblock, buffSlice bulkBuffer) error {
+	serializedData, err := json.Marshal(mb)
 	if err != nil {
-		log.Warn("elastic search: serialize bulk miniblocks, write meta", "error", err.Error())
+		log.Debug("indexer: marshal",
+			"error", "could not serialize miniblock, will skip indexing",
+			"mb hash", mb.Hash)
+		return err
 	}
-	_, err = buff.Write(serializedData)
+
+	blockHashFields := make([]string, 0, 2)
+	if hdrShardID == mb.SenderShardID {
+		blockHashFields = append(blockHashFields, "senderBlockHash")
+	}
+	if hdrShardID == mb.ReceiverShardID {
+		blockHashFields = append(blockHashFields, "receiverBlockHash")
+	}
+
+	script, err := json.Marshal(objectsMap{
+		"source": setMiniblockFieldsScript,
+		"lang":   "painless",
+		"params": objectsMap{
+			"doc":             json.RawMessage(serializedData),
+			"blockHashFields": blockHashFields,
+		},
+	})
 	if err != nil {
-		log.Warn("elastic search: serialize bulk miniblocks, write serialized miniblock", "error", err.Error())
+		return err
 	}
 
-	return buff
+	return buffSlice.PutUpsert(mb.Hash, script, serializedData)
+}
+
+func getMiniblocksHashes(miniblocks []*data.Miniblock) []string {
+	mbsHashes := make([]string, len(miniblocks))
+	for idx := range miniblocks {
+		mbsHashes[idx] = miniblocks[idx].Hash
+	}
+
+	return mbsHashes
 }
 
 func serializeTransactions(
 	transactions []*data.Transaction,
 	selfShardID uint32,
-	_ func(hashes []string, index string) (map[string]bool, error),
-	mbsHashInDB map[string]bool,
-) ([]bytes.Buffer, error) {
-	var err error
-
-	var buff bytes.Buffer
-	buffSlice := make([]bytes.Buffer, 0)
+	buffSlice bulkBuffer,
+) error {
 	for _, tx := range transactions {
-		isMBOfTxInDB := mbsHashInDB[tx.MBHash]
-		meta, serializedData, errPrepareTx := prepareSerializedDataForATransaction(tx, selfShardID, isMBOfTxInDB)
-		if errPrepareTx != nil {
+		err := putTransaction(tx, selfShardID, buffSlice)
+		if err != nil {
 			log.Warn("error preparing transaction for indexing", "tx hash", tx.Hash, "error", err)
-			return nil, errPrepareTx
+			return err
 		}
+	}
 
-		// append a newline for each element
-		serializedData = append(serializedData, "\n"...)
+	return nil
+}
 
-		buffLenWithCurrentTx := buff.Len() + len(meta) + len(serializedData)
-		if buffLenWithCurrentTx > bulkSizeThreshold && buff.Len() != 0 {
-			buffSlice = append(buffSlice, buff)
-			buff = bytes.Buffer{}
-		}
+func getTransactionsHashes(txs []*data.Transaction) []string {
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
 
-		buff.Grow(len(meta) + len(serializedData))
-		_, err = buff.Write(meta)
-		if err != nil {
-			log.Warn("elastic search: serialize bulk tx, write meta", "error", err.Error())
-			return nil, err
+	return hashes
+}
 
+func serializeAccounts(accounts map[string]*data.AccountInfo, buffSlice bulkBuffer) error {
+	for address, acc := range accounts {
+		serializedData, err := json.Marshal(acc)
+		if err != nil {
+			log.Warn("cannot prepare serializes account info", "address", address, "error", err)
+			return err
 		}
-		_, err = buff.Write(serializedData)
+
+		err = buffSlice.PutIndex(address, serializedData)
 		if err != nil {
-			log.Warn("elastic search: serialize bulk tx, write serialized tx", "error", err.Error())
-			return nil, err
+			log.Warn("elastic search: serialize bulk accounts, write", "error", err.Error())
+			return err
 		}
 	}
 
-	// check if the last buffer contains data
-	if buff.Len() != 0 {
-		buffSlice = append(buffSlice, buff)
-	}
+	return nil
+}
 
-	return buffSlice, nil
+// computeAccountHistoryID returns the ID of the balance history entry of an account in a block
+func computeAccountHistoryID(address string, shardID uint32, blockNonce uint64) string {
+	return fmt.Sprintf("%s_%d_%d", address, shardID, blockNonce)
 }
 
-func serializeAccounts(accounts map[string]*data.AccountInfo) ([]bytes.Buffer, error) {
-	var err error
+// computeAddressActivityID returns the identifier of an address activity entry, which is the same each time the
+// transfer is indexed
+// computeRoundID returns the ID of the document of a round of a shard
+func computeRoundID(shardID uint32, round uint64) string {
+	return fmt.Sprintf("%d_%d", shardID, round)
+}
 
-	var buff bytes.Buffer
-	buffSlice := make([]bytes.Buffer, 0)
-	for address, acc := range accounts {
-		meta, serializedData, errPrepareAcc := prepareSerializedAccountInfo(address, acc)
-		if len(meta) == 0 {
-			log.Warn("cannot prepare serializes account info", "error", errPrepareAcc)
-			return nil, err
-		}
+func computeAddressActivityID(activity *data.AddressActivity) string {
+	return fmt.Sprintf("%s_%s_%s_%d", activity.Address, activity.TxHash, activity.Direction, activity.Index)
+}
 
-		// append a newline for each element
-		serializedData = append(serializedData, "\n"...)
+// computeBalanceChange returns the difference between the provided balance and the previous one. A missing previous
+// balance counts as zero
+func computeBalanceChange(balance string, previousBalance *big.Int) *big.Int {
+	balanceChange := stringValueToBigInt(balance)
+	if previousBalance != nil {
+		balanceChange.Sub(balanceChange, previousBalance)
+	}
 
-		buffLenWithCurrentAcc := buff.Len() + len(meta) + len(serializedData)
-		if buffLenWithCurrentAcc > bulkSizeThreshold && buff.Len() != 0 {
-			buffSlice = append(buffSlice, buff)
-			buff = bytes.Buffer{}
-		}
+	return balanceChange
+}
 
-		buff.Grow(len(meta) + len(serializedData))
-		_, err = buff.Write(meta)
-		if err != nil {
-			log.Warn("elastic search: serialize bulk accounts, write meta", "error", err.Error())
-			return nil, err
+// getDecodedAccountsBalances returns the balances of the accounts found by a multi get request, keyed by address
+func getDecodedAccountsBalances(response objectsMap) map[string]*big.Int {
+	balances := make(map[string]*big.Int)
+	interfaceSlice, ok := response["docs"].([]interface{})
+	if !ok {
+		return balances
+	}
+
+	for _, element := range interfaceSlice {
+		obj, ok := element.(objectsMap)
+		if !ok {
+			continue
 		}
-		_, err = buff.Write(serializedData)
-		if err != nil {
-			log.Warn("elastic search: serialize bulk accounts, write serialized account", "error", err.Error())
-			return nil, err
+
+		found, _ := obj["found"].(bool)
+		address, _ := obj["_id"].(string)
+		source, _ := obj["_source"].(objectsMap)
+		if !found || source == nil {
+			continue
 		}
-	}
 
-	// check if the last buffer contains data
-	if buff.Len() != 0 {
-		buffSlice = append(buffSlice, buff)
+		balance, _ := source["balance"].(string)
+		balances[address] = stringValueToBigInt(balance)
 	}
 
-	return buffSlice, nil
+	return balances
 }
 
-func serializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory) ([]bytes.Buffer, error) {
-	var err error
-
-	var buff bytes.Buffer
-	buffSlice := make([]bytes.Buffer, 0)
+func serializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, buffSlice bulkBuffer) error {
 	for address, acc := range accounts {
-		meta, serializedData, errPrepareAcc := prepareSerializedAccountBalanceHistory(address, acc)
-		if errPrepareAcc != nil {
-			log.Warn("cannot prepare serializes account balance history", "error", err)
-			return nil, err
+		serializedData, err := json.Marshal(acc)
+		if err != nil {
+			log.Warn("cannot prepare serializes account balance history", "address", address, "error", err)
+			return err
 		}
 
-		// append a newline for each element
-		serializedData = append(serializedData, "\n"...)
-
-		buffLenWithCurrentAccountHistory := buff.Len() + len(meta) + len(serializedData)
-		if buffLenWithCurrentAccountHistory > bulkSizeThreshold && buff.Len() != 0 {
-			buffSlice = append(buffSlice, buff)
-			buff = bytes.Buffer{}
+		err = buffSlice.PutIndex(address, serializedData)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk accounts history, write", "error", err.Error())
+			return err
 		}
+	}
 
-		buff.Grow(len(meta) + len(serializedData))
-		_, err = buff.Write(meta)
+	return nil
+}
+
+func serializeAddressActivities(activities []*data.AddressActivity, buffSlice bulkBuffer) error {
+	for _, activity := range activities {
+		serializedData, err := json.Marshal(activity)
 		if err != nil {
-			log.Warn("elastic search: serialize bulk accounts history, write meta", "error", err.Error())
-			return nil, err
+			log.Warn("cannot prepare serializes address activity", "address", activity.Address, "error", err)
+			return err
 		}
-		_, err = buff.Write(serializedData)
+
+		err = buffSlice.PutIndex(computeAddressActivityID(activity), serializedData)
 		if err != nil {
-			log.Warn("elastic search: serialize bulk accounts history, write serialized account history", "error", err.Error())
-			return nil, err
+			log.Warn("elastic search: serialize bulk address activity, write", "error", err.Error())
+			return err
 		}
 	}
 
-	// check if the last buffer contains data
-	if buff.Len() != 0 {
-		buffSlice = append(buffSlice, buff)
+	return nil
+}
+
+func serializeTokens(updates map[string]*tokenUpdate, buffSlice bulkBuffer) error {
+	for identifier, update := range updates {
+		err := putTokenUpdate(identifier, update, buffSlice)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk tokens", "identifier", identifier, "error", err.Error())
+			return err
+		}
 	}
 
-	return buffSlice, nil
+	return nil
 }
 
-func prepareSerializedDataForATransaction(
+// setMiniblockFieldsScript sets the missing fields of a miniblock and the block hashes of the shard that indexes it
+const setMiniblockFieldsScript = `for (entry in params.doc.entrySet()) { ` +
+	`if (!ctx._source.containsKey(entry.getKey())) { ctx._source[entry.getKey()] = entry.getValue(); } } ` +
+	`for (field in params.blockHashFields) { ctx._source[field] = params.doc[field]; }`
+
+// destinationTxScript sets the fields of a cross shard transaction known by the destination shard. The smart contract
+// results already added by later blocks are kept and, as the status, the gas used and the fee of the transaction were
+// computed with them, these fields are not overwritten unless the transaction failed at destination
+const destinationTxScript = `List scResults = new ArrayList(params.scResults); boolean appended = false; ` +
+	`if (ctx._source.scResults != null) { for (existing in ctx._source.scResults) { boolean found = false; ` +
+	`for (scr in params.scResults) { if (scr.hash == existing.hash) { found = true; break; } } ` +
+	`if (!found) { scResults.add(existing); appended = true; } } } ` +
+	`ctx._source.scResults = scResults; ` +
+	`if (!appended || params.status == 'fail') { ` +
+	`ctx._source.status = params.status; ctx._source.gasUsed = params.gasUsed; ctx._source.fee = params.fee; ` +
+	`ctx._source.feeNum = params.feeNum; ctx._source.feeScaled = params.feeScaled; } ` +
+	`ctx._source.miniBlockHash = params.miniBlockHash; ctx._source.log = params.log; ` +
+	`ctx._source.timestamp = params.timestamp; ` +
+	`ctx._source.destinationBlockHash = params.destinationBlockHash; ` +
+	`ctx._source.destinationTimestamp = params.destinationTimestamp;`
+
+func putTransaction(
 	tx *data.Transaction,
 	selfShardID uint32,
-	_ bool,
-) ([]byte, []byte, error) {
-	metaData := []byte(fmt.Sprintf(`{"update":{"_id":"%s", "_type": "_doc"}}%s`, tx.Hash, "\n"))
-
+	buffSlice bulkBuffer,
+) error {
 	marshaledTx, err := json.Marshal(tx)
 	if err != nil {
 		log.Debug("indexer: marshal",
 			"error", "could not serialize transaction, will skip indexing",
 			"tx hash", tx.Hash)
-		return nil, nil, err
+		return err
 	}
 
 	if isIntraShardOrInvalid(tx, selfShardID) {
 		// if transaction is intra-shard, use basic insert as data can be re-written at forks
-		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s", "_type" : "%s" } }%s`, tx.Hash, "_doc", "\n"))
-		log.Trace("indexer tx is intra shard or invalid tx", "meta", string(meta), "marshaledTx", string(marshaledTx))
+		log.Trace("indexer tx is intra shard or invalid tx", "hash", tx.Hash, "marshaledTx", string(marshaledTx))
 
-		return meta, marshaledTx, nil
+		return buffSlice.PutIndex(tx.Hash, marshaledTx)
 	}
 
 	if !isCrossShardDstMe(tx, selfShardID) {
-		// if transaction is cross-shard and current shard ID is source, use upsert without updating anything
-		serializedData :=
-			[]byte(fmt.Sprintf(`{"script":{"source":"return"},"upsert":%s}`,
-				string(marshaledTx)))
-		log.Trace("indexer tx is on sender shard", "metaData", string(metaData), "serializedData", string(serializedData))
+		// if transaction is cross-shard and current shard ID is source, use upsert updating only the source block
+		log.Trace("indexer tx is on sender shard", "hash", tx.Hash, "marshaledTx", string(marshaledTx))
+
+		script := []byte(fmt.Sprintf(`{"source":"`+
+			`ctx._source.sourceBlockHash = params.sourceBlockHash;`+
+			`ctx._source.sourceTimestamp = params.sourceTimestamp;`+
+			`","lang": "painless","params":`+
+			`{"sourceBlockHash": "%s", "sourceTimestamp": %d}}`,
+			tx.SourceBlockHash, tx.SourceTimestamp))
 
-		return metaData, serializedData, nil
+		return buffSlice.PutUpsert(tx.Hash, script, marshaledTx)
 	}
 
 	// if transaction is cross-shard and current shard ID is destination, use upsert with updating fields
@@ -356,14 +412,14 @@ func prepareSerializedDataForATransaction(
 		log.Debug("indexer: marshal",
 			"error", "could not serialize transaction log, will skip indexing",
 			"tx hash", tx.Hash)
-		return nil, nil, err
+		return err
 	}
 	scResults, err := json.Marshal(tx.SmartContractResults)
 	if err != nil {
 		log.Debug("indexer: marshal",
 			"error", "could not serialize smart contract results, will skip indexing",
 			"tx hash", tx.Hash)
-		return nil, nil, err
+		return err
 	}
 
 	marshaledTimestamp, err := json.Marshal(tx.Timestamp)
@@ -371,24 +427,26 @@ func prepareSerializedDataForATransaction(
 		log.Debug("indexer: marshal",
 			"error", "could not serialize timestamp, will skip indexing",
 			"tx hash", tx.Hash)
-		return nil, nil, err
+		return err
 	}
 
-	serializedData := []byte(fmt.Sprintf(`{"script":{"source":"`+
-		`ctx._source.status = params.status;`+
-		`ctx._source.miniBlockHash = params.miniBlockHash;`+
-		`ctx._source.log = params.log;`+
-		`ctx._source.scResults = params.scResults;`+
-		`ctx._source.timestamp = params.timestamp;`+
-		`ctx._source.gasUsed = params.gasUsed;`+
-		`ctx._source.fee = params.fee;`+
-		`","lang": "painless","params":`+
-		`{"status": "%s", "miniBlockHash": "%s", "log": %s, "scResults": %s, "timestamp": %s, "gasUsed": %d, "fee": "%s"}},"upsert":%s}`,
-		tx.Status, tx.MBHash, string(marshaledLog), string(scResults), string(marshaledTimestamp), tx.GasUsed, tx.Fee, string(marshaledTx)))
+	marshaledFeeScaled, err := json.Marshal(tx.FeeScaled)
+	if err != nil {
+		log.Debug("indexer: marshal",
+			"error", "could not serialize scaled fee, will skip indexing",
+			"tx hash", tx.Hash)
+		return err
+	}
 
-	log.Trace("indexer tx is on destination shard", "metaData", string(metaData), "serializedData", string(serializedData))
+	script := []byte(fmt.Sprintf(`{"source":"`+destinationTxScript+`","lang": "painless","params":`+
+		`{"status": "%s", "miniBlockHash": "%s", "log": %s, "scResults": %s, "timestamp": %s, "gasUsed": %d, "fee": "%s", `+
+		`"feeNum": %s, "feeScaled": %s, "destinationBlockHash": "%s", "destinationTimestamp": %d}}`,
+		tx.Status, tx.MBHash, string(marshaledLog), string(scResults), string(marshaledTimestamp), tx.GasUsed, tx.Fee,
+		strconv.FormatFloat(tx.FeeNum, 'g', -1, 64), string(marshaledFeeScaled), tx.DestinationBlockHash, tx.DestinationTimestamp))
 
-	return metaData, serializedData, nil
+	log.Trace("indexer tx is on destination shard", "hash", tx.Hash, "script", string(script))
+
+	return buffSlice.PutUpsert(tx.Hash, script, marshaledTx)
 }
 
 func isRelayedTx(tx *data.Transaction) bool {
@@ -399,32 +457,6 @@ func isESDTNFTTransfer(tx *data.Transaction) bool {
 	return strings.HasPrefix(string(tx.Data), core.BuiltInFunctionESDTNFTTransfer) && len(tx.SmartContractResults) > 0
 }
 
-func prepareSerializedAccountInfo(address string, account *data.AccountInfo) ([]byte, []byte, error) {
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, address, "\n"))
-	serializedData, err := json.Marshal(account)
-	if err != nil {
-		log.Debug("indexer: marshal",
-			"error", "could not serialize account, will skip indexing",
-			"address", address)
-		return nil, nil, err
-	}
-
-	return meta, serializedData, nil
-}
-
-func prepareSerializedAccountBalanceHistory(address string, account *data.AccountBalanceHistory) ([]byte, []byte, error) {
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, address, "\n"))
-	serializedData, err := json.Marshal(account)
-	if err != nil {
-		log.Debug("indexer: marshal",
-			"error", "could not serialize account history entry, will skip indexing",
-			"address", address)
-		return nil, nil, err
-	}
-
-	return meta, serializedData, nil
-}
-
 func isCrossShardDstMe(tx *data.Transaction, selfShardID uint32) bool {
 	return tx.SenderShard != tx.ReceiverShard && tx.ReceiverShard == selfShardID
 }
@@ -452,6 +484,69 @@ func getDecodedResponseMultiGet(response objectsMap) map[string]bool {
 	return founded
 }
 
+// getDecodedTransactionsMultiGet returns the transactions found by a multi get request, keyed by their raw hashes
+func getDecodedTransactionsMultiGet(response objectsMap) map[string]*data.Transaction {
+	transactions := make(map[string]*data.Transaction)
+	interfaceSlice, ok := response["docs"].([]interface{})
+	if !ok {
+		return transactions
+	}
+
+	for _, element := range interfaceSlice {
+		obj, ok := element.(objectsMap)
+		if !ok {
+			continue
+		}
+
+		found, _ := obj["found"].(bool)
+		encodedHash, _ := obj["_id"].(string)
+		txHash, err := hex.DecodeString(encodedHash)
+		if !found || err != nil {
+			continue
+		}
+
+		serializedTx, err := json.Marshal(obj["_source"])
+		if err != nil {
+			continue
+		}
+
+		tx := &data.Transaction{}
+		err = json.Unmarshal(serializedTx, tx)
+		if err != nil {
+			log.Debug("indexer: cannot decode transaction", "hash", encodedHash, "error", err)
+			continue
+		}
+
+		tx.Hash = encodedHash
+		transactions[string(txHash)] = tx
+	}
+
+	return transactions
+}
+
+// getDecodedFirstSearchHit decodes the source of the first document found by a search request in the provided value
+func getDecodedFirstSearchHit(response objectsMap, value interface{}) (bool, error) {
+	hits, ok := response["hits"].(objectsMap)
+	if !ok {
+		return false, nil
+	}
+	hitsSlice, ok := hits["hits"].([]interface{})
+	if !ok || len(hitsSlice) == 0 {
+		return false, nil
+	}
+	hit, ok := hitsSlice[0].(objectsMap)
+	if !ok {
+		return false, nil
+	}
+
+	serializedSource, err := json.Marshal(hit["_source"])
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(serializedSource, value)
+}
+
 // GetElasticTemplatesAndPolicies will return elastic templates and policies
 func GetElasticTemplatesAndPolicies(useKibana bool) (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
 	indexTemplates := make(map[string]*bytes.Buffer)
@@ -481,6 +576,14 @@ func getTemplatesKibana() map[string]*bytes.Buffer {
 	indexTemplates[validatorsIndex] = withKibana.Validators.ToBuffer()
 	indexTemplates[accountsIndex] = withKibana.Accounts.ToBuffer()
 	indexTemplates[accountsHistoryIndex] = withKibana.AccountsHistory.ToBuffer()
+	indexTemplates[tokensIndex] = withKibana.Tokens.ToBuffer()
+	indexTemplates[nftsIndex] = withKibana.NFTs.ToBuffer()
+	indexTemplates[scDeploysIndex] = withKibana.ScDeploys.ToBuffer()
+	indexTemplates[ratingHistoryIndex] = withKibana.ValidatorsRatingHistory.ToBuffer()
+	indexTemplates[validatorsStatisticsIndex] = withKibana.ValidatorsStatistics.ToBuffer()
+	indexTemplates[delegatorsIndex] = withKibana.Delegators.ToBuffer()
+	indexTemplates[providersIndex] = withKibana.Providers.ToBuffer()
+	indexTemplates[addressActivityIndex] = withKibana.AddressActivity.ToBuffer()
 
 	return indexTemplates
 }
@@ -497,6 +600,14 @@ func getTemplatesNoKibana() map[string]*bytes.Buffer {
 	indexTemplates[validatorsIndex] = noKibana.Validators.ToBuffer()
 	indexTemplates[accountsIndex] = noKibana.Accounts.ToBuffer()
 	indexTemplates[accountsHistoryIndex] = noKibana.AccountsHistory.ToBuffer()
+	indexTemplates[tokensIndex] = noKibana.Tokens.ToBuffer()
+	indexTemplates[nftsIndex] = noKibana.NFTs.ToBuffer()
+	indexTemplates[scDeploysIndex] = noKibana.ScDeploys.ToBuffer()
+	indexTemplates[ratingHistoryIndex] = noKibana.ValidatorsRatingHistory.ToBuffer()
+	indexTemplates[validatorsStatisticsIndex] = noKibana.ValidatorsStatistics.ToBuffer()
+	indexTemplates[delegatorsIndex] = noKibana.Delegators.ToBuffer()
+	indexTemplates[providersIndex] = noKibana.Providers.ToBuffer()
+	indexTemplates[addressActivityIndex] = noKibana.AddressActivity.ToBuffer()
 
 	return indexTemplates
 }
@@ -1,7 +1,6 @@
 package indexer
 
 import (
-	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -83,6 +82,7 @@ func TestGetMoveBalanceTransaction(t *testing.T) {
 		Fee:                  "100",
 		ReceiverUserName:     []byte("rcv"),
 		SenderUserName:       []byte("snd"),
+		Operation:            operationTransfer,
 	}
 
 	dbTx := cp.buildTransaction(tx, txHash, mbHash, mb, header, status)
@@ -121,6 +121,7 @@ func TestGetTransactionByType_SC(t *testing.T) {
 		Value:        "<nil>",
 		RelayedValue: "<nil>",
 		CallType:     "1",
+		Operation:    operationTransfer,
 	}
 
 	require.Equal(t, expectedTx, scRes)
@@ -142,32 +143,73 @@ func TestGetTransactionByType_RewardTx(t *testing.T) {
 
 	resultTx := cp.buildRewardTransaction(rwdTx, txHash, mbHash, mb, header, status)
 	expectedTx := &data.Transaction{
-		Hash:     hex.EncodeToString(txHash),
-		MBHash:   hex.EncodeToString(mbHash),
-		Round:    round,
-		Receiver: hex.EncodeToString(rcvAddr),
-		Status:   status,
-		Value:    "<nil>",
-		Sender:   fmt.Sprintf("%d", core.MetachainShardId),
-		Data:     make([]byte, 0),
+		Hash:      hex.EncodeToString(txHash),
+		MBHash:    hex.EncodeToString(mbHash),
+		Round:     round,
+		Receiver:  hex.EncodeToString(rcvAddr),
+		Status:    status,
+		Value:     "<nil>",
+		Sender:    fmt.Sprintf("%d", core.MetachainShardId),
+		Data:      make([]byte, 0),
+		Operation: operationReward,
 	}
 
 	require.Equal(t, expectedTx, resultTx)
 }
 
-func TestPrepareBufferMiniblocks(t *testing.T) {
-	var buff bytes.Buffer
+func TestSerializeTransactions_OperationDependsOnShards(t *testing.T) {
+	t.Parallel()
+
+	txs := []*data.Transaction{
+		{Hash: "intra", SenderShard: 0, ReceiverShard: 0},
+		{Hash: "src", SenderShard: 0, ReceiverShard: 1},
+		{Hash: "dst", SenderShard: 1, ReceiverShard: 0, Status: "success", MBHash: "mb", DestinationBlockHash: "block"},
+	}
+
+	buffSlice := data.NewBufferSlice()
+	err := serializeTransactions(txs, 0, buffSlice)
+	require.Nil(t, err)
+
+	require.Len(t, buffSlice.Buffers(), 1)
+	serialized := buffSlice.Buffers()[0].String()
+	require.Contains(t, serialized, `{"index":{"_id":"intra"}}`)
+	require.Contains(t, serialized, `{"update":{"_id":"src"}}`+"\n"+`{ "script" : {"source":"ctx._source.sourceBlockHash = params.sourceBlockHash;`)
+	require.Contains(t, serialized, `{"update":{"_id":"dst"}}`+"\n"+`{ "script" : {"source":"`+destinationTxScript+`"`)
+	require.Contains(t, serialized, `"params":{"status": "success", "miniBlockHash": "mb"`)
+	require.Contains(t, serialized, `"destinationBlockHash": "block"`)
+}
+
+func TestSerializeBulkMiniBlocks_UpsertsTheMiniblocks(t *testing.T) {
+	t.Parallel()
 
-	meta := []byte("test1")
-	serializedData := []byte("test2")
+	miniblocks := []*data.Miniblock{
+		{Hash: "cross", SenderShardID: 0, ReceiverShardID: 1, SenderBlockHash: "block"},
+		{Hash: "intra", SenderShardID: 0, ReceiverShardID: 0, SenderBlockHash: "block", ReceiverBlockHash: "block"},
+	}
+	getAlreadyIndexedItems := func(hashes []string, index string) (map[string]bool, error) {
+		return map[string]bool{"cross": true}, nil
+	}
 
-	buff = prepareBufferMiniblocks(buff, meta, serializedData)
+	buffSlice := data.NewBufferSlice()
+	existsInDb := serializeBulkMiniBlocks(0, miniblocks, getAlreadyIndexedItems, buffSlice)
+	require.Equal(t, map[string]bool{"cross": true}, existsInDb)
+
+	serialized := buffSlice.Buffers()[0].String()
+	require.Contains(t, serialized, `{"update":{"_id":"cross"}}`+"\n"+
+		`{ "script" : {"lang":"painless","params":{"blockHashFields":["senderBlockHash"],"doc":{"senderShard":0,"receiverShard":1,`)
+	require.Contains(t, serialized, `"source":"`+setMiniblockFieldsScript+`"}, "upsert" : {"senderShard":0,"receiverShard":1,"senderBlockHash":"block"`)
+	require.Contains(t, serialized, `"params":{"blockHashFields":["senderBlockHash","receiverBlockHash"]`)
+}
 
-	var expectedBuff bytes.Buffer
-	serializedData = append(serializedData, "\n"...)
-	expectedBuff.Grow(len(meta) + len(serializedData))
-	_, _ = expectedBuff.Write(meta)
-	_, _ = expectedBuff.Write(serializedData)
+func TestSerializeAccounts(t *testing.T) {
+	t.Parallel()
+
+	accounts := map[string]*data.AccountInfo{
+		"addr": {Nonce: 1, Balance: "10"},
+	}
 
-	require.Equal(t, expectedBuff, buff)
+	buffSlice := data.NewBufferSlice()
+	err := serializeAccounts(accounts, buffSlice)
+	require.Nil(t, err)
+	require.Equal(t, `{"index":{"_id":"addr"}}`+"\n"+`{"nonce":1,"balance":"10","balanceNum":0}`+"\n", buffSlice.Buffers()[0].String())
 }
@@ -7,14 +7,22 @@ const (
 	headerContentType = "Content-Type"
 	kibanaPluginPath  = "_plugin/kibana/api"
 
-	blockIndex           = "blocks"
-	miniblocksIndex      = "miniblocks"
-	txIndex              = "transactions"
-	validatorsIndex      = "validators"
-	roundIndex           = "rounds"
-	ratingIndex          = "rating"
-	accountsIndex        = "accounts"
-	accountsHistoryIndex = "accountshistory"
+	blockIndex                = "blocks"
+	miniblocksIndex           = "miniblocks"
+	txIndex                   = "transactions"
+	validatorsIndex           = "validators"
+	roundIndex                = "rounds"
+	ratingIndex               = "rating"
+	accountsIndex             = "accounts"
+	accountsHistoryIndex      = "accountshistory"
+	tokensIndex               = "tokens"
+	nftsIndex                 = "nfts"
+	scDeploysIndex            = "scdeploys"
+	ratingHistoryIndex        = "validatorsratinghistory"
+	validatorsStatisticsIndex = "validatorsstatistics"
+	delegatorsIndex           = "delegators"
+	providersIndex            = "providers"
+	addressActivityIndex      = "addressactivity"
 
 	txPolicy              = "transactions_policy"
 	blockPolicy           = "blocks_policy"
@@ -23,6 +31,20 @@ const (
 	roundPolicy           = "rounds_policy"
 	ratingPolicy          = "rating_policy"
 	accountsHistoryPolicy = "accountshistory_policy"
+)
+
+const (
+	// RefreshPolicyTrue forces a refresh of the affected shards after every write
+	RefreshPolicyTrue = "true"
+	// RefreshPolicyWaitFor waits for the next scheduled refresh before returning
+	RefreshPolicyWaitFor = "wait_for"
+	// RefreshPolicyFalse does not trigger nor wait for any refresh
+	RefreshPolicyFalse = "false"
 
-	bulkSizeThreshold = 800000 // 0.8MB
+	// SyncRefreshMode is the preset used while the node is syncing: no refresh is requested at all
+	SyncRefreshMode = "sync"
+	// TipRefreshMode is the preset used while the node is at the chain tip: writes wait for the next refresh
+	TipRefreshMode = "tip"
 )
+
+var indexes = []string{txIndex, blockIndex, miniblocksIndex, ratingIndex, roundIndex, validatorsIndex, accountsIndex, accountsHistoryIndex, tokensIndex, nftsIndex, scDeploysIndex, ratingHistoryIndex, validatorsStatisticsIndex, delegatorsIndex, providersIndex, addressActivityIndex}
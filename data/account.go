@@ -12,16 +12,31 @@ type AccountInfo struct {
 	Nonce           uint64  `json:"nonce,omitempty"`
 	Balance         string  `json:"balance"`
 	BalanceNum      float64 `json:"balanceNum"`
+	BalanceScaled   *int64  `json:"balanceScaled,omitempty"`
 	TokenIdentifier string  `json:"token,omitempty"`
 	Properties      string  `json:"properties,omitempty"`
+	UserName        string  `json:"userName,omitempty"`
+	IsSmartContract bool    `json:"isSmartContract,omitempty"`
+	CodeHash        string  `json:"codeHash,omitempty"`
+	IsUpgradeable   bool    `json:"isUpgradeable,omitempty"`
+	IsReadable      bool    `json:"isReadable,omitempty"`
+	IsPayable       bool    `json:"isPayable,omitempty"`
+	OwnerAddress    string  `json:"ownerAddress,omitempty"`
+	DeveloperReward string  `json:"developerReward,omitempty"`
 	IsSender        bool    `json:"-"`
+	TxHash          string  `json:"-"`
 }
 
-// AccountBalanceHistory represents an entry in the user accounts balances history
+// AccountBalanceHistory represents an entry in the user accounts balances history. There is at most one entry for
+// an account in a block, which holds the balance at the end of the block and the change made by the block
 type AccountBalanceHistory struct {
 	Address         string        `json:"address"`
 	Timestamp       time.Duration `json:"timestamp"`
 	Balance         string        `json:"balance"`
+	BalanceChange   string        `json:"balanceChange"`
+	TxHash          string        `json:"txHash,omitempty"`
+	ShardID         uint32        `json:"shardId"`
+	BlockNonce      uint64        `json:"blockNonce"`
 	TokenIdentifier string        `json:"token,omitempty"`
 	IsSender        bool          `json:"isSender,omitempty"`
 }
@@ -30,6 +45,7 @@ type AccountBalanceHistory struct {
 type Account struct {
 	UserAccount coreData.UserAccountHandler
 	IsSender    bool
+	TxHash      string
 }
 
 // AccountESDT is a structure that is needed for ESDT accounts
@@ -39,9 +55,11 @@ type AccountESDT struct {
 	IsSender        bool
 }
 
-// AlteredAccount is a structure that holds information about an altered account
+// AlteredAccount is a structure that holds information about an altered account. The transaction hash is the one
+// of a transaction of the block that altered the account
 type AlteredAccount struct {
 	IsSender        bool
 	IsESDTOperation bool
 	TokenIdentifier string
+	TxHash          string
 }
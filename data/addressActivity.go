@@ -0,0 +1,18 @@
+package data
+
+import "time"
+
+// AddressActivity is an entry of the address activity index. It links an address to a transaction or to a smart
+// contract result in which the address acts as sender, receiver or relayer, with the transferred token and value
+type AddressActivity struct {
+	Address        string        `json:"address"`
+	TxHash         string        `json:"txHash"`
+	OriginalTxHash string        `json:"originalTxHash,omitempty"`
+	Direction      string        `json:"direction"`
+	Token          string        `json:"token,omitempty"`
+	Value          string        `json:"value"`
+	Timestamp      time.Duration `json:"timestamp"`
+	ShardID        uint32        `json:"shardId"`
+	BlockNonce     uint64        `json:"blockNonce"`
+	Index          int           `json:"-"`
+}
@@ -8,26 +8,86 @@ import (
 //  to be saved for a block. It has all the default fields
 //  plus some extra information for ease of search and filter
 type Block struct {
-	Nonce                 uint64        `json:"nonce"`
-	Round                 uint64        `json:"round"`
-	Epoch                 uint32        `json:"epoch"`
-	Hash                  string        `json:"-"`
-	MiniBlocksHashes      []string      `json:"miniBlocksHashes"`
-	NotarizedBlocksHashes []string      `json:"notarizedBlocksHashes"`
-	Proposer              uint64        `json:"proposer"`
-	Validators            []uint64      `json:"validators"`
-	PubKeyBitmap          string        `json:"pubKeyBitmap"`
-	Size                  int64         `json:"size"`
-	SizeTxs               int64         `json:"sizeTxs"`
-	Timestamp             time.Duration `json:"timestamp"`
-	StateRootHash         string        `json:"stateRootHash"`
-	PrevHash              string        `json:"prevHash"`
-	ShardID               uint32        `json:"shardId"`
-	TxCount               uint32        `json:"txCount"`
-	AccumulatedFees       string        `json:"accumulatedFees"`
-	DeveloperFees         string        `json:"developerFees"`
-	EpochStartBlock       bool          `json:"epochStartBlock"`
-	SearchOrder           uint64        `json:"searchOrder"`
+	Nonce                 uint64               `json:"nonce"`
+	Round                 uint64               `json:"round"`
+	Epoch                 uint32               `json:"epoch"`
+	Hash                  string               `json:"-"`
+	MiniBlocksHashes      []string             `json:"miniBlocksHashes"`
+	NotarizedBlocksHashes []string             `json:"notarizedBlocksHashes"`
+	Proposer              uint64               `json:"proposer"`
+	Validators            []uint64             `json:"validators"`
+	ProposerBlsKey        string               `json:"proposerBlsKey,omitempty"`
+	ValidatorsBlsKeys     []string             `json:"validatorsBlsKeys,omitempty"`
+	PubKeyBitmap          string               `json:"pubKeyBitmap"`
+	Size                  int64                `json:"size"`
+	SizeTxs               int64                `json:"sizeTxs"`
+	Timestamp             time.Duration        `json:"timestamp"`
+	StateRootHash         string               `json:"stateRootHash"`
+	PrevHash              string               `json:"prevHash"`
+	ShardID               uint32               `json:"shardId"`
+	TxCount               uint32               `json:"txCount"`
+	AccumulatedFees       string               `json:"accumulatedFees"`
+	AccumulatedFeesNum    float64              `json:"accumulatedFeesNum"`
+	AccumulatedFeesScaled *int64               `json:"accumulatedFeesScaled,omitempty"`
+	DeveloperFees         string               `json:"developerFees"`
+	DeveloperFeesNum      float64              `json:"developerFeesNum"`
+	DeveloperFeesScaled   *int64               `json:"developerFeesScaled,omitempty"`
+	EpochStartBlock       bool                 `json:"epochStartBlock"`
+	SearchOrder           uint64               `json:"searchOrder"`
+	NormalTxsCount        uint32               `json:"normalTxsCount"`
+	ScResultsCount        uint32               `json:"scResultsCount"`
+	RewardsCount          uint32               `json:"rewardsCount"`
+	InvalidTxsCount       uint32               `json:"invalidTxsCount"`
+	ReceiptsCount         uint32               `json:"receiptsCount"`
+	GasProvided           uint64               `json:"gasProvided"`
+	GasUsed               uint64               `json:"gasUsed"`
+	GasRefunded           uint64               `json:"gasRefunded"`
+	MaxGasLimit           uint64               `json:"maxGasLimit"`
+	MiniBlocksDetails     []*MiniBlocksDetails `json:"miniBlocksDetails,omitempty"`
+	NotarizedShardHeaders []*ShardHeaderInfo   `json:"notarizedShardHeaders,omitempty"`
+	EpochStartInfo        *EpochStartInfo      `json:"epochStartInfo,omitempty"`
+	LastFinalizedHeaders  []*FinalizedHeader   `json:"lastFinalizedHeaders,omitempty"`
+}
+
+// ShardHeaderInfo is a structure containing the information of a shard header notarized by a metachain block
+type ShardHeaderInfo struct {
+	ShardID         uint32 `json:"shardId"`
+	Nonce           uint64 `json:"nonce"`
+	Round           uint64 `json:"round"`
+	HeaderHash      string `json:"headerHash"`
+	TxCount         uint32 `json:"txCount"`
+	AccumulatedFees string `json:"accumulatedFees"`
+	DeveloperFees   string `json:"developerFees"`
+}
+
+// EpochStartInfo is a structure containing the economics data of an epoch start metachain block
+type EpochStartInfo struct {
+	TotalSupply                      string `json:"totalSupply"`
+	TotalToDistribute                string `json:"totalToDistribute"`
+	TotalNewlyMinted                 string `json:"totalNewlyMinted"`
+	RewardsPerBlock                  string `json:"rewardsPerBlock"`
+	RewardsForProtocolSustainability string `json:"rewardsForProtocolSustainability"`
+	NodePrice                        string `json:"nodePrice"`
+	PrevEpochStartRound              uint64 `json:"prevEpochStartRound"`
+	PrevEpochStartHash               string `json:"prevEpochStartHash"`
+}
+
+// FinalizedHeader is a structure containing the last finalized header of a shard at the start of an epoch
+type FinalizedHeader struct {
+	ShardID    uint32 `json:"shardId"`
+	Epoch      uint32 `json:"epoch"`
+	Nonce      uint64 `json:"nonce"`
+	Round      uint64 `json:"round"`
+	HeaderHash string `json:"headerHash"`
+	RootHash   string `json:"rootHash"`
+}
+
+// MiniBlocksDetails is a structure containing the details of a miniblock included in a block
+type MiniBlocksDetails struct {
+	Type            string `json:"type"`
+	SenderShardID   uint32 `json:"senderShard"`
+	ReceiverShardID uint32 `json:"receiverShard"`
+	TxsCount        int    `json:"txsCount"`
 }
 
 // Miniblock is a structure containing miniblock information
@@ -38,5 +98,9 @@ type Miniblock struct {
 	SenderBlockHash   string        `json:"senderBlockHash"`
 	ReceiverBlockHash string        `json:"receiverBlockHash"`
 	Type              string        `json:"type"`
+	TxHashes          []string      `json:"txHashes"`
 	Timestamp         time.Duration `json:"timestamp"`
+
+	NotarizedAtSourceInMetaNonce      uint64 `json:"notarizedAtSourceInMetaNonce,omitempty"`
+	NotarizedAtDestinationInMetaNonce uint64 `json:"notarizedAtDestinationInMetaNonce,omitempty"`
 }
@@ -2,27 +2,47 @@ package data
 
 import "bytes"
 
-// BulkSizeThreshold is the constant for the maximum size of one bulk request that is sent to the elaticsearch database
+// BulkSizeThreshold is the default maximum size of one bulk request that is sent to the elaticsearch database
 const BulkSizeThreshold = 838860 // 0.8MB
 
 // BufferSlice extend structure bytes.Buffer with new methods
 type bufferSlice struct {
 	buffSlice         []*bytes.Buffer
 	bulkSizeThreshold int
+	bulkMaxDocs       int
+	numDocsInCurrent  int
 	idx               int
 }
 
 // NewBufferSlice will create a new buffer
 func NewBufferSlice() *bufferSlice {
+	return NewBufferSliceWithLimits(BulkSizeThreshold, 0)
+}
+
+// NewBufferSliceWithLimits will create a new buffer. Every bulk request will hold at most maxBulkBytes bytes (the
+// default BulkSizeThreshold is used if the value is not positive) and at most maxBulkDocs operations (no limit if the
+// value is not positive)
+func NewBufferSliceWithLimits(maxBulkBytes int, maxBulkDocs int) *bufferSlice {
+	if maxBulkBytes <= 0 {
+		maxBulkBytes = BulkSizeThreshold
+	}
+
 	return &bufferSlice{
 		buffSlice:         make([]*bytes.Buffer, 0),
-		bulkSizeThreshold: BulkSizeThreshold,
+		bulkSizeThreshold: maxBulkBytes,
+		bulkMaxDocs:       maxBulkDocs,
 		idx:               0,
 	}
 }
 
 // PutData will put meta bytes and serializeData in buffer
 func (bs *bufferSlice) PutData(meta []byte, serializedData []byte) error {
+	return bs.putData(meta, append(serializedData, "\n"...))
+}
+
+// putData writes the provided lines in the current buffer, or in a new one if the limits of the current buffer would
+// be exceeded. The lines have to be already terminated
+func (bs *bufferSlice) putData(meta []byte, serializedData []byte) error {
 	if len(bs.buffSlice) == 0 {
 		bs.buffSlice = append(bs.buffSlice, &bytes.Buffer{})
 	}
@@ -33,10 +53,9 @@ func (bs *bufferSlice) PutData(meta []byte, serializedData []byte) error {
 		currentBuff = &bytes.Buffer{}
 		bs.buffSlice = append(bs.buffSlice, currentBuff)
 		bs.idx++
+		bs.numDocsInCurrent = 0
 	}
 
-	serializedData = append(serializedData, "\n"...)
-
 	currentBuff.Grow(len(meta) + len(serializedData))
 	_, err := currentBuff.Write(meta)
 	if err != nil {
@@ -47,6 +66,8 @@ func (bs *bufferSlice) PutData(meta []byte, serializedData []byte) error {
 		return err
 	}
 
+	bs.numDocsInCurrent++
+
 	return nil
 }
 
@@ -57,8 +78,15 @@ func (bs *bufferSlice) Buffers() []*bytes.Buffer {
 
 func (bs *bufferSlice) aNewElementIsNeeded(meta []byte, serializedData []byte) bool {
 	currentBuff := bs.buffSlice[bs.idx]
+	if currentBuff.Len() == 0 {
+		return false
+	}
+
+	if bs.bulkMaxDocs > 0 && bs.numDocsInCurrent >= bs.bulkMaxDocs {
+		return true
+	}
 
 	buffLenWithCurrentAcc := currentBuff.Len() + len(meta) + len(serializedData)
 
-	return buffLenWithCurrentAcc > bs.bulkSizeThreshold && currentBuff.Len() != 0
+	return buffLenWithCurrentAcc > bs.bulkSizeThreshold
 }
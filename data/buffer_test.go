@@ -2,6 +2,8 @@ package data
 
 import (
 	"crypto/rand"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,67 @@ func TestBufferSlice_PutDataShouldWork(t *testing.T) {
 	require.Equal(t, 2, len(returnedBuffSlice))
 }
 
+func TestBufferSlice_MaxBulkBytes(t *testing.T) {
+	buffSlice := NewBufferSliceWithLimits(100, 0)
+
+	for i := 0; i < 3; i++ {
+		err := buffSlice.PutData(generateRandomBytes(20), generateRandomBytes(20))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, 2, len(buffSlice.Buffers()))
+}
+
+func TestBufferSlice_MaxBulkDocs(t *testing.T) {
+	buffSlice := NewBufferSliceWithLimits(0, 2)
+
+	for i := 0; i < 5; i++ {
+		err := buffSlice.PutIndex("id", []byte(`{}`))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, 3, len(buffSlice.Buffers()))
+}
+
+func TestBufferSlice_TypedOperations(t *testing.T) {
+	buffSlice := NewBufferSlice()
+
+	require.Nil(t, buffSlice.PutIndex("h1", []byte(`{"a":1}`)))
+	require.Nil(t, buffSlice.PutUpdateDoc("h2", []byte(`{"a":2}`)))
+	require.Nil(t, buffSlice.PutUpdateScript("h3", []byte(`{"source":"ctx._source.a = 3"}`)))
+	require.Nil(t, buffSlice.PutUpsert("h4", []byte(`{"source":"return"}`), []byte(`{"a":4}`)))
+	require.Nil(t, buffSlice.PutDelete("h5"))
+
+	expected := `{"index":{"_id":"h1"}}` + "\n" + `{"a":1}` + "\n" +
+		`{"update":{"_id":"h2"}}` + "\n" + `{ "doc" : {"a":2} }` + "\n" +
+		`{"update":{"_id":"h3"}}` + "\n" + `{ "script" : {"source":"ctx._source.a = 3"} }` + "\n" +
+		`{"update":{"_id":"h4"}}` + "\n" + `{ "script" : {"source":"return"}, "upsert" : {"a":4} }` + "\n" +
+		`{"delete":{"_id":"h5"}}` + "\n"
+
+	require.Equal(t, 1, len(buffSlice.Buffers()))
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+}
+
+func TestBufferSlice_IDsShouldBeEscaped(t *testing.T) {
+	buffSlice := NewBufferSlice()
+
+	id := "id\" } }\n{ \"delete\" : { \"_id\" : \"other"
+	require.Nil(t, buffSlice.PutIndex(id, []byte(`{"a":1}`)))
+	require.Nil(t, buffSlice.PutDelete(id))
+
+	lines := strings.Split(strings.TrimSuffix(buffSlice.Buffers()[0].String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	action := make(map[string]map[string]string)
+	require.Nil(t, json.Unmarshal([]byte(lines[0]), &action))
+	require.Equal(t, id, action["index"]["_id"])
+	require.Equal(t, `{"a":1}`, lines[1])
+
+	action = make(map[string]map[string]string)
+	require.Nil(t, json.Unmarshal([]byte(lines[2]), &action))
+	require.Equal(t, id, action["delete"]["_id"])
+}
+
 func generateRandomBytes(n int) []byte {
 	b := make([]byte, n)
 	_, _ = rand.Read(b)
@@ -0,0 +1,57 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	indexAction  = "index"
+	updateAction = "update"
+	deleteAction = "delete"
+)
+
+// PutIndex adds an operation that creates or overwrites the document with the provided id
+func (bs *bufferSlice) PutIndex(id string, doc []byte) error {
+	return bs.putOperation(indexAction, id, doc)
+}
+
+// PutUpdateDoc adds an operation that merges the provided partial document into the existing document
+func (bs *bufferSlice) PutUpdateDoc(id string, partialDoc []byte) error {
+	return bs.putOperation(updateAction, id, []byte(fmt.Sprintf(`{ "doc" : %s }`, partialDoc)))
+}
+
+// PutUpdateScript adds an operation that runs the provided script on the existing document. The script has to be
+// a serialized script object (source, lang and params)
+func (bs *bufferSlice) PutUpdateScript(id string, script []byte) error {
+	return bs.putOperation(updateAction, id, []byte(fmt.Sprintf(`{ "script" : %s }`, script)))
+}
+
+// PutUpsert adds an operation that runs the provided script if the document exists or indexes the provided
+// document otherwise
+func (bs *bufferSlice) PutUpsert(id string, script []byte, doc []byte) error {
+	return bs.putOperation(updateAction, id, []byte(fmt.Sprintf(`{ "script" : %s, "upsert" : %s }`, script, doc)))
+}
+
+// PutDelete adds an operation that removes the document with the provided id
+func (bs *bufferSlice) PutDelete(id string) error {
+	return bs.putOperation(deleteAction, id, nil)
+}
+
+// putOperation adds the action line of an operation, followed by its source line if there is one. The action line
+// is serialized with the encoder, so the id can not break the request whatever characters it holds
+func (bs *bufferSlice) putOperation(action string, id string, source []byte) error {
+	meta, err := json.Marshal(map[string]interface{}{
+		action: map[string]string{"_id": id},
+	})
+	if err != nil {
+		return err
+	}
+	meta = append(meta, "\n"...)
+
+	if len(source) > 0 {
+		source = append(source, "\n"...)
+	}
+
+	return bs.putData(meta, source)
+}
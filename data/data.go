@@ -17,25 +17,46 @@ type KibanaResponse struct {
 
 // ValidatorRatingInfo is a structure containing validator rating information
 type ValidatorRatingInfo struct {
-	PublicKey string  `json:"publicKey"`
-	Rating    float32 `json:"rating"`
-}
-
-// TODO this will be removed in the next PR
-// ValidatorsRatingInfo is a structure containing validators information
-type ValidatorsRatingInfo struct {
-	ValidatorsInfos []*ValidatorRatingInfo `json:"validatorsRating"`
+	PublicKey string        `json:"publicKey"`
+	ShardID   uint32        `json:"shardId"`
+	Epoch     uint32        `json:"epoch"`
+	Rating    float32       `json:"rating"`
+	Timestamp time.Duration `json:"timestamp"`
 }
 
 // RoundInfo is a structure containing block signers and shard id
 type RoundInfo struct {
 	Index            uint64        `json:"round"`
 	SignersIndexes   []uint64      `json:"signersIndexes"`
+	SignersBlsKeys   []string      `json:"signersBlsKeys,omitempty"`
 	BlockWasProposed bool          `json:"blockWasProposed"`
 	ShardId          uint32        `json:"shardId"`
+	Epoch            uint32        `json:"epoch"`
 	Timestamp        time.Duration `json:"timestamp"`
 }
 
+// ValidatorStatistics is a structure containing the number of blocks proposed, missed and signed by a validator
+// in an epoch. The last counted round and the contributions of the recent rounds make the counters idempotent and
+// revertible
+type ValidatorStatistics struct {
+	BlsKey       string                        `json:"blsKey"`
+	ShardID      uint32                        `json:"shardId"`
+	Epoch        uint32                        `json:"epoch"`
+	Proposed     uint64                        `json:"proposed"`
+	Missed       uint64                        `json:"missed"`
+	Signed       uint64                        `json:"signed"`
+	LastRound    uint64                        `json:"lastRound"`
+	RecentRounds map[string]*RoundContribution `json:"recentRounds,omitempty"`
+}
+
+// RoundContribution is a structure containing the blocks proposed, missed and signed by a validator in a round
+type RoundContribution struct {
+	Round    uint64 `json:"round"`
+	Proposed uint64 `json:"proposed"`
+	Missed   uint64 `json:"missed"`
+	Signed   uint64 `json:"signed"`
+}
+
 // EpochInfo holds the information about epoch
 type EpochInfo struct {
 	AccumulatedFees string `json:"accumulatedFees"`
@@ -0,0 +1,42 @@
+package data
+
+import "time"
+
+// Delegator is a structure containing the stake of a delegator in a delegation contract. The applied transactions
+// hashes are the delegations, the claims and the redelegations already added to the amounts
+type Delegator struct {
+	Delegator       string           `json:"delegator"`
+	Contract        string           `json:"contract"`
+	ActiveStake     string           `json:"activeStake"`
+	ClaimedRewards  string           `json:"claimedRewards"`
+	Unstaked        []*UnstakedEntry `json:"unstaked"`
+	AppliedTxHashes []string         `json:"appliedTxHashes"`
+	Timestamp       time.Duration    `json:"timestamp"`
+}
+
+// UnstakedEntry is a structure containing an amount undelegated from a delegation contract. The withdraw transaction
+// hash is set once the amount is withdrawn
+type UnstakedEntry struct {
+	TxHash         string        `json:"txHash"`
+	Value          string        `json:"value"`
+	Timestamp      time.Duration `json:"timestamp"`
+	WithdrawTxHash string        `json:"withdrawTxHash,omitempty"`
+}
+
+// DelegationProvider is a structure containing the configuration of a delegation contract created by the delegation
+// manager system smart contract. The updates hold the previous values of the service fee and of the maximum cap
+type DelegationProvider struct {
+	Contract   string            `json:"contract"`
+	Owner      string            `json:"owner"`
+	ServiceFee uint64            `json:"serviceFee"`
+	MaxCap     string            `json:"maxCap"`
+	Timestamp  time.Duration     `json:"timestamp"`
+	Updates    []*ProviderUpdate `json:"updates,omitempty"`
+}
+
+// ProviderUpdate is a structure containing the value of a provider field before it was changed by a transaction
+type ProviderUpdate struct {
+	TxHash        string      `json:"txHash"`
+	Field         string      `json:"field"`
+	PreviousValue interface{} `json:"previousValue"`
+}
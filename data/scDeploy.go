@@ -0,0 +1,22 @@
+package data
+
+import "time"
+
+// ScDeployInfo is a structure containing the information about a deployed smart contract
+type ScDeployInfo struct {
+	TxHash       string           `json:"deployTxHash"`
+	Deployer     string           `json:"deployer"`
+	Timestamp    time.Duration    `json:"timestamp"`
+	CodeHash     string           `json:"codeHash"`
+	CodeMetadata string           `json:"codeMetadata"`
+	Upgrades     []*ScUpgradeInfo `json:"upgrades"`
+}
+
+// ScUpgradeInfo is a structure containing the information about a smart contract upgrade
+type ScUpgradeInfo struct {
+	TxHash       string        `json:"upgradeTxHash"`
+	Upgrader     string        `json:"upgrader"`
+	Timestamp    time.Duration `json:"timestamp"`
+	CodeHash     string        `json:"codeHash"`
+	CodeMetadata string        `json:"codeMetadata"`
+}
@@ -0,0 +1,44 @@
+package data
+
+import "time"
+
+// Token is a structure containing the metadata of a non fungible or semi fungible token. The supply is known only for
+// the tokens whose creation was indexed and the applied transactions hashes are the ones already added to the supply
+type Token struct {
+	Identifier      string        `json:"identifier"`
+	Token           string        `json:"token"`
+	Nonce           uint64        `json:"nonce"`
+	Name            string        `json:"name"`
+	Creator         string        `json:"creator"`
+	Royalties       uint32        `json:"royalties"`
+	Hash            []byte        `json:"hash"`
+	URIs            [][]byte      `json:"uris"`
+	Attributes      []byte        `json:"attributes"`
+	Tags            []string      `json:"tags,omitempty"`
+	MetaData        string        `json:"metadata,omitempty"`
+	Supply          string        `json:"supply,omitempty"`
+	AppliedTxHashes []string      `json:"appliedTxHashes,omitempty"`
+	Timestamp       time.Duration `json:"timestamp"`
+}
+
+// TokenInfo is a structure containing the registry information of an ESDT token issued by the system ESDT smart
+// contract
+type TokenInfo struct {
+	Identifier string        `json:"identifier"`
+	Token      string        `json:"token"`
+	Name       string        `json:"name"`
+	Ticker     string        `json:"ticker"`
+	Type       string        `json:"type"`
+	Owner      string        `json:"owner"`
+	Decimals   uint64        `json:"decimals"`
+	Paused     bool          `json:"paused"`
+	Roles      []*TokenRoles `json:"roles"`
+	Frozen     []string      `json:"frozen"`
+	Timestamp  time.Duration `json:"timestamp"`
+}
+
+// TokenRoles is a structure containing the addresses that hold a special role of an ESDT token
+type TokenRoles struct {
+	Role      string   `json:"role"`
+	Addresses []string `json:"addresses"`
+}
@@ -13,30 +13,49 @@ const RefundGasMessage = "refundedGas"
 //  to be saved for a transaction. It has all the default fields
 //  plus some extra information for ease of search and filter
 type Transaction struct {
-	Hash                 string        `json:"-"`
-	MBHash               string        `json:"miniBlockHash"`
-	BlockHash            string        `json:"-"`
-	Nonce                uint64        `json:"nonce"`
-	Round                uint64        `json:"round"`
-	Value                string        `json:"value"`
-	Receiver             string        `json:"receiver"`
-	Sender               string        `json:"sender"`
-	ReceiverShard        uint32        `json:"receiverShard"`
-	SenderShard          uint32        `json:"senderShard"`
-	GasPrice             uint64        `json:"gasPrice"`
-	GasLimit             uint64        `json:"gasLimit"`
-	GasUsed              uint64        `json:"gasUsed"`
-	Fee                  string        `json:"fee"`
-	Data                 []byte        `json:"data"`
-	Signature            string        `json:"signature"`
-	Timestamp            time.Duration `json:"timestamp"`
-	Status               string        `json:"status"`
-	SearchOrder          uint32        `json:"searchOrder"`
-	SmartContractResults []ScResult    `json:"scResults,omitempty"`
-	SenderUserName       []byte        `json:"senderUsername,omitempty"`
-	ReceiverUserName     []byte        `json:"receiverUsername,omitempty"`
-	Log                  TxLog         `json:"-"`
-	ReceiverAddressBytes []byte        `json:"-"`
+	Hash                              string        `json:"-"`
+	MBHash                            string        `json:"miniBlockHash"`
+	BlockHash                         string        `json:"-"`
+	Nonce                             uint64        `json:"nonce"`
+	Round                             uint64        `json:"round"`
+	Value                             string        `json:"value"`
+	ValueNum                          float64       `json:"valueNum"`
+	ValueScaled                       *int64        `json:"valueScaled,omitempty"`
+	Receiver                          string        `json:"receiver"`
+	Sender                            string        `json:"sender"`
+	ReceiverShard                     uint32        `json:"receiverShard"`
+	SenderShard                       uint32        `json:"senderShard"`
+	GasPrice                          uint64        `json:"gasPrice"`
+	GasLimit                          uint64        `json:"gasLimit"`
+	GasUsed                           uint64        `json:"gasUsed"`
+	Fee                               string        `json:"fee"`
+	FeeNum                            float64       `json:"feeNum"`
+	FeeScaled                         *int64        `json:"feeScaled,omitempty"`
+	Data                              []byte        `json:"data"`
+	Signature                         string        `json:"signature"`
+	Timestamp                         time.Duration `json:"timestamp"`
+	Status                            string        `json:"status"`
+	SearchOrder                       uint64        `json:"searchOrder"`
+	SourceBlockHash                   string        `json:"sourceBlockHash,omitempty"`
+	SourceTimestamp                   time.Duration `json:"sourceTimestamp,omitempty"`
+	DestinationBlockHash              string        `json:"destinationBlockHash,omitempty"`
+	DestinationTimestamp              time.Duration `json:"destinationTimestamp,omitempty"`
+	NotarizedAtSourceInMetaNonce      uint64        `json:"notarizedAtSourceInMetaNonce,omitempty"`
+	NotarizedAtDestinationInMetaNonce uint64        `json:"notarizedAtDestinationInMetaNonce,omitempty"`
+	Operation                         string        `json:"operation"`
+	Function                          string        `json:"function,omitempty"`
+	Tokens                            []string      `json:"tokens,omitempty"`
+	ESDTValues                        []string      `json:"esdtValues,omitempty"`
+	Receivers                         []string      `json:"receivers,omitempty"`
+	InnerSender                       string        `json:"innerSender,omitempty"`
+	InnerReceiver                     string        `json:"innerReceiver,omitempty"`
+	InnerFunction                     string        `json:"innerFunction,omitempty"`
+	InnerValue                        string        `json:"innerValue,omitempty"`
+	SmartContractResults              []ScResult    `json:"scResults,omitempty"`
+	SenderUserName                    []byte        `json:"senderUsername,omitempty"`
+	ReceiverUserName                  []byte        `json:"receiverUsername,omitempty"`
+	Log                               TxLog         `json:"-"`
+	ReceiverAddressBytes              []byte        `json:"-"`
 }
 
 // GetGasLimit will return transaction gas limit
@@ -81,22 +100,27 @@ type Receipt struct {
 
 // ScResult is a structure containing all the fields that need to be saved for a smart contract result
 type ScResult struct {
-	Hash           string `json:"hash"`
-	Nonce          uint64 `json:"nonce"`
-	GasLimit       uint64 `json:"gasLimit"`
-	GasPrice       uint64 `json:"gasPrice"`
-	Value          string `json:"value"`
-	Sender         string `json:"sender"`
-	Receiver       string `json:"receiver"`
-	RelayerAddr    string `json:"relayerAddr,omitempty"`
-	RelayedValue   string `json:"relayedValue,omitempty"`
-	Code           string `json:"code,omitempty"`
-	Data           []byte `json:"data,omitempty"`
-	PreTxHash      string `json:"prevTxHash"`
-	OriginalTxHash string `json:"originalTxHash"`
-	CallType       string `json:"callType"`
-	CodeMetadata   []byte `json:"codeMetaData,omitempty"`
-	ReturnMessage  string `json:"returnMessage,omitempty"`
+	Hash           string   `json:"hash"`
+	Nonce          uint64   `json:"nonce"`
+	GasLimit       uint64   `json:"gasLimit"`
+	GasPrice       uint64   `json:"gasPrice"`
+	Value          string   `json:"value"`
+	Sender         string   `json:"sender"`
+	Receiver       string   `json:"receiver"`
+	RelayerAddr    string   `json:"relayerAddr,omitempty"`
+	RelayedValue   string   `json:"relayedValue,omitempty"`
+	Code           string   `json:"code,omitempty"`
+	Data           []byte   `json:"data,omitempty"`
+	PreTxHash      string   `json:"prevTxHash"`
+	OriginalTxHash string   `json:"originalTxHash"`
+	CallType       string   `json:"callType"`
+	CodeMetadata   []byte   `json:"codeMetaData,omitempty"`
+	ReturnMessage  string   `json:"returnMessage,omitempty"`
+	Operation      string   `json:"operation"`
+	Function       string   `json:"function,omitempty"`
+	Tokens         []string `json:"tokens,omitempty"`
+	ESDTValues     []string `json:"esdtValues,omitempty"`
+	Receivers      []string `json:"receivers,omitempty"`
 }
 
 // TxLog holds all the data needed for a log structure
@@ -28,6 +28,7 @@ const (
 type dataDispatcher struct {
 	backOffTime   time.Duration
 	chanWorkItems chan workItems.WorkItemHandler
+	ctx           context.Context
 	cancelFunc    func()
 }
 
@@ -40,16 +41,14 @@ func NewDataDispatcher(cacheSize int) (*dataDispatcher, error) {
 	dd := &dataDispatcher{
 		chanWorkItems: make(chan workItems.WorkItemHandler, cacheSize),
 	}
+	dd.ctx, dd.cancelFunc = context.WithCancel(context.Background())
 
 	return dd, nil
 }
 
 // StartIndexData will start index data in database
 func (d *dataDispatcher) StartIndexData() {
-	var ctx context.Context
-	ctx, d.cancelFunc = context.WithCancel(context.Background())
-
-	go d.startWorker(ctx)
+	go d.startWorker(d.ctx)
 }
 
 func (d *dataDispatcher) startWorker(ctx context.Context) {
@@ -73,14 +72,19 @@ func (d *dataDispatcher) Close() error {
 	return nil
 }
 
-// Add will add a new item in queue
+// Add will add a new item in queue. The items added after the dispatcher was closed are dropped, as the queue is not
+// read anymore
 func (d *dataDispatcher) Add(item workItems.WorkItemHandler) {
 	if check.IfNil(item) {
 		log.Warn("dataDispatcher.Add nil item: will do nothing")
 		return
 	}
 
-	d.chanWorkItems <- item
+	select {
+	case d.chanWorkItems <- item:
+	case <-d.ctx.Done():
+		log.Debug("dataDispatcher.Add: the dispatcher is closed, will drop the item")
+	}
 }
 
 func (d *dataDispatcher) doWork(wi workItems.WorkItemHandler) {
@@ -128,3 +128,26 @@ func TestDataDispatcher_AddWithErrorShouldRetryTheReprocessing(t *testing.T) {
 	err = dispatcher.Close()
 	require.NoError(t, err)
 }
+
+func TestDataDispatcher_AddAfterCloseShouldNotBlock(t *testing.T) {
+	t.Parallel()
+
+	dispatcher, err := NewDataDispatcher(0)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+
+	chanDone := make(chan struct{})
+	go func() {
+		dispatcher.Add(workItems.NewItemRounds(&mock.ElasticProcessorStub{}, []*data.RoundInfo{}))
+		close(chanDone)
+	}()
+
+	select {
+	case <-chanDone:
+	case <-time.After(time.Second):
+		require.Fail(t, "dispatcher.Add should not block after close")
+	}
+}
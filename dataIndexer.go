@@ -1,6 +1,9 @@
 package indexer
 
 import (
+	"context"
+	"time"
+
 	"github.com/ElrondNetwork/elastic-indexer-go/data"
 	"github.com/ElrondNetwork/elastic-indexer-go/workItems"
 	"github.com/ElrondNetwork/elrond-go-core/core"
@@ -15,6 +18,7 @@ type dataIndexer struct {
 	dispatcher       DispatcherHandler
 	elasticProcessor ElasticProcessor
 	marshalizer      marshal.Marshalizer
+	cancelFunc       func()
 }
 
 // NewDataIndexer will create a new data indexer
@@ -31,9 +35,40 @@ func NewDataIndexer(arguments ArgDataIndexer) (*dataIndexer, error) {
 		marshalizer:      arguments.Marshalizer,
 	}
 
+	if arguments.BatchFlushPeriod > 0 {
+		var ctx context.Context
+		ctx, dataIndexerObj.cancelFunc = context.WithCancel(context.Background())
+		go dataIndexerObj.flushBatchPeriodically(ctx, arguments.BatchFlushPeriod)
+	}
+
 	return dataIndexerObj, nil
 }
 
+// flushBatchPeriodically will add in the queue the items that flush the blocks batch once it got too old. This is
+// needed because otherwise a batch would wait for the next block in order to be sent
+func (di *dataIndexer) flushBatchPeriodically(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("dataIndexer's flush batch go routine is stopping...")
+			return
+		case <-ticker.C:
+			// the ticker and the context can be ready at the same time, so the context is checked again to not add
+			// the item in the queue of a closed dispatcher
+			select {
+			case <-ctx.Done():
+				log.Debug("dataIndexer's flush batch go routine is stopping...")
+				return
+			default:
+				di.dispatcher.Add(workItems.NewItemFlushBatch(di.elasticProcessor))
+			}
+		}
+	}
+}
+
 func checkIndexerArgs(arguments ArgDataIndexer) error {
 	if check.IfNil(arguments.DataDispatcher) {
 		return ErrNilDataDispatcher
@@ -61,12 +96,22 @@ func (di *dataIndexer) SaveBlock(args *indexer.ArgsSaveBlockData) {
 	di.dispatcher.Add(wi)
 }
 
-// Close will stop goroutine that index data in database
+// Close will stop goroutine that index data in database and will send the blocks that wait in the batch
 func (di *dataIndexer) Close() error {
-	return di.dispatcher.Close()
+	if di.cancelFunc != nil {
+		di.cancelFunc()
+	}
+
+	err := di.dispatcher.Close()
+	if err != nil {
+		return err
+	}
+
+	return di.elasticProcessor.FlushBatch(true)
 }
 
-// RevertIndexedBlock will remove from database block and miniblocks
+// RevertIndexedBlock will remove from database the block, its miniblocks and the rounds of the abandoned branch and will
+// revert the changes made by the block
 func (di *dataIndexer) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) {
 	wi := workItems.NewItemRemoveBlock(
 		di.elasticProcessor,
@@ -74,6 +119,9 @@ func (di *dataIndexer) RevertIndexedBlock(header coreData.HeaderHandler, body co
 		header,
 	)
 	di.dispatcher.Add(wi)
+
+	wi = workItems.NewItemRemoveRounds(di.elasticProcessor, header)
+	di.dispatcher.Add(wi)
 }
 
 // SaveRoundsInfo will save data about a slice of rounds in elasticsearch
@@ -127,6 +175,26 @@ func (di *dataIndexer) SaveAccounts(timestamp uint64, accounts []coreData.UserAc
 	di.dispatcher.Add(wi)
 }
 
+// WaitForSavedItems will block until all the items added before the call were saved in the database, including the
+// blocks that wait in the batch, or until the provided context is done
+func (di *dataIndexer) WaitForSavedItems(ctx context.Context) error {
+	chDone := make(chan struct{})
+	di.dispatcher.Add(workItems.NewItemSyncPoint(di.elasticProcessor, chDone))
+
+	select {
+	case <-chDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NotifyWhenSaved will call the provided handler once all the items added before the call were saved in the database.
+// Unlike WaitForSavedItems, it does not block and it does not force the blocks batch to be sent
+func (di *dataIndexer) NotifyWhenSaved(handler func()) {
+	di.dispatcher.Add(workItems.NewItemSavedNotifier(di.elasticProcessor, handler))
+}
+
 // IsNilIndexer will return a bool value that signals if the indexer's implementation is a NilIndexer
 func (di *dataIndexer) IsNilIndexer() bool {
 	return di.isNilIndexer
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/ElrondNetwork/elrond-go-core/core"
 	"github.com/ElrondNetwork/elrond-go-core/hashing"
@@ -15,6 +16,7 @@ type ArgDataIndexer struct {
 	UseKibana        bool
 	DataDispatcher   DispatcherHandler
 	ElasticProcessor ElasticProcessor
+	BatchFlushPeriod time.Duration
 }
 
 // ArgElasticProcessor is struct that is used to store all components that are needed to an elastic indexer
@@ -33,4 +35,11 @@ type ArgElasticProcessor struct {
 	TransactionFeeCalculator FeesProcessorHandler
 	IsInImportDBMode         bool
 	ShardCoordinator         Coordinator
+	RefreshPolicies          map[string]string
+	BlocksBatchSize          int
+	BlocksBatchMaxBytes      int
+	BlocksBatchMaxAge        time.Duration
+	BulkMaxBytes             int
+	BulkMaxDocs              int
+	UseScaledNumericValues   bool
 }
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
@@ -158,6 +159,35 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 	require.True(t, called)
 }
 
+func TestDataIndexer_WaitForSavedItems(t *testing.T) {
+	flushed := false
+	arguments := NewDataIndexerArguments()
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		FlushBatchCalled: func(force bool) error {
+			flushed = force
+			return nil
+		},
+	}
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) {
+			_ = item.Save()
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.WaitForSavedItems(context.Background())
+	require.Nil(t, err)
+	require.True(t, flushed)
+
+	arguments.DataDispatcher = &mock.DispatcherMock{}
+	ei, _ = NewDataIndexer(arguments)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ei.WaitForSavedItems(ctx)
+	require.Equal(t, context.Canceled, err)
+}
+
 func TestDataIndexer(t *testing.T) {
 	t.Skip("this is not a short test")
 
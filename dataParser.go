@@ -1,9 +1,11 @@
 package indexer
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strconv"
 	"time"
 
@@ -11,20 +13,26 @@ import (
 	"github.com/ElrondNetwork/elrond-go-core/core"
 	coreData "github.com/ElrondNetwork/elrond-go-core/data"
 	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/data/receipt"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
 	"github.com/ElrondNetwork/elrond-go-core/hashing"
 	"github.com/ElrondNetwork/elrond-go-core/marshal"
 )
 
 type dataParser struct {
-	hasher      hashing.Hasher
-	marshalizer marshal.Marshalizer
+	hasher        hashing.Hasher
+	marshalizer   marshal.Marshalizer
+	numericValues numericValuesConverter
 }
 
 func (dp *dataParser) getSerializedElasticBlockAndHeaderHash(
 	header coreData.HeaderHandler,
 	signersIndexes []uint64,
+	signersKeys []string,
 	body *block.Body,
 	notarizedHeadersHashes []string,
+	pool *indexer.Pool,
 	sizeTxs int,
 ) ([]byte, []byte, error) {
 	headerBytes, err := dp.marshalizer.Marshal(header)
@@ -39,6 +47,7 @@ func (dp *dataParser) getSerializedElasticBlockAndHeaderHash(
 	blockSizeInBytes := len(headerBytes) + len(bodyBytes)
 
 	miniblocksHashes := make([]string, 0)
+	miniblocksDetails := make([]*data.MiniBlocksDetails, 0)
 	for _, miniblock := range body.MiniBlocks {
 		mbHash, errComputeHash := core.CalculateHash(dp.marshalizer, dp.hasher, miniblock)
 		if errComputeHash != nil {
@@ -49,12 +58,22 @@ func (dp *dataParser) getSerializedElasticBlockAndHeaderHash(
 
 		encodedMbHash := hex.EncodeToString(mbHash)
 		miniblocksHashes = append(miniblocksHashes, encodedMbHash)
+		miniblocksDetails = append(miniblocksDetails, &data.MiniBlocksDetails{
+			Type:            miniblock.Type.String(),
+			SenderShardID:   miniblock.SenderShardID,
+			ReceiverShardID: miniblock.ReceiverShardID,
+			TxsCount:        len(miniblock.TxHashes),
+		})
 	}
 
 	leaderIndex := uint64(0)
 	if len(signersIndexes) > 0 {
 		leaderIndex = signersIndexes[0]
 	}
+	leaderKey := ""
+	if len(signersKeys) > 0 {
+		leaderKey = signersKeys[0]
+	}
 
 	headerHash := dp.hasher.Compute(string(headerBytes))
 	elasticBlock := data.Block{
@@ -67,6 +86,8 @@ func (dp *dataParser) getSerializedElasticBlockAndHeaderHash(
 		NotarizedBlocksHashes: notarizedHeadersHashes,
 		Proposer:              leaderIndex,
 		Validators:            signersIndexes,
+		ProposerBlsKey:        leaderKey,
+		ValidatorsBlsKeys:     signersKeys,
 		PubKeyBitmap:          hex.EncodeToString(header.GetPubKeysBitmap()),
 		Size:                  int64(blockSizeInBytes),
 		SizeTxs:               int64(sizeTxs),
@@ -75,7 +96,17 @@ func (dp *dataParser) getSerializedElasticBlockAndHeaderHash(
 		StateRootHash:         hex.EncodeToString(header.GetRootHash()),
 		PrevHash:              hex.EncodeToString(header.GetPrevHash()),
 		SearchOrder:           computeBlockSearchOrder(header),
+		MiniBlocksDetails:     miniblocksDetails,
+		AccumulatedFees:       bigIntToString(header.GetAccumulatedFees()),
+		AccumulatedFeesNum:    dp.numericValues.computeFloat(header.GetAccumulatedFees()),
+		AccumulatedFeesScaled: dp.numericValues.computeScaled(header.GetAccumulatedFees()),
+		DeveloperFees:         bigIntToString(header.GetDeveloperFees()),
+		DeveloperFeesNum:      dp.numericValues.computeFloat(header.GetDeveloperFees()),
+		DeveloperFeesScaled:   dp.numericValues.computeScaled(header.GetDeveloperFees()),
+		EpochStartBlock:       header.IsStartOfEpochBlock(),
 	}
+	setBlockTxsStatistics(&elasticBlock, pool)
+	setMetaBlockInfo(&elasticBlock, header)
 
 	serializedBlock, err := json.Marshal(elasticBlock)
 	if err != nil {
@@ -111,6 +142,7 @@ func (dp *dataParser) getMiniblocks(header coreData.HeaderHandler, body *block.B
 			SenderShardID:   miniblock.SenderShardID,
 			ReceiverShardID: miniblock.ReceiverShardID,
 			Type:            miniblock.Type.String(),
+			TxHashes:        encodeHashes(miniblock.TxHashes),
 		}
 
 		if mb.SenderShardID == header.GetShardID() {
@@ -129,20 +161,129 @@ func (dp *dataParser) getMiniblocks(header coreData.HeaderHandler, body *block.B
 	return miniblocks
 }
 
-func serializeRoundInfo(info data.RoundInfo) ([]byte, []byte) {
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%d_%d", "_type" : "%s" } }%s`,
-		info.ShardId, info.Index, "_doc", "\n"))
+// setMetaBlockInfo will set on the provided block the shard headers notarized by a metachain header and, for the
+// epoch start metachain headers, the economics data and the last finalized header of each shard
+func setMetaBlockInfo(elasticBlock *data.Block, header coreData.HeaderHandler) {
+	metaBlock, ok := header.(*block.MetaBlock)
+	if !ok {
+		return
+	}
 
-	serializedInfo, err := json.Marshal(info)
-	if err != nil {
-		log.Warn("indexer: could not serialize round info, will skip indexing this round info",
-			"error", err.Error())
-		return nil, nil
+	for _, shardData := range metaBlock.ShardInfo {
+		elasticBlock.NotarizedShardHeaders = append(elasticBlock.NotarizedShardHeaders, &data.ShardHeaderInfo{
+			ShardID:         shardData.ShardID,
+			Nonce:           shardData.Nonce,
+			Round:           shardData.Round,
+			HeaderHash:      hex.EncodeToString(shardData.HeaderHash),
+			TxCount:         shardData.TxCount,
+			AccumulatedFees: bigIntToString(shardData.AccumulatedFees),
+			DeveloperFees:   bigIntToString(shardData.DeveloperFees),
+		})
+	}
+
+	if !metaBlock.IsStartOfEpochBlock() {
+		return
+	}
+
+	economics := metaBlock.EpochStart.Economics
+	elasticBlock.EpochStartInfo = &data.EpochStartInfo{
+		TotalSupply:                      bigIntToString(economics.TotalSupply),
+		TotalToDistribute:                bigIntToString(economics.TotalToDistribute),
+		TotalNewlyMinted:                 bigIntToString(economics.TotalNewlyMinted),
+		RewardsPerBlock:                  bigIntToString(economics.RewardsPerBlock),
+		RewardsForProtocolSustainability: bigIntToString(economics.RewardsForProtocolSustainability),
+		NodePrice:                        bigIntToString(economics.NodePrice),
+		PrevEpochStartRound:              economics.PrevEpochStartRound,
+		PrevEpochStartHash:               hex.EncodeToString(economics.PrevEpochStartHash),
 	}
-	// append a newline foreach element in the bulk we create
-	serializedInfo = append(serializedInfo, "\n"...)
 
-	return serializedInfo, meta
+	for _, finalizedHeader := range metaBlock.EpochStart.LastFinalizedHeaders {
+		elasticBlock.LastFinalizedHeaders = append(elasticBlock.LastFinalizedHeaders, &data.FinalizedHeader{
+			ShardID:    finalizedHeader.ShardID,
+			Epoch:      finalizedHeader.Epoch,
+			Nonce:      finalizedHeader.Nonce,
+			Round:      finalizedHeader.Round,
+			HeaderHash: hex.EncodeToString(finalizedHeader.HeaderHash),
+			RootHash:   hex.EncodeToString(finalizedHeader.RootHash),
+		})
+	}
+}
+
+func bigIntToString(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+
+	return value.String()
+}
+
+// setBlockTxsStatistics will set on the provided block the number of transactions of each type and the gas totals
+// of the transactions from the pool of the block. The gas used is the gas provided by the normal and the invalid
+// transactions minus the gas refunded to their senders through receipts and smart contract results
+func setBlockTxsStatistics(elasticBlock *data.Block, pool *indexer.Pool) {
+	if pool == nil {
+		return
+	}
+
+	elasticBlock.NormalTxsCount = uint32(len(pool.Txs))
+	elasticBlock.ScResultsCount = uint32(len(pool.Scrs))
+	elasticBlock.RewardsCount = uint32(len(pool.Rewards))
+	elasticBlock.InvalidTxsCount = uint32(len(pool.Invalid))
+	elasticBlock.ReceiptsCount = uint32(len(pool.Receipts))
+
+	for _, txsMap := range []map[string]coreData.TransactionHandler{pool.Txs, pool.Invalid} {
+		for _, tx := range txsMap {
+			elasticBlock.GasProvided += tx.GetGasLimit()
+			if tx.GetGasLimit() > elasticBlock.MaxGasLimit {
+				elasticBlock.MaxGasLimit = tx.GetGasLimit()
+			}
+		}
+	}
+
+	for _, txHandler := range pool.Receipts {
+		rec, ok := txHandler.(*receipt.Receipt)
+		if !ok || string(rec.Data) != data.RefundGasMessage {
+			continue
+		}
+
+		elasticBlock.GasRefunded += computeRefundedGas(pool.Txs[string(rec.TxHash)], rec.Value)
+	}
+
+	for _, txHandler := range pool.Scrs {
+		scr, ok := txHandler.(*smartContractResult.SmartContractResult)
+		if !ok {
+			continue
+		}
+
+		tx, found := pool.Txs[string(scr.OriginalTxHash)]
+		isRefund := found && isDataOk(scr.Data) && scr.Nonce == tx.GetNonce()+1 &&
+			bytes.Equal(scr.PrevTxHash, scr.OriginalTxHash) && bytes.Equal(scr.RcvAddr, tx.GetSndAddr())
+		if isRefund {
+			elasticBlock.GasRefunded += computeRefundedGas(tx, scr.Value)
+		}
+	}
+
+	if elasticBlock.GasRefunded > elasticBlock.GasProvided {
+		elasticBlock.GasRefunded = elasticBlock.GasProvided
+	}
+	elasticBlock.GasUsed = elasticBlock.GasProvided - elasticBlock.GasRefunded
+}
+
+func computeRefundedGas(tx coreData.TransactionHandler, refundValue *big.Int) uint64 {
+	if tx == nil || tx.GetGasPrice() == 0 || refundValue == nil {
+		return 0
+	}
+
+	return big.NewInt(0).Div(refundValue, big.NewInt(0).SetUint64(tx.GetGasPrice())).Uint64()
+}
+
+func encodeHashes(hashes [][]byte) []string {
+	encodedHashes := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		encodedHashes = append(encodedHashes, hex.EncodeToString(hash))
+	}
+
+	return encodedHashes
 }
 
 func computeBlockSearchOrder(header coreData.HeaderHandler) uint64 {
@@ -159,6 +300,22 @@ func computeBlockSearchOrder(header coreData.HeaderHandler) uint64 {
 	return order
 }
 
+// computeTxSearchOrder builds the search order of a transaction from the shard of the block, from the position of the
+// miniblock in the block body and from the position of the transaction in the miniblock
+func computeTxSearchOrder(shardID uint32, mbIndex int, txIndex int) uint64 {
+	shardIdentifier := createShardIdentifier(shardID)
+	stringOrder := fmt.Sprintf("1%02d%04d%06d", shardIdentifier, mbIndex, txIndex)
+
+	order, err := strconv.ParseUint(stringOrder, 10, 64)
+	if err != nil {
+		log.Debug("elasticsearchDatabase.computeTxSearchOrder",
+			"could not set uint64 search order", err.Error())
+		return 0
+	}
+
+	return order
+}
+
 func createShardIdentifier(shardID uint32) uint32 {
 	shardIdentifier := shardID + 2
 	if shardID == core.MetachainShardId {
@@ -0,0 +1,151 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/data/receipt"
+	"github.com/ElrondNetwork/elrond-go-core/data/rewardTx"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataParser_GetSerializedElasticBlockStatistics(t *testing.T) {
+	t.Parallel()
+
+	dp := &dataParser{
+		hasher:      &mock.HasherMock{},
+		marshalizer: &mock.MarshalizerMock{},
+	}
+	body := &dataBlock.Body{
+		MiniBlocks: []*dataBlock.MiniBlock{
+			{TxHashes: [][]byte{[]byte("tx1"), []byte("tx2")}, Type: dataBlock.TxBlock, ReceiverShardID: 1},
+			{TxHashes: [][]byte{[]byte("invalid")}, Type: dataBlock.InvalidBlock},
+		},
+	}
+	pool := &indexer.Pool{
+		Txs: map[string]coreData.TransactionHandler{
+			"tx1": &transaction.Transaction{Nonce: 1, SndAddr: []byte("sender"), GasLimit: 1000, GasPrice: 10},
+			"tx2": &transaction.Transaction{Nonce: 2, SndAddr: []byte("sender"), GasLimit: 500, GasPrice: 10},
+		},
+		Invalid: map[string]coreData.TransactionHandler{
+			"invalid": &transaction.Transaction{GasLimit: 200, GasPrice: 10},
+		},
+		Scrs: map[string]coreData.TransactionHandler{
+			"refund": &smartContractResult.SmartContractResult{
+				Nonce:          2,
+				RcvAddr:        []byte("sender"),
+				Value:          big.NewInt(3000),
+				Data:           []byte("@6f6b"),
+				PrevTxHash:     []byte("tx1"),
+				OriginalTxHash: []byte("tx1"),
+			},
+		},
+		Rewards: map[string]coreData.TransactionHandler{
+			"reward": &rewardTx.RewardTx{},
+		},
+		Receipts: map[string]coreData.TransactionHandler{
+			"receipt": &receipt.Receipt{Value: big.NewInt(1000), Data: []byte(data.RefundGasMessage), TxHash: []byte("tx2")},
+		},
+	}
+
+	serializedBlock, _, err := dp.getSerializedElasticBlockAndHeaderHash(&dataBlock.Header{}, nil, nil, body, nil, pool, 0)
+	require.Nil(t, err)
+
+	block := &data.Block{}
+	err = json.Unmarshal(serializedBlock, block)
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), block.NormalTxsCount)
+	require.Equal(t, uint32(1), block.ScResultsCount)
+	require.Equal(t, uint32(1), block.RewardsCount)
+	require.Equal(t, uint32(1), block.InvalidTxsCount)
+	require.Equal(t, uint32(1), block.ReceiptsCount)
+	require.Equal(t, uint64(1700), block.GasProvided)
+	require.Equal(t, uint64(400), block.GasRefunded)
+	require.Equal(t, uint64(1300), block.GasUsed)
+	require.Equal(t, uint64(1000), block.MaxGasLimit)
+	require.Equal(t, []*data.MiniBlocksDetails{
+		{Type: dataBlock.TxBlock.String(), ReceiverShardID: 1, TxsCount: 2},
+		{Type: dataBlock.InvalidBlock.String(), TxsCount: 1},
+	}, block.MiniBlocksDetails)
+}
+
+func TestDataParser_GetMiniblocks(t *testing.T) {
+	t.Parallel()
+
+	dp := &dataParser{
+		hasher:      &mock.HasherMock{},
+		marshalizer: &mock.MarshalizerMock{},
+	}
+	body := &dataBlock.Body{
+		MiniBlocks: []*dataBlock.MiniBlock{
+			{TxHashes: [][]byte{[]byte("tx1")}, Type: dataBlock.TxBlock},
+		},
+	}
+
+	miniblocks := dp.getMiniblocks(&dataBlock.Header{}, body)
+	require.Len(t, miniblocks, 1)
+	require.Equal(t, []string{hex.EncodeToString([]byte("tx1"))}, miniblocks[0].TxHashes)
+}
+
+func TestDataParser_GetSerializedElasticBlockMetaBlockInfo(t *testing.T) {
+	t.Parallel()
+
+	dp := &dataParser{
+		hasher:      &mock.HasherMock{},
+		marshalizer: &mock.MarshalizerMock{},
+	}
+	metaBlock := &dataBlock.MetaBlock{
+		ShardInfo: []dataBlock.ShardData{
+			{ShardID: 1, Nonce: 10, Round: 11, HeaderHash: []byte("shard hash"), TxCount: 5, AccumulatedFees: big.NewInt(100)},
+		},
+		EpochStart: dataBlock.EpochStart{
+			LastFinalizedHeaders: []dataBlock.EpochStartShardData{
+				{ShardID: 1, Epoch: 2, Nonce: 9, Round: 10, HeaderHash: []byte("final hash"), RootHash: []byte("root")},
+			},
+			Economics: dataBlock.Economics{
+				TotalSupply:      big.NewInt(1000),
+				TotalNewlyMinted: big.NewInt(10),
+				RewardsPerBlock:  big.NewInt(1),
+				NodePrice:        big.NewInt(2500),
+			},
+		},
+	}
+
+	serializedBlock, _, err := dp.getSerializedElasticBlockAndHeaderHash(metaBlock, nil, nil, &dataBlock.Body{}, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+
+	block := &data.Block{}
+	err = json.Unmarshal(serializedBlock, block)
+	require.Nil(t, err)
+	require.True(t, block.EpochStartBlock)
+	require.Equal(t, []*data.ShardHeaderInfo{
+		{ShardID: 1, Nonce: 10, Round: 11, HeaderHash: hex.EncodeToString([]byte("shard hash")), TxCount: 5, AccumulatedFees: "100", DeveloperFees: "0"},
+	}, block.NotarizedShardHeaders)
+	require.Equal(t, &data.EpochStartInfo{
+		TotalSupply:                      "1000",
+		TotalToDistribute:                "0",
+		TotalNewlyMinted:                 "10",
+		RewardsPerBlock:                  "1",
+		RewardsForProtocolSustainability: "0",
+		NodePrice:                        "2500",
+	}, block.EpochStartInfo)
+	require.Equal(t, []*data.FinalizedHeader{
+		{ShardID: 1, Epoch: 2, Nonce: 9, Round: 10, HeaderHash: hex.EncodeToString([]byte("final hash")), RootHash: hex.EncodeToString([]byte("root"))},
+	}, block.LastFinalizedHeaders)
+
+	serializedBlock, _, err = dp.getSerializedElasticBlockAndHeaderHash(&dataBlock.Header{}, nil, nil, &dataBlock.Body{}, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+	block = &data.Block{}
+	_ = json.Unmarshal(serializedBlock, block)
+	require.Nil(t, block.EpochStartInfo)
+	require.Empty(t, block.NotarizedShardHeaders)
+}
@@ -0,0 +1,336 @@
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+)
+
+const (
+	createNewDelegationContractFunction = "createNewDelegationContract"
+	delegateFunction                    = "delegate"
+	unDelegateFunction                  = "unDelegate"
+	withdrawFunction                    = "withdraw"
+	claimRewardsFunction                = "claimRewards"
+	reDelegateRewardsFunction           = "reDelegateRewards"
+	changeServiceFeeFunction            = "changeServiceFee"
+	modifyTotalDelegationCapFunction    = "modifyTotalDelegationCap"
+
+	minArgsCreateDelegationContract = 2
+	minArgsUnDelegate               = 1
+	minArgsProviderUpdate           = 1
+
+	// addToAmountScript and revertAddToAmountScript record the hashes of the applied transactions, so an operation that
+	// is indexed or reverted again is not counted twice
+	addToAmountScript = `if (ctx._source.appliedTxHashes == null) { ctx._source.appliedTxHashes = new ArrayList(); } ` +
+		`if (ctx._source.appliedTxHashes.contains(params.txHash)) { return; } ` +
+		`ctx._source[params.field] = new BigInteger(ctx._source[params.field]).add(new BigInteger(params.value)).toString(); ` +
+		`ctx._source.appliedTxHashes.add(params.txHash);`
+	revertAddToAmountScript = `if (ctx._source.appliedTxHashes == null || !ctx._source.appliedTxHashes.contains(params.txHash)) { return; } ` +
+		`ctx._source[params.field] = new BigInteger(ctx._source[params.field]).subtract(new BigInteger(params.value)).toString(); ` +
+		`ctx._source.appliedTxHashes.remove(ctx._source.appliedTxHashes.indexOf(params.txHash));`
+	unDelegateScript = `for (entry in ctx._source.unstaked) { if (entry.txHash == params.entry.txHash) { return; } } ` +
+		`ctx._source.activeStake = new BigInteger(ctx._source.activeStake).subtract(new BigInteger(params.entry.value)).toString(); ` +
+		`ctx._source.unstaked.add(params.entry);`
+	revertUnDelegateScript = `for (int i = 0; i < ctx._source.unstaked.size(); i++) { ` +
+		`if (ctx._source.unstaked[i].txHash == params.txHash) { ` +
+		`ctx._source.activeStake = new BigInteger(ctx._source.activeStake).add(new BigInteger(ctx._source.unstaked[i].value)).toString(); ` +
+		`ctx._source.unstaked.remove(i); return; } }`
+	// withdrawScript marks as withdrawn the oldest unstaked entries covered by the amount returned to the delegator. The
+	// unbond period is not known by the indexer, so the entries are assumed to become withdrawable in the order in which
+	// they were undelegated. An entry larger than the amount left is not marked, so the entries are never over-marked
+	withdrawScript = `for (entry in ctx._source.unstaked) { if (entry.withdrawTxHash == params.txHash) { return; } } ` +
+		`BigInteger left = new BigInteger(params.value); ` +
+		`for (entry in ctx._source.unstaked) { if (entry.withdrawTxHash != null) { continue; } ` +
+		`BigInteger entryValue = new BigInteger(entry.value); if (entryValue.compareTo(left) > 0) { break; } ` +
+		`entry.withdrawTxHash = params.txHash; left = left.subtract(entryValue); }`
+	revertWithdrawScript = `for (entry in ctx._source.unstaked) { if (entry.withdrawTxHash == params.txHash) { entry.remove('withdrawTxHash'); } }`
+
+	// updateProviderScript sets a field of the provider and records its previous value, so the update can be reverted
+	updateProviderScript = `if (ctx._source.updates == null) { ctx._source.updates = new ArrayList(); } ` +
+		`for (update in ctx._source.updates) { if (update.txHash == params.txHash) { return; } } ` +
+		`ctx._source.updates.add(['txHash': params.txHash, 'field': params.field, 'previousValue': ctx._source[params.field]]); ` +
+		`ctx._source[params.field] = params.value;`
+	revertUpdateProviderScript = `if (ctx._source.updates == null) { return; } ` +
+		`for (int i = 0; i < ctx._source.updates.size(); i++) { ` +
+		`if (ctx._source.updates[i].txHash == params.txHash) { ` +
+		`ctx._source[ctx._source.updates[i].field] = ctx._source.updates[i].previousValue; ` +
+		`ctx._source.updates.remove(i); return; } }`
+)
+
+// delegationManagerSCAddress is the address of the system smart contract that creates the delegation contracts
+var delegationManagerSCAddress = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 255, 255}
+
+// delegationOperation holds a successful call to the delegation manager or to a delegation contract. The value is
+// the amount delegated, undelegated, withdrawn, claimed or redelegated by the call, or the new service fee or maximum
+// cap of the provider
+type delegationOperation struct {
+	function  string
+	txHash    string
+	delegator string
+	contract  string
+	value     *big.Int
+	timestamp time.Duration
+	provider  *data.DelegationProvider
+}
+
+// getDelegationOperations returns the successful delegation calls in the execution order of the provided block. The
+// delegation contracts live in metachain, so the other shards do not return any operation. The transactions map has
+// to be keyed by the raw transaction hashes
+func (tdp *txDatabaseProcessor) getDelegationOperations(
+	body *block.Body,
+	transactions map[string]*data.Transaction,
+	selfShardID uint32,
+) []*delegationOperation {
+	operations := make([]*delegationOperation, 0)
+	if selfShardID != core.MetachainShardId || body == nil {
+		return operations
+	}
+
+	for _, mb := range body.MiniBlocks {
+		if mb.Type != block.TxBlock || mb.ReceiverShardID != core.MetachainShardId {
+			continue
+		}
+
+		for _, txHash := range mb.TxHashes {
+			tx, ok := transactions[string(txHash)]
+			if !ok {
+				continue
+			}
+
+			operation, ok := tdp.parseDelegationOperation(tx)
+			if ok {
+				operations = append(operations, operation)
+			}
+		}
+	}
+
+	return operations
+}
+
+func (tdp *txDatabaseProcessor) parseDelegationOperation(tx *data.Transaction) (*delegationOperation, bool) {
+	if tx.Status == transaction.TxStatusInvalid.String() || tx.Status == transaction.TxStatusFail.String() {
+		return nil, false
+	}
+
+	receiver, err := tdp.addressPubkeyConverter.Decode(tx.Receiver)
+	if err != nil || len(receiver) < core.ShardIdentiferLen {
+		return nil, false
+	}
+	if !core.IsSmartContractOnMetachain(receiver[len(receiver)-core.ShardIdentiferLen:], receiver) {
+		return nil, false
+	}
+
+	function, args, ok := decodeCallArguments(tx.Data)
+	if !ok {
+		return nil, false
+	}
+
+	operation := &delegationOperation{
+		function:  function,
+		txHash:    tx.Hash,
+		delegator: tx.Sender,
+		contract:  tx.Receiver,
+		timestamp: tx.Timestamp,
+	}
+
+	switch function {
+	case createNewDelegationContractFunction:
+		if tx.Receiver != tdp.addressPubkeyConverter.Encode(delegationManagerSCAddress) || len(args) < minArgsCreateDelegationContract {
+			return nil, false
+		}
+
+		operation.contract = tdp.getCreatedDelegationContract(tx.SmartContractResults, tx.Receiver)
+		if operation.contract == "" {
+			return nil, false
+		}
+		operation.value = stringValueToBigInt(tx.Value)
+		operation.provider = &data.DelegationProvider{
+			Contract:   operation.contract,
+			Owner:      tx.Sender,
+			ServiceFee: big.NewInt(0).SetBytes(args[1]).Uint64(),
+			MaxCap:     big.NewInt(0).SetBytes(args[0]).String(),
+			Timestamp:  tx.Timestamp,
+		}
+	case delegateFunction:
+		operation.value = stringValueToBigInt(tx.Value)
+	case unDelegateFunction:
+		if len(args) < minArgsUnDelegate {
+			return nil, false
+		}
+		operation.value = big.NewInt(0).SetBytes(args[0])
+	case withdrawFunction, claimRewardsFunction:
+		operation.value = sumScResultsValues(tx.SmartContractResults, tx.Receiver, tx.Sender)
+	case reDelegateRewardsFunction:
+		// the rewards are staked again by the delegation contract on the validator system smart contract
+		operation.value = sumScResultsValues(tx.SmartContractResults, tx.Receiver, tdp.addressPubkeyConverter.Encode(validatorSCAddress))
+	case changeServiceFeeFunction, modifyTotalDelegationCapFunction:
+		// only the owner can change the configuration of the provider and both values can be zero
+		if len(args) < minArgsProviderUpdate {
+			return nil, false
+		}
+		operation.value = big.NewInt(0).SetBytes(args[0])
+		return operation, true
+	default:
+		return nil, false
+	}
+
+	if operation.value.Sign() <= 0 {
+		return nil, false
+	}
+
+	return operation, true
+}
+
+// getCreatedDelegationContract extracts the address of a new delegation contract, which is returned by the delegation
+// manager after the ok return code
+func (tdp *txDatabaseProcessor) getCreatedDelegationContract(scrs []data.ScResult, encodedManagerAddress string) string {
+	for _, scr := range scrs {
+		if scr.Sender != encodedManagerAddress || !isScResultSuccessful(scr.Data) {
+			continue
+		}
+
+		function, args, ok := decodeCallArguments(scr.Data)
+		if ok && function == "" && len(args) > 1 {
+			return tdp.addressPubkeyConverter.Encode(args[1])
+		}
+	}
+
+	return ""
+}
+
+func sumScResultsValues(scrs []data.ScResult, sender string, receiver string) *big.Int {
+	sum := big.NewInt(0)
+	for _, scr := range scrs {
+		if scr.Sender == sender && scr.Receiver == receiver {
+			sum.Add(sum, stringValueToBigInt(scr.Value))
+		}
+	}
+
+	return sum
+}
+
+func computeDelegatorID(delegator string, contract string) string {
+	return fmt.Sprintf("%s_%s", delegator, contract)
+}
+
+// putDelegationOperation adds the bulk operations that apply the provided delegation operation on the delegators and
+// on the providers documents
+func putDelegationOperation(operation *delegationOperation, delegatorsBuff bulkBuffer, providersBuff bulkBuffer) error {
+	id := computeDelegatorID(operation.delegator, operation.contract)
+	value := operation.value.String()
+
+	switch operation.function {
+	case createNewDelegationContractFunction:
+		serializedProvider, err := json.Marshal(operation.provider)
+		if err != nil {
+			return err
+		}
+
+		err = providersBuff.PutIndex(operation.contract, serializedProvider)
+		if err != nil {
+			return err
+		}
+
+		return putDelegatorStake(operation, delegatorsBuff)
+	case delegateFunction:
+		return putDelegatorStake(operation, delegatorsBuff)
+	case unDelegateFunction:
+		entry := &data.UnstakedEntry{
+			TxHash:    operation.txHash,
+			Value:     value,
+			Timestamp: operation.timestamp,
+		}
+		return putUpdateScript(id, unDelegateScript, objectsMap{"entry": entry}, delegatorsBuff)
+	case withdrawFunction:
+		return putUpdateScript(id, withdrawScript, objectsMap{"txHash": operation.txHash, "value": value}, delegatorsBuff)
+	case claimRewardsFunction:
+		return putAddToAmountScript(id, addToAmountScript, "claimedRewards", operation, delegatorsBuff)
+	case reDelegateRewardsFunction:
+		return putAddToAmountScript(id, addToAmountScript, "activeStake", operation, delegatorsBuff)
+	case changeServiceFeeFunction:
+		params := objectsMap{"txHash": operation.txHash, "field": "serviceFee", "value": operation.value.Uint64()}
+		return putUpdateScript(operation.contract, updateProviderScript, params, providersBuff)
+	case modifyTotalDelegationCapFunction:
+		params := objectsMap{"txHash": operation.txHash, "field": "maxCap", "value": value}
+		return putUpdateScript(operation.contract, updateProviderScript, params, providersBuff)
+	}
+
+	return nil
+}
+
+// putDelegationRevert adds the bulk operations that undo the provided delegation operation. A delegator document
+// created by a reverted delegation is kept with no active stake
+func putDelegationRevert(operation *delegationOperation, delegatorsBuff bulkBuffer, providersBuff bulkBuffer) error {
+	id := computeDelegatorID(operation.delegator, operation.contract)
+
+	switch operation.function {
+	case createNewDelegationContractFunction:
+		err := providersBuff.PutDelete(operation.contract)
+		if err != nil {
+			return err
+		}
+
+		return delegatorsBuff.PutDelete(id)
+	case delegateFunction, reDelegateRewardsFunction:
+		return putAddToAmountScript(id, revertAddToAmountScript, "activeStake", operation, delegatorsBuff)
+	case unDelegateFunction:
+		return putUpdateScript(id, revertUnDelegateScript, objectsMap{"txHash": operation.txHash}, delegatorsBuff)
+	case withdrawFunction:
+		return putUpdateScript(id, revertWithdrawScript, objectsMap{"txHash": operation.txHash}, delegatorsBuff)
+	case claimRewardsFunction:
+		return putAddToAmountScript(id, revertAddToAmountScript, "claimedRewards", operation, delegatorsBuff)
+	case changeServiceFeeFunction, modifyTotalDelegationCapFunction:
+		return putUpdateScript(operation.contract, revertUpdateProviderScript, objectsMap{"txHash": operation.txHash}, providersBuff)
+	}
+
+	return nil
+}
+
+// putDelegatorStake adds the delegated value to the active stake of the delegator, creating the delegator document
+// on the first delegation
+func putDelegatorStake(operation *delegationOperation, delegatorsBuff bulkBuffer) error {
+	script, err := json.Marshal(objectsMap{
+		"source": addToAmountScript,
+		"lang":   "painless",
+		"params": getAddToAmountParams("activeStake", operation),
+	})
+	if err != nil {
+		return err
+	}
+
+	serializedDelegator, err := json.Marshal(&data.Delegator{
+		Delegator:       operation.delegator,
+		Contract:        operation.contract,
+		ActiveStake:     operation.value.String(),
+		ClaimedRewards:  "0",
+		Unstaked:        make([]*data.UnstakedEntry, 0),
+		AppliedTxHashes: []string{operation.txHash},
+		Timestamp:       operation.timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	return delegatorsBuff.PutUpsert(computeDelegatorID(operation.delegator, operation.contract), script, serializedDelegator)
+}
+
+// putAddToAmountScript adds the update that applies, or reverts, the value of the provided operation on an amount of
+// the delegator
+func putAddToAmountScript(id string, source string, field string, operation *delegationOperation, delegatorsBuff bulkBuffer) error {
+	return putUpdateScript(id, source, getAddToAmountParams(field, operation), delegatorsBuff)
+}
+
+func getAddToAmountParams(field string, operation *delegationOperation) objectsMap {
+	return objectsMap{
+		"field":  field,
+		"value":  operation.value.String(),
+		"txHash": operation.txHash,
+	}
+}
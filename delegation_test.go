@@ -0,0 +1,258 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+var delegationContractAddress = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 255, 255}
+
+func createDelegationTxPool() map[string]coreData.TransactionHandler {
+	delegator := []byte("delegator")
+	return map[string]coreData.TransactionHandler{
+		"create": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationManagerSCAddress,
+			Value:   big.NewInt(1250),
+			Data:    createESDTCallData(createNewDelegationContractFunction, big.NewInt(5000).Bytes(), big.NewInt(1000).Bytes()),
+		},
+		"delegate": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(100),
+			Data:    []byte(delegateFunction),
+		},
+		"unDelegate": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(unDelegateFunction, big.NewInt(50).Bytes()),
+		},
+		"withdraw": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    []byte(withdrawFunction),
+		},
+		"claimRewards": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    []byte(claimRewardsFunction),
+		},
+		"reDelegateRewards": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    []byte(reDelegateRewardsFunction),
+		},
+		"changeServiceFee": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(changeServiceFeeFunction, big.NewInt(1500).Bytes()),
+		},
+		"modifyTotalDelegationCap": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: delegationContractAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(modifyTotalDelegationCapFunction, big.NewInt(0).Bytes()),
+		},
+		"notSystemContract": &transaction.Transaction{
+			SndAddr: delegator,
+			RcvAddr: []byte("receiver"),
+			Value:   big.NewInt(100),
+			Data:    []byte(delegateFunction),
+		},
+		"scrCreate": &smartContractResult.SmartContractResult{
+			SndAddr:        delegationManagerSCAddress,
+			RcvAddr:        delegator,
+			OriginalTxHash: []byte("create"),
+			Value:          big.NewInt(0),
+			Data:           createESDTCallData("", []byte("ok"), delegationContractAddress),
+		},
+		"scrWithdraw": &smartContractResult.SmartContractResult{
+			SndAddr:        delegationContractAddress,
+			RcvAddr:        delegator,
+			OriginalTxHash: []byte("withdraw"),
+			Data:           createESDTCallData("", []byte("ok")),
+			Value:          big.NewInt(50),
+		},
+		"scrClaimRewards": &smartContractResult.SmartContractResult{
+			SndAddr:        delegationContractAddress,
+			RcvAddr:        delegator,
+			OriginalTxHash: []byte("claimRewards"),
+			Data:           createESDTCallData("", []byte("ok")),
+			Value:          big.NewInt(7),
+		},
+		"scrReDelegateRewards": &smartContractResult.SmartContractResult{
+			SndAddr:        delegationContractAddress,
+			RcvAddr:        validatorSCAddress,
+			OriginalTxHash: []byte("reDelegateRewards"),
+			Data:           createESDTCallData("", []byte("ok")),
+			Value:          big.NewInt(3),
+		},
+	}
+}
+
+func TestTxDatabaseProcessor_GetDelegationOperations(t *testing.T) {
+	t.Parallel()
+
+	body := createSystemSCCallsBody("create", "delegate", "unDelegate", "withdraw", "claimRewards", "reDelegateRewards",
+		"changeServiceFee", "modifyTotalDelegationCap", "notSystemContract")
+	results := prepareSystemSCCalls(body, createDelegationTxPool(), core.MetachainShardId)
+	operations := results.delegationOperations
+	require.Len(t, operations, 8)
+
+	encodedDelegator := hex.EncodeToString([]byte("delegator"))
+	encodedContract := hex.EncodeToString(delegationContractAddress)
+	for _, operation := range operations {
+		require.Equal(t, encodedDelegator, operation.delegator)
+		require.Equal(t, encodedContract, operation.contract)
+	}
+
+	require.Equal(t, createNewDelegationContractFunction, operations[0].function)
+	require.Equal(t, big.NewInt(1250), operations[0].value)
+	require.Equal(t, &data.DelegationProvider{
+		Contract:   encodedContract,
+		Owner:      encodedDelegator,
+		ServiceFee: 1000,
+		MaxCap:     "5000",
+	}, operations[0].provider)
+
+	expectedValues := map[string]int64{
+		delegateFunction:                 100,
+		unDelegateFunction:               50,
+		withdrawFunction:                 50,
+		claimRewardsFunction:             7,
+		reDelegateRewardsFunction:        3,
+		changeServiceFeeFunction:         1500,
+		modifyTotalDelegationCapFunction: 0,
+	}
+	for _, operation := range operations[1:] {
+		require.Equal(t, big.NewInt(expectedValues[operation.function]), operation.value, operation.function)
+	}
+
+	results = prepareSystemSCCalls(body, createDelegationTxPool(), 0)
+	require.Len(t, results.delegationOperations, 0)
+}
+
+func TestPutDelegationOperationAndRevert(t *testing.T) {
+	t.Parallel()
+
+	operations := []*delegationOperation{
+		{function: createNewDelegationContractFunction, txHash: "createHash", delegator: "owner", contract: "contract", value: big.NewInt(10), provider: &data.DelegationProvider{Contract: "contract"}},
+		{function: unDelegateFunction, txHash: "unDelegateHash", delegator: "delegator", contract: "contract", value: big.NewInt(5)},
+		{function: withdrawFunction, txHash: "withdrawHash", delegator: "delegator", contract: "contract", value: big.NewInt(5)},
+		{function: claimRewardsFunction, txHash: "claimHash", delegator: "delegator", contract: "contract", value: big.NewInt(2)},
+		{function: changeServiceFeeFunction, txHash: "feeHash", delegator: "owner", contract: "contract", value: big.NewInt(1500)},
+		{function: modifyTotalDelegationCapFunction, txHash: "capHash", delegator: "owner", contract: "contract", value: big.NewInt(0)},
+	}
+
+	delegatorsBuff := data.NewBufferSlice()
+	providersBuff := data.NewBufferSlice()
+	for _, operation := range operations {
+		err := putDelegationOperation(operation, delegatorsBuff, providersBuff)
+		require.Nil(t, err)
+	}
+
+	providersOps := providersBuff.Buffers()[0].String()
+	require.Contains(t, providersOps, `{"index":{"_id":"contract"}}`+"\n"+`{"contract":"contract","owner":"","serviceFee":0,"maxCap":"","timestamp":0}`+"\n")
+	require.Contains(t, providersOps, `{"update":{"_id":"contract"}}`+"\n"+
+		`{ "script" : {"lang":"painless","params":{"field":"serviceFee","txHash":"feeHash","value":1500},"source":"`+updateProviderScript+`"} }`)
+	require.Contains(t, providersOps, `"params":{"field":"maxCap","txHash":"capHash","value":"0"}`)
+	delegatorsOps := delegatorsBuff.Buffers()[0].String()
+	require.Contains(t, delegatorsOps, `{"update":{"_id":"owner_contract"}}`+"\n"+
+		`{ "script" : {"lang":"painless","params":{"field":"activeStake","txHash":"createHash","value":"10"}`)
+	require.Contains(t, delegatorsOps, `"upsert" : {"delegator":"owner","contract":"contract","activeStake":"10","claimedRewards":"0","unstaked":[],"appliedTxHashes":["createHash"],"timestamp":0} }`)
+	require.Contains(t, delegatorsOps, `"params":{"entry":{"txHash":"unDelegateHash","value":"5","timestamp":0}}`)
+	require.Contains(t, delegatorsOps, `"params":{"txHash":"withdrawHash","value":"5"},"source":"`+strings.Replace(withdrawScript, ">", `\u003e`, -1)+`"`)
+	require.Contains(t, delegatorsOps, `"params":{"field":"claimedRewards","txHash":"claimHash","value":"2"},"source":"`+addToAmountScript+`"`)
+
+	delegatorsBuff = data.NewBufferSlice()
+	providersBuff = data.NewBufferSlice()
+	for _, operation := range operations {
+		err := putDelegationRevert(operation, delegatorsBuff, providersBuff)
+		require.Nil(t, err)
+	}
+
+	providersOps = providersBuff.Buffers()[0].String()
+	require.Contains(t, providersOps, `{"delete":{"_id":"contract"}}`+"\n")
+	require.Contains(t, providersOps, `"params":{"txHash":"feeHash"},"source":"`+strings.Replace(revertUpdateProviderScript, "<", `\u003c`, -1)+`"`)
+	require.Contains(t, providersOps, `"params":{"txHash":"capHash"}`)
+	delegatorsOps = delegatorsBuff.Buffers()[0].String()
+	require.Contains(t, delegatorsOps, `{"delete":{"_id":"owner_contract"}}`)
+	require.Contains(t, delegatorsOps, `"params":{"txHash":"unDelegateHash"},"source":"`+strings.Replace(revertUnDelegateScript, "<", `\u003c`, -1)+`"`)
+	require.Contains(t, delegatorsOps, `"params":{"txHash":"withdrawHash"},"source":"`+revertWithdrawScript+`"`)
+	require.Contains(t, delegatorsOps, `"params":{"field":"claimedRewards","txHash":"claimHash","value":"2"},"source":"`+revertAddToAmountScript+`"`)
+}
+
+func TestElasticProcessor_RevertDelegations(t *testing.T) {
+	t.Parallel()
+
+	encodedContract := hex.EncodeToString(delegationContractAddress)
+	delegateTx := &data.Transaction{
+		Sender:   hex.EncodeToString([]byte("delegator")),
+		Receiver: encodedContract,
+		Value:    "100",
+		Data:     []byte(delegateFunction),
+		Status:   transaction.TxStatusSuccess.String(),
+	}
+	serializedTx, _ := json.Marshal(delegateTx)
+	source := make(map[string]interface{})
+	_ = json.Unmarshal(serializedTx, &source)
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[delegatorsIndex] = struct{}{}
+	args.EnabledIndexes[providersIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, txIndex, index)
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": hex.EncodeToString([]byte("delegate")), "found": true, "_source": source},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	args.ShardCoordinator = &mock.ShardCoordinatorMock{SelfID: core.MetachainShardId}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	body := &dataBlock.Body{
+		MiniBlocks: []*dataBlock.MiniBlock{
+			{
+				Type:            dataBlock.TxBlock,
+				ReceiverShardID: core.MetachainShardId,
+				TxHashes:        [][]byte{[]byte("delegate")},
+			},
+		},
+	}
+
+	err = elasticProc.RevertDelegations(&dataBlock.Header{}, body)
+	require.Nil(t, err)
+	require.Empty(t, bulkRequests)
+
+	err = elasticProc.RevertDelegations(&dataBlock.MetaBlock{}, body)
+	require.Nil(t, err)
+	require.Contains(t, bulkRequests[delegatorsIndex], `{"update":{"_id":"`+hex.EncodeToString([]byte("delegator"))+"_"+encodedContract+`"}}`)
+	require.Contains(t, bulkRequests[delegatorsIndex], `"params":{"field":"activeStake","txHash":"`+hex.EncodeToString([]byte("delegate"))+`","value":"100"}`)
+}
@@ -91,11 +91,16 @@ func (ec *elasticClient) DoRequest(req *esapi.IndexRequest) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
-// DoBulkRequest will do a bulk of request to elastic server
-func (ec *elasticClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
+// DoBulkRequest will do a bulk of request to elastic server. An empty refresh policy will use the server's default
+func (ec *elasticClient) DoBulkRequest(buff *bytes.Buffer, index string, refresh string) error {
 	reader := bytes.NewReader(buff.Bytes())
 
-	res, err := ec.es.Bulk(reader, ec.es.Bulk.WithIndex(index))
+	options := []func(*esapi.BulkRequest){ec.es.Bulk.WithIndex(index)}
+	if refresh != "" {
+		options = append(options, ec.es.Bulk.WithRefresh(refresh))
+	}
+
+	res, err := ec.es.Bulk(reader, options...)
 	if err != nil {
 		log.Warn("elasticClient.DoBulkRequest",
 			"indexer do bulk request no response", err.Error())
@@ -133,6 +138,95 @@ func (ec *elasticClient) DoMultiGet(obj objectsMap, index string) (objectsMap, e
 	return decodedBody, nil
 }
 
+// DoSearch will run the provided search query on the index and will return the decoded response
+func (ec *elasticClient) DoSearch(query objectsMap, index string) (objectsMap, error) {
+	body, err := encode(query)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ec.es.Search(
+		ec.es.Search.WithIndex(index),
+		ec.es.Search.WithBody(&body),
+		ec.es.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		log.Warn("elasticClient.DoSearch",
+			"cannot do search no response", err.Error())
+		return nil, err
+	}
+
+	var decodedBody objectsMap
+	err = parseResponse(res, &decodedBody, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient.DoSearch",
+			"error parsing response", err.Error())
+		return nil, err
+	}
+
+	return decodedBody, nil
+}
+
+// DoUpdateByQuery will run the script of the provided query on all the matching documents of the index
+func (ec *elasticClient) DoUpdateByQuery(query objectsMap, index string) error {
+	body, err := encode(query)
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.es.UpdateByQuery(
+		[]string{index},
+		ec.es.UpdateByQuery.WithBody(&body),
+		ec.es.UpdateByQuery.WithConflicts("proceed"),
+		ec.es.UpdateByQuery.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		log.Warn("elasticClient.DoUpdateByQuery",
+			"cannot do update by query", err.Error())
+		return err
+	}
+
+	var decodedBody objectsMap
+	err = parseResponse(res, &decodedBody, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient.DoUpdateByQuery",
+			"error parsing response", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// DoDeleteByQuery will remove all the documents of the index that match the provided query
+func (ec *elasticClient) DoDeleteByQuery(query objectsMap, index string) error {
+	body, err := encode(query)
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.es.DeleteByQuery(
+		[]string{index},
+		&body,
+		ec.es.DeleteByQuery.WithConflicts("proceed"),
+		ec.es.DeleteByQuery.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		log.Warn("elasticClient.DoDeleteByQuery",
+			"cannot do delete by query", err.Error())
+		return err
+	}
+
+	var decodedBody objectsMap
+	err = parseResponse(res, &decodedBody, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		log.Warn("elasticClient.DoDeleteByQuery",
+			"error parsing response", err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // DoBulkRemove will do a bulk remove to elasticsearch server
 func (ec *elasticClient) DoBulkRemove(index string, hashes []string) error {
 	obj := prepareHashesForBulkRemove(hashes)
@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math"
 	"math/big"
 	"time"
 
@@ -15,20 +14,26 @@ import (
 	coreData "github.com/ElrondNetwork/elrond-go-core/data"
 	"github.com/ElrondNetwork/elrond-go-core/data/block"
 	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
-const numDecimalsInFloatBalance = 10
-
 type elasticProcessor struct {
 	*txDatabaseProcessor
 
-	elasticClient          DatabaseClientHandler
-	parser                 *dataParser
-	enabledIndexes         map[string]struct{}
-	accountsDB             AccountsAdapter
-	dividerForDenomination float64
-	balancePrecision       float64
+	elasticClient   DatabaseClientHandler
+	parser          *dataParser
+	enabledIndexes  map[string]struct{}
+	accountsDB      AccountsAdapter
+	numericValues   numericValuesConverter
+	refreshPolicies map[string]string
+	blocksBatcher   *blocksBatcher
+	nftsProcessor   *nftsProcessor
+	bulkMaxBytes    int
+	bulkMaxDocs     int
+	validatorsKeys  *validatorsKeysHolder
+
+	getCurrentTimestamp func() time.Duration
 }
 
 // NewElasticProcessor creates an elasticsearch es and handles saving
@@ -38,17 +43,27 @@ func NewElasticProcessor(arguments ArgElasticProcessor) (ElasticProcessor, error
 		return nil, err
 	}
 
+	numericValues := newNumericValuesConverter(arguments.Denomination, arguments.UseScaledNumericValues)
 	ei := &elasticProcessor{
 		elasticClient: arguments.DBClient,
 		parser: &dataParser{
-			hasher:      arguments.Hasher,
-			marshalizer: arguments.Marshalizer,
+			hasher:        arguments.Hasher,
+			marshalizer:   arguments.Marshalizer,
+			numericValues: numericValues,
+		},
+		enabledIndexes:  arguments.EnabledIndexes,
+		accountsDB:      arguments.AccountsDB,
+		numericValues:   numericValues,
+		refreshPolicies: arguments.RefreshPolicies,
+		bulkMaxBytes:    arguments.BulkMaxBytes,
+		bulkMaxDocs:     arguments.BulkMaxDocs,
+		validatorsKeys:  newValidatorsKeysHolder(),
+
+		getCurrentTimestamp: func() time.Duration {
+			return time.Duration(time.Now().Unix())
 		},
-		enabledIndexes:         arguments.EnabledIndexes,
-		accountsDB:             arguments.AccountsDB,
-		balancePrecision:       math.Pow(10, float64(numDecimalsInFloatBalance)),
-		dividerForDenomination: math.Pow(10, float64(core.MaxInt(arguments.Denomination, 0))),
 	}
+	ei.blocksBatcher = newBlocksBatcher(arguments.BlocksBatchSize, arguments.BlocksBatchMaxBytes, arguments.BlocksBatchMaxAge, ei.newBulkBuffer)
 
 	ei.txDatabaseProcessor = newTxDatabaseProcessor(
 		arguments.Hasher,
@@ -59,6 +74,12 @@ func NewElasticProcessor(arguments ArgElasticProcessor) (ElasticProcessor, error
 		arguments.IsInImportDBMode,
 		arguments.ShardCoordinator,
 	)
+	ei.nftsProcessor = newNFTsProcessor(
+		arguments.AccountsDB,
+		arguments.Marshalizer,
+		arguments.AddressPubkeyConverter,
+		arguments.ShardCoordinator,
+	)
 
 	if arguments.IsInImportDBMode {
 		log.Warn("the node is in import mode! Cross shard transactions and rewards where destination shard is " +
@@ -102,8 +123,14 @@ func checkArgElasticProcessor(arguments ArgElasticProcessor) error {
 	if check.IfNil(arguments.ShardCoordinator) {
 		return ErrNilShardCoordinator
 	}
+	if arguments.BlocksBatchSize < 0 || arguments.BlocksBatchMaxBytes < 0 || arguments.BlocksBatchMaxAge < 0 {
+		return ErrNegativeBatchLimit
+	}
+	if arguments.BulkMaxBytes < 0 || arguments.BulkMaxDocs < 0 {
+		return ErrNegativeBulkLimit
+	}
 
-	return nil
+	return checkRefreshPolicies(arguments.RefreshPolicies)
 }
 
 func (ei *elasticProcessor) initWithKibana(indexTemplates, indexPolicies map[string]*bytes.Buffer) error {
@@ -189,7 +216,6 @@ func (ei *elasticProcessor) createOpenDistroTemplates(indexTemplates map[string]
 }
 
 func (ei *elasticProcessor) createIndexTemplates(indexTemplates map[string]*bytes.Buffer) error {
-	indexes := []string{txIndex, blockIndex, miniblocksIndex, ratingIndex, roundIndex, validatorsIndex, accountsIndex, accountsHistoryIndex}
 	for _, index := range indexes {
 		indexTemplate := getTemplateByName(index, indexTemplates)
 		if indexTemplate != nil {
@@ -204,7 +230,6 @@ func (ei *elasticProcessor) createIndexTemplates(indexTemplates map[string]*byte
 }
 
 func (ei *elasticProcessor) createIndexes() error {
-	indexes := []string{txIndex, blockIndex, miniblocksIndex, ratingIndex, roundIndex, validatorsIndex, accountsIndex, accountsHistoryIndex}
 	for _, index := range indexes {
 		indexName := fmt.Sprintf("%s-000001", index)
 		err := ei.elasticClient.CheckAndCreateIndex(indexName)
@@ -217,7 +242,6 @@ func (ei *elasticProcessor) createIndexes() error {
 }
 
 func (ei *elasticProcessor) createAliases() error {
-	indexes := []string{txIndex, blockIndex, miniblocksIndex, ratingIndex, roundIndex, validatorsIndex, accountsIndex, accountsHistoryIndex}
 	for _, index := range indexes {
 		indexName := fmt.Sprintf("%s-000001", index)
 		err := ei.elasticClient.CheckAndCreateAlias(index, indexName)
@@ -235,12 +259,14 @@ func (ei *elasticProcessor) getExistingObjMap(hashes []string, index string) (ma
 		return make(map[string]bool), nil
 	}
 
-	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(hashes), index)
+	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(hashes, false), index)
 	if err != nil {
 		return nil, err
 	}
 
-	return getDecodedResponseMultiGet(response), nil
+	// documents that wait in the blocks batch are not yet visible in the database, but they will be written
+	// before any operation that is currently prepared
+	return ei.blocksBatcher.mergePendingIDs(index, hashes, getDecodedResponseMultiGet(response)), nil
 }
 
 func getTemplateByName(templateName string, templateList map[string]*bytes.Buffer) *bytes.Buffer {
@@ -258,19 +284,33 @@ func (ei *elasticProcessor) SaveHeader(
 	signersIndexes []uint64,
 	body *block.Body,
 	notarizedHeadersHashes []string,
+	pool *indexer.Pool,
 	txsSize int,
 ) error {
+	ei.validatorsKeys.setEpoch(header.GetShardID(), header.GetEpoch())
+	if header.IsStartOfEpochBlock() {
+		ei.validatorsKeys.setEpochStartTimestamp(header.GetEpoch(), header.GetTimeStamp())
+	}
 	if !ei.isIndexEnabled(blockIndex) {
 		return nil
 	}
 
 	var buff bytes.Buffer
 
-	serializedBlock, headerHash, err := ei.parser.getSerializedElasticBlockAndHeaderHash(header, signersIndexes, body, notarizedHeadersHashes, txsSize)
+	signersKeys, err := ei.getSignersKeys(header.GetShardID(), header.GetEpoch(), signersIndexes)
+	if err != nil {
+		return err
+	}
+
+	serializedBlock, headerHash, err := ei.parser.getSerializedElasticBlockAndHeaderHash(header, signersIndexes, signersKeys, body, notarizedHeadersHashes, pool, txsSize)
 	if err != nil {
 		return err
 	}
 
+	if ei.blocksBatcher.isEnabled() {
+		return ei.addBlockToBatch(serializedBlock, headerHash)
+	}
+
 	buff.Grow(len(serializedBlock))
 	_, err = buff.Write(serializedBlock)
 	if err != nil {
@@ -281,14 +321,199 @@ func (ei *elasticProcessor) SaveHeader(
 		Index:      blockIndex,
 		DocumentID: hex.EncodeToString(headerHash),
 		Body:       bytes.NewReader(buff.Bytes()),
-		Refresh:    "true",
+		Refresh:    ei.getRefreshPolicy(blockIndex),
 	}
 
 	return ei.elasticClient.DoRequest(req)
 }
 
+// UpdateNotarizedTransactions will set the nonce of the provided metachain header on the miniblocks of the shard blocks
+// it notarizes and on their transactions, on the source side or on the destination side. The transactions indexed
+// later by their shards take the nonce from their miniblocks
+func (ei *elasticProcessor) UpdateNotarizedTransactions(header coreData.HeaderHandler, notarizedHeadersHashes []string) error {
+	if !ei.isIndexEnabled(txIndex) && !ei.isIndexEnabled(miniblocksIndex) {
+		return nil
+	}
+	if header.GetShardID() != core.MetachainShardId || len(notarizedHeadersHashes) == 0 {
+		return nil
+	}
+
+	sourceHashes, destinationHashes := getNotarizedMiniblocksHashes(header, notarizedHeadersHashes)
+	if len(sourceHashes) == 0 && len(destinationHashes) == 0 {
+		return nil
+	}
+
+	err := ei.saveMiniblocksNotarization(sourceHashes, destinationHashes, header.GetNonce())
+	if err != nil {
+		return err
+	}
+
+	if !ei.isIndexEnabled(txIndex) {
+		return nil
+	}
+
+	if len(sourceHashes) > 0 {
+		err = ei.elasticClient.DoUpdateByQuery(getNotarizedTransactionsQuery(sourceHashes, notarizedAtSourceField, header.GetNonce()), txIndex)
+		if err != nil {
+			return err
+		}
+	}
+	if len(destinationHashes) == 0 {
+		return nil
+	}
+
+	return ei.elasticClient.DoUpdateByQuery(getNotarizedTransactionsQuery(destinationHashes, notarizedAtDestinationField, header.GetNonce()), txIndex)
+}
+
+func (ei *elasticProcessor) saveMiniblocksNotarization(sourceHashes []string, destinationHashes []string, metaNonce uint64) error {
+	if !ei.isIndexEnabled(miniblocksIndex) {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	err := putMiniblocksNotarization(sourceHashes, notarizedAtSourceField, metaNonce, buffSlice)
+	if err != nil {
+		return err
+	}
+	err = putMiniblocksNotarization(destinationHashes, notarizedAtDestinationField, metaNonce, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, miniblocksIndex)
+}
+
+// updateLateNotarizedTransactions will set on the provided transactions the metachain nonces already stored on their
+// miniblocks. It has to be called after the transactions were added to the bulk buffer of the transactions index
+func (ei *elasticProcessor) updateLateNotarizedTransactions(txs []*data.Transaction, selfShardID uint32) error {
+	if !ei.isIndexEnabled(miniblocksIndex) || selfShardID == core.MetachainShardId || len(txs) == 0 {
+		return nil
+	}
+
+	mbsHashes := make([]string, 0)
+	uniqueHashes := make(map[string]struct{})
+	for _, tx := range txs {
+		_, exists := uniqueHashes[tx.MBHash]
+		if !exists {
+			uniqueHashes[tx.MBHash] = struct{}{}
+			mbsHashes = append(mbsHashes, tx.MBHash)
+		}
+	}
+
+	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(mbsHashes, true), miniblocksIndex)
+	if err != nil {
+		return err
+	}
+
+	notarizations := getLateTransactionsNotarization(txs, getDecodedMiniblocksMultiGet(response))
+	if len(notarizations) == 0 {
+		return nil
+	}
+
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	buffSlice := ei.getBulkBuffer(txIndex)
+	for txHash, fields := range notarizations {
+		serializedFields, errMarshal := json.Marshal(fields)
+		if errMarshal != nil {
+			return errMarshal
+		}
+
+		err = buffSlice.PutUpdateDoc(txHash, serializedFields)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.doBulkRequests(buffSlice, txIndex)
+}
+
+func (ei *elasticProcessor) addBlockToBatch(serializedBlock []byte, headerHash []byte) error {
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	encodedHeaderHash := hex.EncodeToString(headerHash)
+	isAlreadyBatched := ei.blocksBatcher.isPending(blockIndex, encodedHeaderHash)
+	err := ei.blocksBatcher.getBuffer(blockIndex).PutIndex(encodedHeaderHash, serializedBlock)
+	if err != nil {
+		return err
+	}
+
+	ei.blocksBatcher.addPendingIDs(blockIndex, []string{encodedHeaderHash})
+	if !isAlreadyBatched {
+		ei.blocksBatcher.blockAdded()
+	}
+
+	return nil
+}
+
+// FlushBatch will send the accumulated blocks to the elasticsearch server if the batch reached one of its limits
+// or if the flush is forced
+func (ei *elasticProcessor) FlushBatch(force bool) error {
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	if !force && !ei.blocksBatcher.shouldFlush() {
+		return nil
+	}
+
+	return ei.blocksBatcher.flush(func(buff *bytes.Buffer, index string) error {
+		return ei.elasticClient.DoBulkRequest(buff, index, ei.getRefreshPolicy(index))
+	})
+}
+
+// NotifyWhenFlushed will call the provided handler once the blocks that wait in the batch were saved, or right away
+// if no block waits in the batch
+func (ei *elasticProcessor) NotifyWhenFlushed(handler func()) {
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	ei.blocksBatcher.notifyWhenFlushed(handler)
+}
+
+// getBulkBuffer returns the buffer in which the bulk operations for the provided index have to be written
+func (ei *elasticProcessor) getBulkBuffer(index string) bulkBuffer {
+	if ei.blocksBatcher.isEnabled() {
+		return ei.blocksBatcher.getBuffer(index)
+	}
+
+	return ei.newBulkBuffer()
+}
+
+func (ei *elasticProcessor) newBulkBuffer() bulkBuffer {
+	return data.NewBufferSliceWithLimits(ei.bulkMaxBytes, ei.bulkMaxDocs)
+}
+
+// doBulkRequests will send the provided buffers, unless they belong to the blocks batch
+func (ei *elasticProcessor) doBulkRequests(buffSlice bulkBuffer, index string) error {
+	if ei.blocksBatcher.isEnabled() {
+		return nil
+	}
+
+	return ei.sendBulkRequests(buffSlice, index)
+}
+
+// sendBulkRequests will send all the buffers of the provided bulk buffer
+func (ei *elasticProcessor) sendBulkRequests(buffSlice bulkBuffer, index string) error {
+	for _, buff := range buffSlice.Buffers() {
+		err := ei.elasticClient.DoBulkRequest(buff, index, ei.getRefreshPolicy(index))
+		if err != nil {
+			log.Warn("indexer: indexing bulk", "index", index, "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
 // RemoveHeader will remove a block from elasticsearch server
 func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
 	headerHash, err := core.CalculateHash(ei.marshalizer, ei.hasher, header)
 	if err != nil {
 		return err
@@ -297,12 +522,88 @@ func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
 	return ei.elasticClient.DoBulkRemove(blockIndex, []string{hex.EncodeToString(headerHash)})
 }
 
+// RemoveRoundsInfo will remove from elasticsearch server the round of the provided header and will subtract the rounds
+// starting with it from the validators statistics, as they belong to the abandoned branch. The missed rounds before
+// the header are not removed as they were missed regardless of the block that replaces the abandoned one
+func (ei *elasticProcessor) RemoveRoundsInfo(header coreData.HeaderHandler) error {
+	if !ei.isIndexEnabled(roundIndex) && !ei.isIndexEnabled(validatorsStatisticsIndex) {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	if ei.isIndexEnabled(validatorsStatisticsIndex) {
+		query := getValidatorsStatisticsFromRoundQuery(header.GetShardID(), header.GetRound())
+		err = ei.elasticClient.DoUpdateByQuery(query, validatorsStatisticsIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ei.isIndexEnabled(roundIndex) {
+		return nil
+	}
+
+	return ei.elasticClient.DoBulkRemove(roundIndex, []string{computeRoundID(header.GetShardID(), header.GetRound())})
+}
+
+// RemoveEpochData will remove from elasticsearch server the rating, the validators lists and the validators statistics
+// of the epoch started by the provided metachain header, as the epoch start was abandoned
+func (ei *elasticProcessor) RemoveEpochData(header coreData.HeaderHandler) error {
+	if header.GetShardID() != core.MetachainShardId || !header.IsStartOfEpochBlock() {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	epoch := header.GetEpoch()
+	ei.validatorsKeys.removeEpoch(epoch)
+
+	for _, index := range []string{ratingIndex, ratingHistoryIndex, validatorsStatisticsIndex} {
+		if !ei.isIndexEnabled(index) {
+			continue
+		}
+
+		err = ei.elasticClient.DoDeleteByQuery(getEpochDocumentsQuery(epoch), index)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ei.isIndexEnabled(validatorsIndex) {
+		return nil
+	}
+
+	err = ei.elasticClient.DoUpdateByQuery(getRevertEpochValidatorsQuery(epoch), validatorsIndex)
+	if err != nil {
+		return err
+	}
+
+	listsIDs := []string{computeValidatorsListID(core.MetachainShardId, epoch)}
+	for shardID := uint32(0); shardID < ei.shardCoordinator.NumberOfShards(); shardID++ {
+		listsIDs = append(listsIDs, computeValidatorsListID(shardID, epoch))
+	}
+
+	return ei.elasticClient.DoBulkRemove(validatorsIndex, listsIDs)
+}
+
 // RemoveMiniblocks will remove all miniblocks that are in header from elasticsearch server
 func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error {
 	if body == nil || len(header.GetMiniBlockHeadersHashes()) == 0 {
 		return nil
 	}
 
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
 	encodedMiniblocksHashes := make([]string, 0)
 	selfShardID := header.GetShardID()
 	for _, miniblock := range body.MiniBlocks {
@@ -346,8 +647,16 @@ func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, body *
 		return make(map[string]bool), nil
 	}
 
-	buff, mbHashDb := serializeBulkMiniBlocks(header.GetShardID(), miniblocks, ei.getExistingObjMap)
-	return mbHashDb, ei.elasticClient.DoBulkRequest(&buff, miniblocksIndex)
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	buffSlice := ei.getBulkBuffer(miniblocksIndex)
+	mbHashDb := serializeBulkMiniBlocks(header.GetShardID(), miniblocks, ei.getExistingObjMap, buffSlice)
+	if ei.blocksBatcher.isEnabled() {
+		ei.blocksBatcher.addPendingIDs(miniblocksIndex, getMiniblocksHashes(miniblocks))
+	}
+
+	return mbHashDb, ei.doBulkRequests(buffSlice, miniblocksIndex)
 }
 
 // SaveTransactions will prepare and save information about a transactions in elasticsearch server
@@ -367,244 +676,990 @@ func (ei *elasticProcessor) SaveTransactions(
 	allTxs := mergeSliceOfMaps(sliceMaps)
 
 	selfShardID := ei.shardCoordinator.SelfId()
-	txs, alteredAccounts := ei.prepareTransactionsForDatabase(body, header, allTxs, selfShardID)
-	buffSlice, err := serializeTransactions(txs, selfShardID, ei.getExistingObjMap, mbsInDb)
+	preparedTxs := ei.prepareTransactionsForDatabase(body, header, allTxs, selfShardID)
+	err := ei.saveTransactions(preparedTxs.transactions, selfShardID, mbsInDb)
 	if err != nil {
 		return err
 	}
 
-	for idx := range buffSlice {
-		err = ei.elasticClient.DoBulkRequest(&buffSlice[idx], txIndex)
-		if err != nil {
-			log.Warn("indexer indexing bulk of transactions",
-				"error", err.Error())
-			return err
-		}
+	err = ei.updateLateNotarizedTransactions(preparedTxs.transactions, selfShardID)
+	if err != nil {
+		return err
 	}
 
-	return ei.indexAlteredAccounts(header.GetTimeStamp(), alteredAccounts)
-}
-
-func mergeSliceOfMaps(sliceMaps []map[string]coreData.TransactionHandler) map[string]coreData.TransactionHandler {
-	allTxs := make(map[string]coreData.TransactionHandler)
-
-	for _, txsMap := range sliceMaps {
-		for hash, tx := range txsMap {
-			allTxs[hash] = tx
-		}
+	err = ei.saveAddressActivities(preparedTxs.addressActivities)
+	if err != nil {
+		return err
 	}
 
-	return allTxs
-}
-
-// SaveValidatorsRating will save validators rating
-func (ei *elasticProcessor) SaveValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error {
-	if !ei.isIndexEnabled(ratingIndex) {
-		return nil
+	err = ei.updatePreviousTransactions(preparedTxs.scrsOfPreviousTxs)
+	if err != nil {
+		return err
 	}
 
-	var buff bytes.Buffer
+	err = ei.indexTokensRegistry(preparedTxs.tokensRegistryOperations)
+	if err != nil {
+		return err
+	}
 
-	infosRating := data.ValidatorsRatingInfo{ValidatorsInfos: validatorsRatingInfo}
+	err = ei.indexTokens(header.GetTimeStamp(), pool, preparedTxs.transactions)
+	if err != nil {
+		return err
+	}
 
-	marshalizedInfoRating, err := json.Marshal(&infosRating)
+	err = ei.indexScDeploys(preparedTxs.scDeploys)
 	if err != nil {
-		log.Debug("indexer: marshal", "error", "could not marshal validators rating")
 		return err
 	}
 
-	buff.Grow(len(marshalizedInfoRating))
-	_, err = buff.Write(marshalizedInfoRating)
+	err = ei.indexValidatorsOperations(preparedTxs.validatorsOperations)
 	if err != nil {
-		log.Warn("elastic search: save validators rating, write", "error", err.Error())
+		return err
 	}
 
-	req := &esapi.IndexRequest{
-		Index:      ratingIndex,
-		DocumentID: index,
-		Body:       bytes.NewReader(buff.Bytes()),
-		Refresh:    "true",
+	err = ei.indexDelegationOperations(preparedTxs.delegationOperations)
+	if err != nil {
+		return err
 	}
 
-	return ei.elasticClient.DoRequest(req)
+	return ei.indexAlteredAccounts(header, preparedTxs.alteredAccounts)
 }
 
-// SaveShardValidatorsPubKeys will prepare and save information about a shard validators public keys in elasticsearch server
-func (ei *elasticProcessor) SaveShardValidatorsPubKeys(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error {
-	if !ei.isIndexEnabled(validatorsIndex) {
+// updatePreviousTransactions will add the provided smart contract results to the transactions of previous blocks
+// they belong to and will update the status, the gas used and the fee of these transactions accordingly. The results
+// of the transactions that are not indexed are skipped, as the transactions documents cannot be built from them
+func (ei *elasticProcessor) updatePreviousTransactions(scrsOfPreviousTxs map[string][]data.ScResult) error {
+	if len(scrsOfPreviousTxs) == 0 {
 		return nil
 	}
 
-	var buff bytes.Buffer
-
-	shardValPubKeys := data.ValidatorsPublicKeys{
-		PublicKeys: make([]string, 0, len(shardValidatorsPubKeys)),
-	}
-	for _, validatorPk := range shardValidatorsPubKeys {
-		strValidatorPk := ei.validatorPubkeyConverter.Encode(validatorPk)
-		shardValPubKeys.PublicKeys = append(shardValPubKeys.PublicKeys, strValidatorPk)
+	encodedHashes := make([]string, 0, len(scrsOfPreviousTxs))
+	for txHash := range scrsOfPreviousTxs {
+		encodedHashes = append(encodedHashes, hex.EncodeToString([]byte(txHash)))
 	}
 
-	marshalizedValidatorPubKeys, err := json.Marshal(shardValPubKeys)
+	err := ei.flushPendingTransactions(encodedHashes)
 	if err != nil {
-		log.Debug("indexer: marshal", "error", "could not marshal validators public keys")
 		return err
 	}
 
-	buff.Grow(len(marshalizedValidatorPubKeys))
-	_, err = buff.Write(marshalizedValidatorPubKeys)
+	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(encodedHashes, true), txIndex)
 	if err != nil {
-		log.Warn("elastic search: save shard validators pub keys, write", "error", err.Error())
+		return err
 	}
 
-	req := &esapi.IndexRequest{
-		Index:      validatorsIndex,
-		DocumentID: fmt.Sprintf("%d_%d", shardID, epoch),
-		Body:       bytes.NewReader(buff.Bytes()),
-		Refresh:    "true",
+	transactions := getDecodedTransactionsMultiGet(response)
+	for txHash, scrs := range scrsOfPreviousTxs {
+		_, found := transactions[txHash]
+		if !found {
+			log.Warn("indexer: smart contract results of a transaction that is not indexed were skipped",
+				"tx hash", hex.EncodeToString([]byte(txHash)), "num results", len(scrs))
+		}
 	}
 
-	return ei.elasticClient.DoRequest(req)
+	buffSlice := ei.newBulkBuffer()
+	for txHash, tx := range transactions {
+		tx.ReceiverAddressBytes, _ = ei.addressPubkeyConverter.Decode(tx.Receiver)
+		update, ok := ei.prepareTxStatusUpdate(tx, scrsOfPreviousTxs[txHash])
+		if !ok {
+			continue
+		}
+		ei.setTransactionNumericValues(tx)
+		update.feeNum = tx.FeeNum
+		update.feeScaled = tx.FeeScaled
+
+		err = putTxStatusUpdate(update, buffSlice)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk transactions status, write", "error", err.Error())
+			return err
+		}
+	}
+
+	return ei.sendBulkRequests(buffSlice, txIndex)
 }
 
-// SaveRoundsInfo will prepare and save information about a slice of rounds in elasticsearch server
-func (ei *elasticProcessor) SaveRoundsInfo(infos []*data.RoundInfo) error {
-	if !ei.isIndexEnabled(roundIndex) {
+// flushPendingTransactions will send the blocks batch if one of the provided transactions is still waiting in it
+func (ei *elasticProcessor) flushPendingTransactions(encodedHashes []string) error {
+	ei.blocksBatcher.mutex.Lock()
+	pendingTxs := ei.blocksBatcher.mergePendingIDs(txIndex, encodedHashes, make(map[string]bool))
+	ei.blocksBatcher.mutex.Unlock()
+
+	if len(pendingTxs) == 0 {
 		return nil
 	}
 
-	var buff bytes.Buffer
-
-	for _, info := range infos {
-		serializedRoundInfo, meta := serializeRoundInfo(*info)
+	return ei.FlushBatch(true)
+}
 
-		buff.Grow(len(meta) + len(serializedRoundInfo))
-		_, err := buff.Write(meta)
-		if err != nil {
-			log.Warn("indexer: cannot write meta", "error", err.Error())
-		}
+// indexTokensRegistry will apply the provided calls to the system ESDT smart contract on the registry documents of
+// the tokens
+func (ei *elasticProcessor) indexTokensRegistry(operations []*tokenRegistryOperation) error {
+	if !ei.isIndexEnabled(tokensIndex) || len(operations) == 0 {
+		return nil
+	}
 
-		_, err = buff.Write(serializedRoundInfo)
+	buffSlice := ei.newBulkBuffer()
+	for _, operation := range operations {
+		err := putTokenRegistryOperation(operation, buffSlice)
 		if err != nil {
-			log.Warn("indexer: cannot write serialized round info", "error", err.Error())
+			log.Warn("elastic search: serialize bulk tokens registry, write", "error", err.Error())
+			return err
 		}
 	}
 
-	return ei.elasticClient.DoBulkRequest(&buff, roundIndex)
+	return ei.sendBulkRequests(buffSlice, tokensIndex)
 }
 
-func (ei *elasticProcessor) indexAlteredAccounts(blockTimestamp uint64, accounts map[string]struct{}) error {
-	if !ei.isIndexEnabled(accountsIndex) {
+// indexScDeploys will save the smart contracts deployed by the transactions of the block and will append the
+// successful upgrades to the history of the upgraded contracts
+func (ei *elasticProcessor) indexScDeploys(results *scDeploysResults) error {
+	if !ei.isIndexEnabled(scDeploysIndex) || len(results.deploys) == 0 && len(results.upgrades) == 0 {
 		return nil
 	}
 
-	accountsToIndex := make([]coreData.UserAccountHandler, 0)
-	for address := range accounts {
-		addressBytes, err := ei.addressPubkeyConverter.Decode(address)
-		if err != nil {
-			log.Warn("cannot decode address", "address", address, "error", err)
-			continue
-		}
-
-		if ei.shardCoordinator.ComputeId(addressBytes) != ei.shardCoordinator.SelfId() {
-			continue
+	buffSlice := ei.newBulkBuffer()
+	err := serializeScDeploys(results, buffSlice)
+	if err != nil {
+		log.Warn("elastic search: serialize bulk sc deploys, write", "error", err.Error())
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, scDeploysIndex)
+}
+
+// indexValidatorsOperations will apply the changes made by the calls to the validator system smart contract on the
+// validators documents. The top-ups are applied on all the indexed validators of their owners
+func (ei *elasticProcessor) indexValidatorsOperations(operations *validatorsOperations) error {
+	if !ei.isIndexEnabled(validatorsIndex) || len(operations.updates) == 0 && len(operations.topUps) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	for _, update := range operations.updates {
+		err := putValidatorUpdate(update, buffSlice)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk validators, write", "error", err.Error())
+			return err
 		}
+	}
 
-		account, err := ei.accountsDB.LoadAccount(addressBytes)
+	err := ei.sendBulkRequests(buffSlice, validatorsIndex)
+	if err != nil {
+		return err
+	}
+
+	for owner, topUps := range operations.topUps {
+		err = ei.elasticClient.DoUpdateByQuery(getValidatorsTopUpQuery(owner, topUps), validatorsIndex)
 		if err != nil {
-			log.Warn("cannot load account", "address bytes", addressBytes, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexDelegationOperations will apply the successful delegation calls of the block on the delegators and on the
+// providers documents
+func (ei *elasticProcessor) indexDelegationOperations(operations []*delegationOperation) error {
+	if !ei.isDelegationEnabled() || len(operations) == 0 {
+		return nil
+	}
+
+	delegatorsBuff := ei.newBulkBuffer()
+	providersBuff := ei.newBulkBuffer()
+	for _, operation := range operations {
+		err := putDelegationOperation(operation, delegatorsBuff, providersBuff)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk delegation, write", "error", err.Error())
+			return err
+		}
+	}
+
+	return ei.sendDelegationBulkRequests(delegatorsBuff, providersBuff)
+}
+
+func (ei *elasticProcessor) sendDelegationBulkRequests(delegatorsBuff bulkBuffer, providersBuff bulkBuffer) error {
+	err := ei.sendBulkRequests(providersBuff, providersIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(delegatorsBuff, delegatorsIndex)
+}
+
+// isDelegationEnabled returns true if the delegation calls should be indexed. The delegators and the providers are
+// updated together, so both indexes have to be enabled
+func (ei *elasticProcessor) isDelegationEnabled() bool {
+	return ei.isIndexEnabled(delegatorsIndex) && ei.isIndexEnabled(providersIndex)
+}
+
+// RevertTokens will undo the changes made on the tokens registry by the transactions of the provided block. The
+// transactions are read back from the database, so this has to be called before they are removed
+func (ei *elasticProcessor) RevertTokens(header coreData.HeaderHandler, body *block.Body) error {
+	if !ei.isIndexEnabled(tokensIndex) || !ei.isIndexEnabled(txIndex) {
+		return nil
+	}
+	if body == nil || header.GetShardID() != core.MetachainShardId {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	txs, err := ei.getTransactionsFromDatabase(body)
+	if err != nil {
+		return err
+	}
+
+	operations := ei.getTokensRegistryOperations(body, txs, header.GetShardID())
+	if len(operations) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	for idx := len(operations) - 1; idx >= 0; idx-- {
+		err = putTokenRegistryRevert(operations[idx], buffSlice)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk tokens registry, revert", "error", err.Error())
+			return err
+		}
+	}
+
+	return ei.sendBulkRequests(buffSlice, tokensIndex)
+}
+
+// RevertDelegations will undo the changes made on the delegators and on the providers by the transactions of the
+// provided block. The transactions are read back from the database, so this has to be called before they are removed
+func (ei *elasticProcessor) RevertDelegations(header coreData.HeaderHandler, body *block.Body) error {
+	if !ei.isDelegationEnabled() || !ei.isIndexEnabled(txIndex) {
+		return nil
+	}
+	if body == nil || header.GetShardID() != core.MetachainShardId {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	txs, err := ei.getTransactionsFromDatabase(body)
+	if err != nil {
+		return err
+	}
+
+	operations := ei.getDelegationOperations(body, txs, header.GetShardID())
+	if len(operations) == 0 {
+		return nil
+	}
+
+	delegatorsBuff := ei.newBulkBuffer()
+	providersBuff := ei.newBulkBuffer()
+	for idx := len(operations) - 1; idx >= 0; idx-- {
+		err = putDelegationRevert(operations[idx], delegatorsBuff, providersBuff)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk delegation, revert", "error", err.Error())
+			return err
+		}
+	}
+
+	return ei.sendDelegationBulkRequests(delegatorsBuff, providersBuff)
+}
+
+// getTransactionsFromDatabase returns the indexed transactions of the metachain destination miniblocks of the
+// provided body, keyed by their raw hashes
+func (ei *elasticProcessor) getTransactionsFromDatabase(body *block.Body) (map[string]*data.Transaction, error) {
+	encodedHashes := make([]string, 0)
+	for _, mb := range body.MiniBlocks {
+		if mb.Type != block.TxBlock || mb.ReceiverShardID != core.MetachainShardId {
 			continue
 		}
 
-		userAccount, ok := account.(coreData.UserAccountHandler)
+		for _, txHash := range mb.TxHashes {
+			encodedHashes = append(encodedHashes, hex.EncodeToString(txHash))
+		}
+	}
+
+	return ei.getTransactionsByHashes(encodedHashes)
+}
+
+func (ei *elasticProcessor) getTransactionsByHashes(encodedHashes []string) (map[string]*data.Transaction, error) {
+	if len(encodedHashes) == 0 {
+		return make(map[string]*data.Transaction), nil
+	}
+
+	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(encodedHashes, true), txIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return getDecodedTransactionsMultiGet(response), nil
+}
+
+// getAlteredAccountsFromDatabase returns the accounts of the current shard altered by the transactions of the provided
+// block, which are read from the database. The receivers of the smart contract results are taken from the results
+// stored in the transactions
+func (ei *elasticProcessor) getAlteredAccountsFromDatabase(body *block.Body) (map[string]*data.AlteredAccount, error) {
+	encodedHashes := make([]string, 0)
+	for _, mb := range body.MiniBlocks {
+		if mb.Type != block.TxBlock && mb.Type != block.InvalidBlock && mb.Type != block.RewardsBlock {
+			continue
+		}
+
+		for _, txHash := range mb.TxHashes {
+			encodedHashes = append(encodedHashes, hex.EncodeToString(txHash))
+		}
+	}
+
+	txs, err := ei.getTransactionsByHashes(encodedHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	selfShardID := ei.shardCoordinator.SelfId()
+	alteredAccounts := make(map[string]*data.AlteredAccount)
+	for _, mb := range body.MiniBlocks {
+		for _, txHash := range mb.TxHashes {
+			tx, ok := txs[string(txHash)]
+			if !ok {
+				continue
+			}
+
+			addToAlteredAddresses(tx, alteredAccounts, mb, selfShardID, mb.Type == block.RewardsBlock)
+			ei.addRelayedInnerAddressesToAlteredAccounts(tx, alteredAccounts, selfShardID)
+			for _, scr := range tx.SmartContractResults {
+				receiver, errDecode := ei.addressPubkeyConverter.Decode(scr.Receiver)
+				if errDecode != nil || ei.shardCoordinator.ComputeId(receiver) != selfShardID {
+					continue
+				}
+
+				addAlteredAccount(alteredAccounts, scr.Receiver, tx.Hash, false)
+			}
+		}
+	}
+
+	return alteredAccounts, nil
+}
+
+// indexTokens will save the metadata and the supply of the non-fungible and semi-fungible tokens that were created or
+// altered by the successful transactions of the block and will remove the tokens whose supply reached zero
+func (ei *elasticProcessor) indexTokens(blockTimestamp uint64, pool *indexer.Pool, txs []*data.Transaction) error {
+	if !ei.isIndexEnabled(nftsIndex) {
+		return nil
+	}
+
+	txsPool := mergeSliceOfMaps([]map[string]coreData.TransactionHandler{pool.Txs, pool.Scrs})
+	operations := ei.nftsProcessor.getNFTOperations(txsPool, getFailedTransactionsHashes(txs))
+	tokens := ei.nftsProcessor.prepareTokens(operations, blockTimestamp)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	err := serializeTokens(tokens, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, nftsIndex)
+}
+
+func (ei *elasticProcessor) saveTransactions(txs []*data.Transaction, selfShardID uint32, mbsInDb map[string]bool) error {
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	for _, tx := range txs {
+		ei.setTransactionNumericValues(tx)
+	}
+
+	buffSlice := ei.getBulkBuffer(txIndex)
+	err := serializeTransactions(txs, selfShardID, buffSlice)
+	if err != nil {
+		return err
+	}
+	if ei.blocksBatcher.isEnabled() {
+		ei.blocksBatcher.addPendingIDs(txIndex, getTransactionsHashes(txs))
+	}
+
+	return ei.doBulkRequests(buffSlice, txIndex)
+}
+
+// saveAddressActivities writes the address activity entries of a block alongside its transactions
+func (ei *elasticProcessor) saveAddressActivities(activities []*data.AddressActivity) error {
+	if !ei.isIndexEnabled(addressActivityIndex) || len(activities) == 0 {
+		return nil
+	}
+
+	ei.blocksBatcher.mutex.Lock()
+	defer ei.blocksBatcher.mutex.Unlock()
+
+	buffSlice := ei.getBulkBuffer(addressActivityIndex)
+	err := serializeAddressActivities(activities, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.doBulkRequests(buffSlice, addressActivityIndex)
+}
+
+// RevertAddressActivity will remove the address activity entries created by the provided block
+func (ei *elasticProcessor) RevertAddressActivity(header coreData.HeaderHandler) error {
+	if !ei.isIndexEnabled(addressActivityIndex) {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	return ei.elasticClient.DoDeleteByQuery(getBlockEntriesQuery(header.GetShardID(), header.GetNonce()), addressActivityIndex)
+}
+
+// setTransactionNumericValues sets the denominated numeric values of the value and of the fee of the transaction
+func (ei *elasticProcessor) setTransactionNumericValues(tx *data.Transaction) {
+	value := stringValueToBigInt(tx.Value)
+	fee := stringValueToBigInt(tx.Fee)
+
+	tx.ValueNum = ei.numericValues.computeFloat(value)
+	tx.ValueScaled = ei.numericValues.computeScaled(value)
+	tx.FeeNum = ei.numericValues.computeFloat(fee)
+	tx.FeeScaled = ei.numericValues.computeScaled(fee)
+}
+
+func mergeSliceOfMaps(sliceMaps []map[string]coreData.TransactionHandler) map[string]coreData.TransactionHandler {
+	allTxs := make(map[string]coreData.TransactionHandler)
+
+	for _, txsMap := range sliceMaps {
+		for hash, tx := range txsMap {
+			allTxs[hash] = tx
+		}
+	}
+
+	return allTxs
+}
+
+// getEpochStartTimestamp returns the timestamp of the block that started the provided epoch. The timestamp is read
+// from the blocks index when the epoch start block was not saved since the indexer started
+func (ei *elasticProcessor) getEpochStartTimestamp(epoch uint32) (uint64, error) {
+	timestamp, ok := ei.validatorsKeys.getEpochStartTimestamp(epoch)
+	if ok {
+		return timestamp, nil
+	}
+	if !ei.isIndexEnabled(blockIndex) {
+		return 0, fmt.Errorf("%w: epoch %d", ErrUnknownEpochStartTimestamp, epoch)
+	}
+
+	response, err := ei.elasticClient.DoSearch(getEpochStartBlockQuery(epoch), blockIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	epochStartBlock := &data.Block{}
+	found, err := getDecodedFirstSearchHit(response, epochStartBlock)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: epoch %d", ErrUnknownEpochStartTimestamp, epoch)
+	}
+
+	timestamp = uint64(epochStartBlock.Timestamp)
+	ei.validatorsKeys.setEpochStartTimestamp(epoch, timestamp)
+
+	return timestamp, nil
+}
+
+// SaveValidatorsRating will save a document for every validator rating and, if enabled, the ratings history. The
+// ratings are also set on the validators documents. The ratings are timestamped with the epoch start block, while the
+// history entries are timestamped with the moment of the save, so every save adds a new entry
+func (ei *elasticProcessor) SaveValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error {
+	saveRating := ei.isIndexEnabled(ratingIndex)
+	saveRatingHistory := ei.isIndexEnabled(ratingHistoryIndex)
+	updateValidators := ei.isIndexEnabled(validatorsIndex)
+	if !saveRating && !saveRatingHistory && !updateValidators {
+		return nil
+	}
+
+	shardID, epoch, err := parseRatingIndexID(index)
+	if err != nil {
+		return err
+	}
+
+	epochStartTimestamp, err := ei.getEpochStartTimestamp(epoch)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Duration(epochStartTimestamp)
+	for _, info := range validatorsRatingInfo {
+		info.ShardID = shardID
+		info.Epoch = epoch
+		info.Timestamp = timestamp
+	}
+
+	if saveRating {
+		buffSlice := ei.newBulkBuffer()
+		err = serializeValidatorsRating(validatorsRatingInfo, buffSlice)
+		if err != nil {
+			return err
+		}
+
+		err = ei.sendBulkRequests(buffSlice, ratingIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	if saveRatingHistory {
+		buffSlice := ei.newBulkBuffer()
+		err = serializeValidatorsRatingHistory(validatorsRatingInfo, ei.getCurrentTimestamp(), buffSlice)
+		if err != nil {
+			return err
+		}
+
+		err = ei.sendBulkRequests(buffSlice, ratingHistoryIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !updateValidators {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	for _, info := range validatorsRatingInfo {
+		err = putValidatorUpdate(&validatorUpdate{blsKey: info.PublicKey, fields: objectsMap{"rating": info.Rating}}, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ei.sendBulkRequests(buffSlice, validatorsIndex)
+}
+
+// SaveShardValidatorsPubKeys will save the ordered list of the validators public keys of the shard in the epoch and
+// will mark the validators as eligible. The validators of the shard that were eligible in a previous epoch and are not
+// part of the list anymore are moved in the waiting list
+func (ei *elasticProcessor) SaveShardValidatorsPubKeys(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error {
+	shardValPubKeys := data.ValidatorsPublicKeys{
+		PublicKeys: make([]string, 0, len(shardValidatorsPubKeys)),
+	}
+	for _, validatorPk := range shardValidatorsPubKeys {
+		strValidatorPk := ei.validatorPubkeyConverter.Encode(validatorPk)
+		shardValPubKeys.PublicKeys = append(shardValPubKeys.PublicKeys, strValidatorPk)
+	}
+	ei.validatorsKeys.addList(shardID, epoch, shardValPubKeys.PublicKeys)
+
+	if !ei.isIndexEnabled(validatorsIndex) {
+		return nil
+	}
+
+	marshalizedValidatorPubKeys, err := json.Marshal(shardValPubKeys)
+	if err != nil {
+		log.Debug("indexer: marshal", "error", "could not marshal validators public keys")
+		return err
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	err = buffSlice.PutIndex(computeValidatorsListID(shardID, epoch), marshalizedValidatorPubKeys)
+	if err != nil {
+		log.Warn("elastic search: save shard validators pub keys, write", "error", err.Error())
+		return err
+	}
+
+	for _, strValidatorPk := range shardValPubKeys.PublicKeys {
+		err = putEligibleValidatorUpdate(strValidatorPk, shardID, epoch, buffSlice)
+		if err != nil {
+			log.Warn("elastic search: save shard validators pub keys, write", "error", err.Error())
+			return err
+		}
+	}
+
+	err = ei.elasticClient.DoUpdateByQuery(getPreviousEligibleValidatorsQuery(shardID, epoch), validatorsIndex)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, validatorsIndex)
+}
+
+// getSignersKeys resolves the provided signers indexes to the public keys of the validators of the shard in the epoch.
+// Nothing is returned if the validators list is not known
+func (ei *elasticProcessor) getSignersKeys(shardID uint32, epoch uint32, signersIndexes []uint64) ([]string, error) {
+	if len(signersIndexes) == 0 {
+		return nil, nil
+	}
+
+	publicKeys, ok := ei.validatorsKeys.getList(shardID, epoch)
+	if ok {
+		return resolveSignersKeys(publicKeys, signersIndexes), nil
+	}
+	if !ei.isIndexEnabled(validatorsIndex) {
+		return nil, nil
+	}
+
+	query := getDocumentsByIDsQuery([]string{computeValidatorsListID(shardID, epoch)}, true)
+	response, err := ei.elasticClient.DoMultiGet(query, validatorsIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing list is cached as well, so it is not requested again for every block
+	publicKeys = getDecodedValidatorsPublicKeys(response)
+	ei.validatorsKeys.addList(shardID, epoch, publicKeys)
+
+	return resolveSignersKeys(publicKeys, signersIndexes), nil
+}
+
+// getShardEpoch returns the last epoch seen in the blocks of the provided shard. The epoch is read from the last
+// indexed block of the shard when no block of the shard was saved since the indexer started
+func (ei *elasticProcessor) getShardEpoch(shardID uint32) (uint32, bool, error) {
+	epoch, ok := ei.validatorsKeys.getEpoch(shardID)
+	if ok || !ei.isIndexEnabled(blockIndex) {
+		return epoch, ok, nil
+	}
+
+	response, err := ei.elasticClient.DoSearch(getLastShardBlockQuery(shardID), blockIndex)
+	if err != nil {
+		return 0, false, err
+	}
+
+	lastBlock := &data.Block{}
+	found, err := getDecodedFirstSearchHit(response, lastBlock)
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	ei.validatorsKeys.setEpoch(shardID, lastBlock.Epoch)
+
+	return lastBlock.Epoch, true, nil
+}
+
+// SaveRoundsInfo will prepare and save information about a slice of rounds in elasticsearch server. The signers of
+// the rounds are resolved using the validators of the last epoch seen in the blocks of the rounds shard and are
+// aggregated in the validators statistics
+func (ei *elasticProcessor) SaveRoundsInfo(infos []*data.RoundInfo) error {
+	saveRounds := ei.isIndexEnabled(roundIndex)
+	saveStatistics := ei.isIndexEnabled(validatorsStatisticsIndex)
+	if !saveRounds && !saveStatistics {
+		return nil
+	}
+
+	for _, info := range infos {
+		epoch, ok, err := ei.getShardEpoch(info.ShardId)
+		if err != nil {
+			return err
+		}
 		if !ok {
-			log.Warn("cannot cast AccountHandler to type UserAccountHandler")
 			continue
 		}
 
-		accountsToIndex = append(accountsToIndex, userAccount)
+		signersKeys, err := ei.getSignersKeys(info.ShardId, epoch, info.SignersIndexes)
+		if err != nil {
+			return err
+		}
+		info.Epoch = epoch
+		info.SignersBlsKeys = signersKeys
+	}
+
+	if saveRounds {
+		buffSlice := ei.newBulkBuffer()
+		for _, info := range infos {
+			serializedRoundInfo, err := json.Marshal(info)
+			if err != nil {
+				log.Warn("indexer: could not serialize round info, will skip indexing this round info",
+					"error", err.Error())
+				continue
+			}
+
+			err = buffSlice.PutIndex(computeRoundID(info.ShardId, info.Index), serializedRoundInfo)
+			if err != nil {
+				log.Warn("indexer: cannot write serialized round info", "error", err.Error())
+			}
+		}
+
+		err := ei.sendBulkRequests(buffSlice, roundIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	statistics := getValidatorsStatistics(infos)
+	if !saveStatistics || len(statistics) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	err := serializeValidatorsStatistics(statistics, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, validatorsStatisticsIndex)
+}
+
+func (ei *elasticProcessor) indexAlteredAccounts(header coreData.HeaderHandler, accounts map[string]*data.AlteredAccount) error {
+	if !ei.isIndexEnabled(accountsIndex) {
+		return nil
 	}
 
-	if len(accountsToIndex) == 0 {
+	accountsSlice := ei.loadAlteredAccounts(accounts)
+	if len(accountsSlice) == 0 {
 		log.Debug("no account to index from provided transactions")
 		return nil
 	}
 
-	accountsSlice := make([]*data.Account, len(accountsToIndex))
-	for idx, account := range accountsToIndex {
-		accountsSlice[idx] = &data.Account{
-			UserAccount: account,
-			IsSender:    false,
+	return ei.saveAccounts(header.GetTimeStamp(), header.GetNonce(), accountsSlice)
+}
+
+// loadAlteredAccounts loads from the accounts adapter the provided altered accounts that belong to the current shard
+func (ei *elasticProcessor) loadAlteredAccounts(accounts map[string]*data.AlteredAccount) []*data.Account {
+	accountsSlice := make([]*data.Account, 0)
+	for address, alteredAccount := range accounts {
+		addressBytes, err := ei.addressPubkeyConverter.Decode(address)
+		if err != nil {
+			log.Warn("cannot decode address", "address", address, "error", err)
+			continue
+		}
+
+		if ei.shardCoordinator.ComputeId(addressBytes) != ei.shardCoordinator.SelfId() {
+			continue
+		}
+
+		account, err := ei.accountsDB.LoadAccount(addressBytes)
+		if err != nil {
+			log.Warn("cannot load account", "address bytes", addressBytes, "error", err)
+			continue
+		}
+
+		userAccount, ok := account.(coreData.UserAccountHandler)
+		if !ok {
+			log.Warn("cannot cast AccountHandler to type UserAccountHandler")
+			continue
 		}
+
+		accountsSlice = append(accountsSlice, &data.Account{
+			UserAccount: userAccount,
+			IsSender:    alteredAccount.IsSender,
+			TxHash:      alteredAccount.TxHash,
+		})
 	}
 
-	return ei.SaveAccounts(blockTimestamp, accountsSlice)
+	return accountsSlice
 }
 
-// SaveAccounts will prepare and save information about provided accounts in elasticsearch server
+// SaveAccounts will prepare and save information about provided accounts in elasticsearch server. The accounts saved
+// outside of a block, like the genesis accounts, are recorded in the balances history at nonce 0
 func (ei *elasticProcessor) SaveAccounts(blockTimestamp uint64, accountsSlice []*data.Account) error {
+	return ei.saveAccounts(blockTimestamp, 0, accountsSlice)
+}
+
+func (ei *elasticProcessor) saveAccounts(blockTimestamp uint64, blockNonce uint64, accountsSlice []*data.Account) error {
 	if !ei.isIndexEnabled(accountsIndex) {
 		return nil
 	}
 
+	accountsMap := ei.prepareAccountsInfo(accountsSlice)
+
+	// the previous balances are read and the history is saved before the accounts documents are overwritten. A retry
+	// after the accounts were saved finds no balance change, but the history entries of the block are already saved
+	previousBalances, err := ei.getPreviousBalances(accountsMap)
+	if err != nil {
+		return err
+	}
+
+	err = ei.saveAccountsHistory(blockTimestamp, blockNonce, accountsMap, previousBalances)
+	if err != nil {
+		return err
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	err = serializeAccounts(accountsMap, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, accountsIndex)
+}
+
+func (ei *elasticProcessor) prepareAccountsInfo(accountsSlice []*data.Account) map[string]*data.AccountInfo {
 	accountsMap := make(map[string]*data.AccountInfo)
 	for _, userAccount := range accountsSlice {
 		balanceAsFloat := ei.computeBalanceAsFloat(userAccount.UserAccount.GetBalance())
 		acc := &data.AccountInfo{
-			Nonce:      userAccount.UserAccount.GetNonce(),
-			Balance:    userAccount.UserAccount.GetBalance().String(),
-			BalanceNum: balanceAsFloat,
+			Nonce:         userAccount.UserAccount.GetNonce(),
+			Balance:       userAccount.UserAccount.GetBalance().String(),
+			BalanceNum:    balanceAsFloat,
+			BalanceScaled: ei.numericValues.computeScaled(userAccount.UserAccount.GetBalance()),
+			IsSender:      userAccount.IsSender,
+			TxHash:        userAccount.TxHash,
 		}
+		ei.addUserAccountDetails(acc, userAccount.UserAccount)
+
 		address := ei.addressPubkeyConverter.Encode(userAccount.UserAccount.AddressBytes())
 		accountsMap[address] = acc
 	}
 
-	buffSlice, err := serializeAccounts(accountsMap)
+	return accountsMap
+}
+
+// RevertAccounts will index again the accounts altered by the provided block, with the balances read from the accounts
+// adapter after the node rolled back the state. The accounts that no longer exist are removed
+func (ei *elasticProcessor) RevertAccounts(header coreData.HeaderHandler, body *block.Body) error {
+	if !ei.isIndexEnabled(accountsIndex) || !ei.isIndexEnabled(txIndex) {
+		return nil
+	}
+	if body == nil || header.GetShardID() != ei.shardCoordinator.SelfId() {
+		return nil
+	}
+
+	err := ei.FlushBatch(true)
 	if err != nil {
 		return err
 	}
-	for idx := range buffSlice {
-		err = ei.elasticClient.DoBulkRequest(&buffSlice[idx], accountsIndex)
-		if err != nil {
-			log.Warn("indexer: indexing bulk of accounts",
-				"error", err.Error())
-			return err
+
+	alteredAccounts, err := ei.getAlteredAccountsFromDatabase(body)
+	if err != nil {
+		return err
+	}
+
+	accountsMap := ei.prepareAccountsInfo(ei.loadAlteredAccounts(alteredAccounts))
+	if len(accountsMap) == 0 {
+		return nil
+	}
+
+	buffSlice := ei.newBulkBuffer()
+	for address, acc := range accountsMap {
+		if acc.Nonce == 0 && stringValueToBigInt(acc.Balance).Sign() == 0 {
+			err = buffSlice.PutDelete(address)
+			if err != nil {
+				return err
+			}
+			delete(accountsMap, address)
 		}
 	}
 
-	return ei.saveAccountsHistory(blockTimestamp, accountsMap)
+	err = serializeAccounts(accountsMap, buffSlice)
+	if err != nil {
+		return err
+	}
+
+	return ei.sendBulkRequests(buffSlice, accountsIndex)
+}
+
+// getPreviousBalances returns the indexed balances of the provided accounts. The accounts that are not indexed yet
+// are missing from the result
+func (ei *elasticProcessor) getPreviousBalances(accountsInfoMap map[string]*data.AccountInfo) (map[string]*big.Int, error) {
+	if !ei.isIndexEnabled(accountsHistoryIndex) || len(accountsInfoMap) == 0 {
+		return make(map[string]*big.Int), nil
+	}
+
+	addresses := make([]string, 0, len(accountsInfoMap))
+	for address := range accountsInfoMap {
+		addresses = append(addresses, address)
+	}
+
+	response, err := ei.elasticClient.DoMultiGet(getDocumentsByIDsQuery(addresses, true), accountsIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return getDecodedAccountsBalances(response), nil
 }
 
-func (ei *elasticProcessor) saveAccountsHistory(blockTimestamp uint64, accountsInfoMap map[string]*data.AccountInfo) error {
+// addUserAccountDetails sets the username and, for smart contracts, the code and the owner details of the provided
+// account. Nothing is added if the account does not expose them
+func (ei *elasticProcessor) addUserAccountDetails(acc *data.AccountInfo, account coreData.UserAccountHandler) {
+	userAccount, ok := account.(vmcommon.UserAccountHandler)
+	if !ok {
+		return
+	}
+
+	acc.UserName = string(userAccount.GetUserName())
+	acc.IsSmartContract = core.IsSmartContractAddress(userAccount.AddressBytes())
+	if !acc.IsSmartContract {
+		return
+	}
+
+	codeMetadata := vmcommon.CodeMetadataFromBytes(userAccount.GetCodeMetadata())
+	acc.CodeHash = hex.EncodeToString(userAccount.GetCodeHash())
+	acc.IsUpgradeable = codeMetadata.Upgradeable
+	acc.IsReadable = codeMetadata.Readable
+	acc.IsPayable = codeMetadata.Payable
+	if len(userAccount.GetOwnerAddress()) > 0 {
+		acc.OwnerAddress = ei.addressPubkeyConverter.Encode(userAccount.GetOwnerAddress())
+	}
+	if userAccount.GetDeveloperReward() != nil {
+		acc.DeveloperReward = userAccount.GetDeveloperReward().String()
+	}
+}
+
+func (ei *elasticProcessor) saveAccountsHistory(
+	blockTimestamp uint64,
+	blockNonce uint64,
+	accountsInfoMap map[string]*data.AccountInfo,
+	previousBalances map[string]*big.Int,
+) error {
 	if !ei.isIndexEnabled(accountsHistoryIndex) {
 		return nil
 	}
 
+	selfShardID := ei.shardCoordinator.SelfId()
 	accountsMap := make(map[string]*data.AccountBalanceHistory)
 	for address, userAccount := range accountsInfoMap {
+		balanceChange := computeBalanceChange(userAccount.Balance, previousBalances[address])
+		if balanceChange.Sign() == 0 {
+			continue
+		}
+
 		acc := &data.AccountBalanceHistory{
-			Address:   address,
-			Balance:   userAccount.Balance,
-			Timestamp: time.Duration(blockTimestamp),
+			Address:       address,
+			Balance:       userAccount.Balance,
+			BalanceChange: balanceChange.String(),
+			TxHash:        userAccount.TxHash,
+			ShardID:       selfShardID,
+			BlockNonce:    blockNonce,
+			Timestamp:     time.Duration(blockTimestamp),
+			IsSender:      userAccount.IsSender,
 		}
-		addressKey := fmt.Sprintf("%s_%d", address, blockTimestamp)
-		accountsMap[addressKey] = acc
+		accountsMap[computeAccountHistoryID(address, selfShardID, blockNonce)] = acc
+	}
+
+	if len(accountsMap) == 0 {
+		return nil
 	}
 
-	buffSlice, err := serializeAccountsHistory(accountsMap)
+	buffSlice := ei.newBulkBuffer()
+	err := serializeAccountsHistory(accountsMap, buffSlice)
 	if err != nil {
 		return err
 	}
-	for idx := range buffSlice {
-		err = ei.elasticClient.DoBulkRequest(&buffSlice[idx], accountsHistoryIndex)
-		if err != nil {
-			log.Warn("indexer: indexing bulk of accounts history",
-				"error", err.Error())
-			return err
-		}
+
+	return ei.sendBulkRequests(buffSlice, accountsHistoryIndex)
+}
+
+// RevertAccountsHistory will remove the balances history entries created by the provided block
+func (ei *elasticProcessor) RevertAccountsHistory(header coreData.HeaderHandler) error {
+	if !ei.isIndexEnabled(accountsHistoryIndex) {
+		return nil
 	}
 
-	return nil
+	err := ei.FlushBatch(true)
+	if err != nil {
+		return err
+	}
+
+	return ei.elasticClient.DoDeleteByQuery(getBlockEntriesQuery(header.GetShardID(), header.GetNonce()), accountsHistoryIndex)
+}
+
+// getRefreshPolicy returns the configured refresh policy for the provided index. An empty policy means that the
+// request will use the elasticsearch default, which does not trigger any refresh
+func (ei *elasticProcessor) getRefreshPolicy(index string) string {
+	return ei.refreshPolicies[index]
 }
 
 func (ei *elasticProcessor) isIndexEnabled(index string) bool {
@@ -613,13 +1668,7 @@ func (ei *elasticProcessor) isIndexEnabled(index string) bool {
 }
 
 func (ei *elasticProcessor) computeBalanceAsFloat(balance *big.Int) float64 {
-	balanceBigFloat := big.NewFloat(0).SetInt(balance)
-	balanceFloat64, _ := balanceBigFloat.Float64()
-
-	bal := balanceFloat64 / ei.dividerForDenomination
-	balanceFloatWithDecimals := math.Round(bal*ei.balancePrecision) / ei.balancePrecision
-
-	return core.MaxFloat64(balanceFloatWithDecimals, 0)
+	return core.MaxFloat64(ei.numericValues.computeFloat(balance), 0)
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -9,7 +9,9 @@ import (
 	"io/ioutil"
 	"math/big"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ElrondNetwork/elastic-indexer-go/data"
 	"github.com/ElrondNetwork/elastic-indexer-go/mock"
@@ -21,6 +23,7 @@ import (
 	"github.com/ElrondNetwork/elrond-go-core/data/rewardTx"
 	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
 	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/stretchr/testify/assert"
@@ -45,6 +48,8 @@ func newTestElasticSearchDatabase(elasticsearchWriter DatabaseClientHandler, arg
 		},
 		enabledIndexes: arguments.EnabledIndexes,
 		accountsDB:     arguments.AccountsDB,
+		blocksBatcher:  newBlocksBatcher(arguments.BlocksBatchSize, arguments.BlocksBatchMaxBytes, arguments.BlocksBatchMaxAge, createBulkBuffer),
+		validatorsKeys: newValidatorsKeysHolder(),
 	}
 }
 
@@ -134,6 +139,15 @@ func TestNewElasticProcessorWithKibana(t *testing.T) {
 	require.NotNil(t, elasticProc)
 }
 
+func TestNewElasticProcessor_InvalidRefreshPolicy(t *testing.T) {
+	args := createMockElasticProcessorArgs()
+	args.RefreshPolicies = map[string]string{blockIndex: "always"}
+
+	elasticProc, err := NewElasticProcessor(args)
+	require.True(t, errors.Is(err, ErrInvalidRefreshPolicy))
+	require.Nil(t, elasticProc)
+}
+
 func TestElasticProcessor_RemoveHeader(t *testing.T) {
 	called := false
 
@@ -228,7 +242,7 @@ func TestElasticSearchDatabaseSaveHeader_RequestError(t *testing.T) {
 	}
 	elasticDatabase := newTestElasticSearchDatabase(dbWriter, arguments)
 
-	err := elasticDatabase.SaveHeader(header, signerIndexes, &dataBlock.Body{}, nil, 1)
+	err := elasticDatabase.SaveHeader(header, signerIndexes, &dataBlock.Body{}, nil, &indexer.Pool{}, 1)
 	require.Equal(t, localErr, err)
 }
 
@@ -268,15 +282,116 @@ func TestElasticSearchDatabaseSaveHeader_CheckRequestBody(t *testing.T) {
 	}
 
 	elasticDatabase := newTestElasticSearchDatabase(dbWriter, arguments)
-	err := elasticDatabase.SaveHeader(header, signerIndexes, blockBody, nil, 1)
+	err := elasticDatabase.SaveHeader(header, signerIndexes, blockBody, nil, &indexer.Pool{}, 1)
+	require.Nil(t, err)
+}
+
+func TestElasticSearchDatabaseSaveHeader_UsesRefreshPolicy(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+	arguments.RefreshPolicies, _ = CreateRefreshPolicies(SyncRefreshMode)
+
+	called := false
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoRequestCalled: func(req *esapi.IndexRequest) error {
+			called = true
+			require.Equal(t, RefreshPolicyFalse, req.Refresh)
+			return nil
+		},
+	}
+
+	elasticProc, err := NewElasticProcessor(arguments)
+	require.Nil(t, err)
+
+	err = elasticProc.SaveHeader(&dataBlock.Header{Nonce: 1}, []uint64{0}, &dataBlock.Body{}, nil, &indexer.Pool{}, 1)
+	require.Nil(t, err)
+	require.True(t, called)
+}
+
+func TestElasticProcessor_SaveHeaderAndMiniblocksWithBatching(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+	arguments.BlocksBatchSize = 2
+
+	mb := &dataBlock.MiniBlock{SenderShardID: 0, ReceiverShardID: 1}
+	mbHash, _ := core.CalculateHash(arguments.Marshalizer, arguments.Hasher, mb)
+	encodedMbHash := hex.EncodeToString(mbHash)
+
+	flushedIndexes := make([]string, 0)
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoRequestCalled: func(req *esapi.IndexRequest) error {
+			require.Fail(t, "blocks should be batched")
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, refresh string) error {
+			flushedIndexes = append(flushedIndexes, index)
+			return nil
+		},
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	elasticProc, err := NewElasticProcessor(arguments)
+	require.Nil(t, err)
+
+	body := &dataBlock.Body{MiniBlocks: dataBlock.MiniBlockSlice{mb}}
+	err = elasticProc.SaveHeader(&dataBlock.Header{Nonce: 1}, nil, body, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+	mbsInDb, err := elasticProc.SaveMiniblocks(&dataBlock.Header{Nonce: 1}, body)
+	require.Nil(t, err)
+	require.False(t, mbsInDb[encodedMbHash])
+
+	err = elasticProc.FlushBatch(false)
+	require.Nil(t, err)
+	require.Len(t, flushedIndexes, 0)
+
+	// the miniblock waits in the batch so it should be considered as already indexed
+	err = elasticProc.SaveHeader(&dataBlock.Header{Nonce: 2, ShardID: 1}, nil, body, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+	mbsInDb, err = elasticProc.SaveMiniblocks(&dataBlock.Header{Nonce: 2, ShardID: 1}, body)
+	require.Nil(t, err)
+	require.True(t, mbsInDb[encodedMbHash])
+
+	err = elasticProc.FlushBatch(false)
+	require.Nil(t, err)
+	require.Equal(t, []string{blockIndex, miniblocksIndex}, flushedIndexes)
+}
+
+func TestElasticProcessor_SaveHeaderRetriedWithBatchingShouldCountTheBlockOnce(t *testing.T) {
+	arguments := createMockElasticProcessorArgs()
+	arguments.BlocksBatchSize = 2
+
+	flushed := make([]string, 0)
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, refresh string) error {
+			flushed = append(flushed, buff.String())
+			return nil
+		},
+	}
+
+	elasticProc, err := NewElasticProcessor(arguments)
+	require.Nil(t, err)
+
+	header := &dataBlock.Header{Nonce: 1}
+	err = elasticProc.SaveHeader(header, nil, &dataBlock.Body{}, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+	err = elasticProc.SaveHeader(header, nil, &dataBlock.Body{}, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+
+	err = elasticProc.FlushBatch(false)
+	require.Nil(t, err)
+	require.Len(t, flushed, 0)
+
+	err = elasticProc.FlushBatch(true)
 	require.Nil(t, err)
+	require.Len(t, flushed, 1)
+	require.Equal(t, 1, strings.Count(flushed[0], `{"index":`))
 }
 
 func TestElasticSearchSaveTransactions(t *testing.T) {
 	localErr := errors.New("localErr")
 	arguments := createMockElasticProcessorArgs()
 	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, refresh string) error {
 			return localErr
 		},
 	}
@@ -295,15 +410,20 @@ func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 	localErr := errors.New("localErr")
 
 	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[ratingHistoryIndex] = struct{}{}
+	bulkRequests := make(map[string]string)
 	arguments.DBClient = &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
-			require.Equal(t, docID, req.DocumentID)
-
-			return localErr
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
 		},
 	}
 
 	elasticProc, _ := NewElasticProcessor(arguments)
+	elasticProc.(*elasticProcessor).validatorsKeys.setEpochStartTimestamp(1, 5000)
+	elasticProc.(*elasticProcessor).getCurrentTimestamp = func() time.Duration {
+		return 6000
+	}
 
 	err := elasticProc.SaveValidatorsRating(
 		docID,
@@ -314,7 +434,66 @@ func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 			},
 		},
 	)
+	require.Nil(t, err)
+	require.Contains(t, bulkRequests[ratingIndex], `{"index":{"_id":"blablabla_0_1"}}`)
+	require.Contains(t, bulkRequests[ratingIndex], `"publicKey":"blablabla","shardId":0,"epoch":1,"rating":100,"timestamp":5000`)
+	require.Contains(t, bulkRequests[ratingHistoryIndex], `{"index":{"_id":"blablabla_0_1_6000"}}`)
+	require.Contains(t, bulkRequests[ratingHistoryIndex], `"publicKey":"blablabla","shardId":0,"epoch":1,"rating":100,"timestamp":6000`)
+	require.Contains(t, bulkRequests[validatorsIndex], `"upsert" : {"blsKey":"blablabla","rating":100,"type":"validator"}`)
+
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string, _ string) error {
+			return localErr
+		},
+	}
+	elasticProc, _ = NewElasticProcessor(arguments)
+	elasticProc.(*elasticProcessor).validatorsKeys.setEpochStartTimestamp(1, 5000)
+
+	err = elasticProc.SaveValidatorsRating(docID, []*data.ValidatorRatingInfo{{PublicKey: "blablabla"}})
 	require.Equal(t, localErr, err)
+
+	err = elasticProc.SaveValidatorsRating("invalid", []*data.ValidatorRatingInfo{{PublicKey: "blablabla"}})
+	require.True(t, errors.Is(err, ErrInvalidRatingIndexID))
+}
+
+func TestElasticProcessor_SaveValidatorsRatingAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	found := true
+	bulkRequests := make(map[string]string)
+	arguments := createMockElasticProcessorArgs()
+	arguments.DBClient = &mock.DatabaseWriterStub{
+		DoSearchCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, blockIndex, index)
+			require.Equal(t, getEpochStartBlockQuery(1), objectsMap(query))
+			if !found {
+				return map[string]interface{}{"hits": map[string]interface{}{"hits": []interface{}{}}}, nil
+			}
+			return map[string]interface{}{
+				"hits": map[string]interface{}{
+					"hits": []interface{}{
+						map[string]interface{}{"_id": "hash", "_source": map[string]interface{}{"epoch": 1, "timestamp": 5000}},
+					},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+
+	elasticProc, _ := NewElasticProcessor(arguments)
+	err := elasticProc.SaveValidatorsRating("0_1", []*data.ValidatorRatingInfo{{PublicKey: "blablabla", Rating: 100}})
+	require.Nil(t, err)
+	require.Contains(t, bulkRequests[ratingIndex], `"publicKey":"blablabla","shardId":0,"epoch":1,"rating":100,"timestamp":5000`)
+
+	found = false
+	bulkRequests = make(map[string]string)
+	elasticProc, _ = NewElasticProcessor(arguments)
+	err = elasticProc.SaveValidatorsRating("0_1", []*data.ValidatorRatingInfo{{PublicKey: "blablabla", Rating: 100}})
+	require.True(t, errors.Is(err, ErrUnknownEpochStartTimestamp))
+	require.Empty(t, bulkRequests)
 }
 
 func TestElasticProcessor_SaveMiniblocks(t *testing.T) {
@@ -322,7 +501,7 @@ func TestElasticProcessor_SaveMiniblocks(t *testing.T) {
 
 	arguments := createMockElasticProcessorArgs()
 	arguments.DBClient = &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, refresh string) error {
 			return localErr
 		},
 		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
@@ -348,7 +527,7 @@ func TestElasticsearch_saveShardValidatorsPubKeys_RequestError(t *testing.T) {
 	localErr := errors.New("localErr")
 	arguments := createMockElasticProcessorArgs()
 	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
 			return localErr
 		},
 	}
@@ -359,13 +538,24 @@ func TestElasticsearch_saveShardValidatorsPubKeys_RequestError(t *testing.T) {
 }
 
 func TestElasticsearch_saveShardValidatorsPubKeys(t *testing.T) {
-	shardID := uint32(0)
-	epoch := uint32(0)
+	shardID := uint32(1)
+	epoch := uint32(2)
 	valPubKeys := [][]byte{[]byte("key1"), []byte("key2")}
 	arguments := createMockElasticProcessorArgs()
+	bulkRequest := ""
+	updateByQueryCalled := false
 	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
-			require.Equal(t, fmt.Sprintf("%d_%d", shardID, epoch), req.DocumentID)
+		DoUpdateByQueryCalled: func(query map[string]interface{}, index string) error {
+			require.Equal(t, validatorsIndex, index)
+			require.Equal(t, getPreviousEligibleValidatorsQuery(1, 2), objectsMap(query))
+			require.False(t, updateByQueryCalled)
+			require.Empty(t, bulkRequest)
+			updateByQueryCalled = true
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			require.Equal(t, validatorsIndex, index)
+			bulkRequest = buff.String()
 			return nil
 		},
 	}
@@ -373,6 +563,13 @@ func TestElasticsearch_saveShardValidatorsPubKeys(t *testing.T) {
 
 	err := elasticDatabase.SaveShardValidatorsPubKeys(shardID, epoch, valPubKeys)
 	require.Nil(t, err)
+	require.True(t, updateByQueryCalled)
+	require.Contains(t, bulkRequest, `{"index":{"_id":"1_2"}}`+"\n"+
+		`{"publicKeys":["`+hex.EncodeToString([]byte("key1"))+`","`+hex.EncodeToString([]byte("key2"))+`"]}`)
+	require.Contains(t, bulkRequest, `{"update":{"_id":"`+hex.EncodeToString([]byte("key1"))+`"}}`)
+	require.Contains(t, bulkRequest, `{"update":{"_id":"`+hex.EncodeToString([]byte("key2"))+`"}}`)
+	require.Contains(t, bulkRequest, `"upsert" : {"blsKey":"`+hex.EncodeToString([]byte("key2"))+`","epoch":2,"list":"eligible",`+
+		`"previousState":{"epoch":null,"list":null,"shardId":null},"shardId":1,"type":"validator"}`)
 }
 
 func TestElasticsearch_saveRoundInfo(t *testing.T) {
@@ -397,7 +594,7 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 	localError := errors.New("local err")
 	arguments := createMockElasticProcessorArgs()
 	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, refresh string) error {
 			return localError
 		},
 	}
@@ -794,3 +991,375 @@ func TestElasticProcessor_ComputeBalanceAsFloat(t *testing.T) {
 		assert.Equal(t, tt.output, out)
 	}
 }
+
+func TestElasticProcessor_UpdateNotarizedTransactions(t *testing.T) {
+	t.Parallel()
+
+	queries := make([]map[string]interface{}, 0)
+	miniblocksOps := ""
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoUpdateByQueryCalled: func(query map[string]interface{}, index string) error {
+			require.Equal(t, txIndex, index)
+			queries = append(queries, query)
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			require.Equal(t, miniblocksIndex, index)
+			miniblocksOps = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.UpdateNotarizedTransactions(&dataBlock.Header{Nonce: 5}, []string{"h1"})
+	require.Nil(t, err)
+	require.Empty(t, queries)
+
+	metaBlock := &dataBlock.MetaBlock{
+		Nonce: 5,
+		ShardInfo: []dataBlock.ShardData{
+			{
+				HeaderHash: []byte("h1"),
+				ShardID:    1,
+				ShardMiniBlockHeaders: []dataBlock.MiniBlockHeader{
+					{Hash: []byte("intra"), SenderShardID: 1, ReceiverShardID: 1},
+					{Hash: []byte("out"), SenderShardID: 1, ReceiverShardID: 2},
+					{Hash: []byte("in"), SenderShardID: 0, ReceiverShardID: 1},
+				},
+			},
+			{
+				HeaderHash:            []byte("notNotarized"),
+				ShardID:               2,
+				ShardMiniBlockHeaders: []dataBlock.MiniBlockHeader{{Hash: []byte("other"), SenderShardID: 2, ReceiverShardID: 2}},
+			},
+		},
+	}
+	err = elasticProc.UpdateNotarizedTransactions(metaBlock, []string{hex.EncodeToString([]byte("h1"))})
+	require.Nil(t, err)
+
+	intra, out, in := hex.EncodeToString([]byte("intra")), hex.EncodeToString([]byte("out")), hex.EncodeToString([]byte("in"))
+	require.Equal(t, []map[string]interface{}{
+		getNotarizedTransactionsQuery([]string{intra, out}, notarizedAtSourceField, 5),
+		getNotarizedTransactionsQuery([]string{intra, in}, notarizedAtDestinationField, 5),
+	}, queries)
+	require.Contains(t, miniblocksOps, `{"update":{"_id":"`+out+`"}}`+"\n"+
+		`{ "script" : {"lang":"painless","params":{"field":"notarizedAtSourceInMetaNonce","nonce":5},"source":"`+setNotarizationScript+`"}, `+
+		`"upsert" : {"notarizedAtSourceInMetaNonce":5} }`)
+	require.Contains(t, miniblocksOps, `"upsert" : {"notarizedAtDestinationInMetaNonce":5} }`)
+	require.NotContains(t, miniblocksOps, hex.EncodeToString([]byte("other")))
+}
+
+func TestElasticProcessor_UpdateLateNotarizedTransactions(t *testing.T) {
+	t.Parallel()
+
+	txsOps := ""
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			if index != miniblocksIndex {
+				return map[string]interface{}{}, nil
+			}
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": "notarized", "found": true, "_source": map[string]interface{}{"notarizedAtSourceInMetaNonce": 7}},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			if index == txIndex {
+				txsOps += buff.String()
+			}
+			return nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	txs := []*data.Transaction{{Hash: "late", MBHash: "notarized"}, {Hash: "other", MBHash: "notNotarized"}}
+	err = elasticProc.updateLateNotarizedTransactions(txs, 0)
+	require.Nil(t, err)
+	require.Equal(t, `{"update":{"_id":"late"}}`+"\n"+`{ "doc" : {"notarizedAtSourceInMetaNonce":7} }`+"\n", txsOps)
+}
+
+func TestElasticProcessor_SaveAccountsWithDetails(t *testing.T) {
+	t.Parallel()
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	scAddress := []byte{0, 0, 0, 0, 0, 0, 0, 0, 5, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22}
+	err = elasticProc.SaveAccounts(0, []*data.Account{
+		{UserAccount: &mock.UserAccountStub{Address: []byte("user"), Balance: big.NewInt(10), Nonce: 2, UserName: []byte("alice.elrond")}},
+		{UserAccount: &mock.UserAccountStub{
+			Address:         scAddress,
+			CodeHash:        []byte("code"),
+			CodeMetadata:    []byte{vmcommon.MetadataUpgradeable | vmcommon.MetadataReadable, vmcommon.MetadataPayable},
+			OwnerAddress:    []byte("owner"),
+			DeveloperReward: big.NewInt(7),
+		}},
+	})
+	require.Nil(t, err)
+
+	accountsOps := bulkRequests[accountsIndex]
+	require.Contains(t, accountsOps, `{"index":{"_id":"`+hex.EncodeToString([]byte("user"))+`"}}`+"\n"+
+		`{"nonce":2,"balance":"10","balanceNum":10,"userName":"alice.elrond"}`)
+	require.Contains(t, accountsOps, `{"index":{"_id":"`+hex.EncodeToString(scAddress)+`"}}`+"\n"+
+		`{"balance":"0","balanceNum":0,"isSmartContract":true,"codeHash":"`+hex.EncodeToString([]byte("code"))+`",`+
+		`"isUpgradeable":true,"isReadable":true,"isPayable":true,"ownerAddress":"`+hex.EncodeToString([]byte("owner"))+`","developerReward":"7"}`)
+}
+
+func TestElasticProcessor_SaveAccountsHistoryPerBlock(t *testing.T) {
+	t.Parallel()
+
+	changed := hex.EncodeToString([]byte("changed"))
+	unchanged := hex.EncodeToString([]byte("unchanged"))
+	created := hex.EncodeToString([]byte("created"))
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, accountsIndex, index)
+			require.Empty(t, bulkRequests)
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": changed, "found": true, "_source": map[string]interface{}{"balance": "15"}},
+					map[string]interface{}{"_id": unchanged, "found": true, "_source": map[string]interface{}{"balance": "7"}},
+					map[string]interface{}{"_id": created, "found": false},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	args.ShardCoordinator = &mock.ShardCoordinatorMock{SelfID: 1}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	err = elasticProc.saveAccounts(100, 5, []*data.Account{
+		{UserAccount: &mock.UserAccountStub{Address: []byte("changed"), Balance: big.NewInt(10)}, IsSender: true, TxHash: "h1"},
+		{UserAccount: &mock.UserAccountStub{Address: []byte("unchanged"), Balance: big.NewInt(7)}, TxHash: "h2"},
+		{UserAccount: &mock.UserAccountStub{Address: []byte("created"), Balance: big.NewInt(3)}, TxHash: "h3"},
+	})
+	require.Nil(t, err)
+
+	historyOps := bulkRequests[accountsHistoryIndex]
+	require.Contains(t, historyOps, `{"index":{"_id":"`+changed+`_1_5"}}`+"\n"+
+		`{"address":"`+changed+`","timestamp":100,"balance":"10","balanceChange":"-5","txHash":"h1","shardId":1,"blockNonce":5,"isSender":true}`)
+	require.Contains(t, historyOps, `{"index":{"_id":"`+created+`_1_5"}}`+"\n"+
+		`{"address":"`+created+`","timestamp":100,"balance":"3","balanceChange":"3","txHash":"h3","shardId":1,"blockNonce":5}`)
+	require.NotContains(t, historyOps, unchanged)
+	require.Contains(t, bulkRequests[accountsIndex], unchanged)
+}
+
+func TestElasticProcessor_SaveAccountsHistoryBeforeAccounts(t *testing.T) {
+	t.Parallel()
+
+	changed := hex.EncodeToString([]byte("changed"))
+	savedIndexes := make([]string, 0)
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": changed, "found": true, "_source": map[string]interface{}{"balance": "15"}},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			savedIndexes = append(savedIndexes, index)
+			if index == accountsIndex {
+				return errors.New("local err")
+			}
+			return nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	err = elasticProc.saveAccounts(100, 5, []*data.Account{
+		{UserAccount: &mock.UserAccountStub{Address: []byte("changed"), Balance: big.NewInt(10)}, TxHash: "h1"},
+	})
+	require.NotNil(t, err)
+	require.Equal(t, []string{accountsHistoryIndex, accountsIndex}, savedIndexes)
+}
+
+func TestElasticProcessor_RevertAccountsHistory(t *testing.T) {
+	t.Parallel()
+
+	var deleteQuery map[string]interface{}
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoDeleteByQueryCalled: func(query map[string]interface{}, index string) error {
+			require.Equal(t, accountsHistoryIndex, index)
+			deleteQuery = query
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.RevertAccountsHistory(&dataBlock.Header{ShardID: 2, Nonce: 9})
+	require.Nil(t, err)
+	require.Equal(t, getBlockEntriesQuery(2, 9), objectsMap(deleteQuery))
+}
+
+func TestElasticProcessor_RevertAccounts(t *testing.T) {
+	t.Parallel()
+
+	sender := hex.EncodeToString([]byte("sender"))
+	receiver := hex.EncodeToString([]byte("receiver"))
+	scrReceiver := hex.EncodeToString([]byte("scrReceiver"))
+	tx := &data.Transaction{
+		Hash:                 hex.EncodeToString([]byte("tx")),
+		Sender:               sender,
+		Receiver:             receiver,
+		Status:               transaction.TxStatusSuccess.String(),
+		SmartContractResults: []data.ScResult{{Receiver: scrReceiver}},
+	}
+	serializedTx, _ := json.Marshal(tx)
+	source := make(map[string]interface{})
+	_ = json.Unmarshal(serializedTx, &source)
+
+	bulkRequest := ""
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, txIndex, index)
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": hex.EncodeToString([]byte("tx")), "found": true, "_source": source},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			require.Equal(t, accountsIndex, index)
+			bulkRequest = buff.String()
+			return nil
+		},
+	}
+	args.AccountsDB = &mock.AccountsStub{
+		LoadAccountCalled: func(address []byte) (vmcommon.AccountHandler, error) {
+			if string(address) == "receiver" {
+				return &mock.UserAccountStub{Address: address}, nil
+			}
+			return &mock.UserAccountStub{Address: address, Balance: big.NewInt(5), Nonce: 1}, nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	body := &dataBlock.Body{
+		MiniBlocks: []*dataBlock.MiniBlock{
+			{Type: dataBlock.TxBlock, TxHashes: [][]byte{[]byte("tx")}},
+		},
+	}
+	err = elasticProc.RevertAccounts(&dataBlock.Header{}, body)
+	require.Nil(t, err)
+
+	require.Contains(t, bulkRequest, `{"delete":{"_id":"`+receiver+`"}}`)
+	require.Contains(t, bulkRequest, `{"index":{"_id":"`+sender+`"}}`+"\n"+`{"nonce":1,"balance":"5","balanceNum":5}`)
+	require.Contains(t, bulkRequest, `{"index":{"_id":"`+scrReceiver+`"}}`+"\n"+`{"nonce":1,"balance":"5","balanceNum":5}`)
+}
+
+func TestElasticProcessor_RemoveRoundsInfo(t *testing.T) {
+	t.Parallel()
+
+	var removedIDs []string
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRemoveCalled: func(index string, hashes []string) error {
+			require.Equal(t, roundIndex, index)
+			removedIDs = hashes
+			return nil
+		},
+		DoDeleteByQueryCalled: func(_ map[string]interface{}, _ string) error {
+			require.Fail(t, "the rounds should be removed by their ids")
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.RemoveRoundsInfo(&dataBlock.Header{ShardID: 1, Round: 20})
+	require.Nil(t, err)
+	require.Equal(t, []string{"1_20"}, removedIDs)
+}
+
+func TestElasticProcessor_RemoveEpochData(t *testing.T) {
+	t.Parallel()
+
+	deletedIndexes := make([]string, 0)
+	removedIDs := make([]string, 0)
+	revertedEpochs := 0
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[validatorsStatisticsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoDeleteByQueryCalled: func(query map[string]interface{}, index string) error {
+			require.Equal(t, getEpochDocumentsQuery(3), objectsMap(query))
+			deletedIndexes = append(deletedIndexes, index)
+			return nil
+		},
+		DoBulkRemoveCalled: func(index string, hashes []string) error {
+			require.Equal(t, validatorsIndex, index)
+			removedIDs = append(removedIDs, hashes...)
+			return nil
+		},
+		DoUpdateByQueryCalled: func(query map[string]interface{}, index string) error {
+			require.Equal(t, validatorsIndex, index)
+			require.Equal(t, getRevertEpochValidatorsQuery(3), objectsMap(query))
+			revertedEpochs++
+			return nil
+		},
+	}
+	args.ShardCoordinator = &mock.ShardCoordinatorMock{SelfID: core.MetachainShardId, NumOfShards: 2}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+	elasticProc.validatorsKeys.addList(0, 3, []string{"key"})
+	elasticProc.validatorsKeys.addList(0, 2, []string{"key"})
+	elasticProc.validatorsKeys.setEpoch(0, 3)
+	elasticProc.validatorsKeys.setEpoch(1, 2)
+
+	err = elasticProc.RemoveEpochData(&dataBlock.MetaBlock{Epoch: 3})
+	require.Nil(t, err)
+	require.Empty(t, deletedIndexes)
+
+	err = elasticProc.RemoveEpochData(&dataBlock.Header{Epoch: 3, EpochStartMetaHash: []byte("meta")})
+	require.Nil(t, err)
+	require.Empty(t, deletedIndexes)
+
+	epochStart := &dataBlock.MetaBlock{
+		Epoch:      3,
+		EpochStart: dataBlock.EpochStart{LastFinalizedHeaders: []dataBlock.EpochStartShardData{{}}},
+	}
+	err = elasticProc.RemoveEpochData(epochStart)
+	require.Nil(t, err)
+	require.Equal(t, []string{ratingIndex, validatorsStatisticsIndex}, deletedIndexes)
+	require.Equal(t, []string{computeValidatorsListID(core.MetachainShardId, 3), "0_3", "1_3"}, removedIDs)
+	require.Equal(t, 1, revertedEpochs)
+
+	epoch, _ := elasticProc.validatorsKeys.getEpoch(0)
+	require.Equal(t, uint32(2), epoch)
+	epoch, _ = elasticProc.validatorsKeys.getEpoch(1)
+	require.Equal(t, uint32(2), epoch)
+
+	_, ok := elasticProc.validatorsKeys.getList(0, 3)
+	require.False(t, ok)
+	_, ok = elasticProc.validatorsKeys.getList(0, 2)
+	require.True(t, ok)
+}
@@ -39,3 +39,39 @@ var ErrEmptyEnabledIndexes = errors.New("empty enabled indexes slice")
 
 // ErrNilShardCoordinator signals that a nil shard coordinator was provided
 var ErrNilShardCoordinator = errors.New("nil shard coordinator")
+
+// ErrInvalidRefreshPolicy signals that an invalid refresh policy has been provided
+var ErrInvalidRefreshPolicy = errors.New("invalid refresh policy")
+
+// ErrInvalidRefreshMode signals that an invalid refresh mode has been provided
+var ErrInvalidRefreshMode = errors.New("invalid refresh mode")
+
+// ErrNegativeBatchLimit signals that a negative limit has been provided for the blocks batch
+var ErrNegativeBatchLimit = errors.New("negative blocks batch limit")
+
+// ErrInvalidCACertificate signals that the provided CA certificate file does not contain any valid PEM certificate
+var ErrInvalidCACertificate = errors.New("invalid CA certificate")
+
+// ErrIncompleteClientCertificate signals that only one of the client certificate and client key files has been provided
+var ErrIncompleteClientCertificate = errors.New("both the client certificate and the client key files should be provided")
+
+// ErrMultipleAuthMethods signals that more than one authentication method has been configured for the elastic client
+var ErrMultipleAuthMethods = errors.New("only one of basic auth, api key or bearer token should be provided")
+
+// ErrNegativeConnectionLimit signals that a negative limit has been provided for the idle connections of the elastic client
+var ErrNegativeConnectionLimit = errors.New("negative connection limit")
+
+// ErrNegativeBulkLimit signals that a negative limit has been provided for the bulk requests
+var ErrNegativeBulkLimit = errors.New("negative bulk request limit")
+
+// ErrCannotCastAccountHandlerToUserAccount signals that an account handler cannot be cast to a user account handler
+var ErrCannotCastAccountHandlerToUserAccount = errors.New("cannot cast account handler to user account handler")
+
+// ErrInvalidScDeploy signals that the address of a deployed smart contract cannot be computed
+var ErrInvalidScDeploy = errors.New("invalid smart contract deploy")
+
+// ErrInvalidRatingIndexID signals that the provided validators rating index ID does not have the shard_epoch format
+var ErrInvalidRatingIndexID = errors.New("invalid validators rating index ID")
+
+// ErrUnknownEpochStartTimestamp signals that the epoch start block of the saved validators ratings was not indexed
+var ErrUnknownEpochStartTimestamp = errors.New("the timestamp of the epoch start block is not known")
@@ -0,0 +1,185 @@
+package factory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	indexer "github.com/ElrondNetwork/elastic-indexer-go"
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+const (
+	headerAuthorization   = "Authorization"
+	headerContentEncoding = "Content-Encoding"
+	gzipEncoding          = "gzip"
+	defaultKeepAlive      = 30 * time.Second
+)
+
+func createElasticConfig(args *ArgsIndexerFactory) (elasticsearch.Config, error) {
+	err := checkAuthArgs(args)
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+
+	transport, err := createTransport(args)
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+
+	cfg := elasticsearch.Config{
+		Addresses: []string{args.Url},
+		Username:  args.UserName,
+		Password:  args.Password,
+		APIKey:    args.APIKey,
+		Transport: transport,
+	}
+	if args.BearerToken != "" {
+		cfg.Header = http.Header{}
+		cfg.Header.Set(headerAuthorization, "Bearer "+args.BearerToken)
+	}
+
+	return cfg, nil
+}
+
+func checkAuthArgs(args *ArgsIndexerFactory) error {
+	numAuthMethods := 0
+	if args.UserName != "" || args.Password != "" {
+		numAuthMethods++
+	}
+	if args.APIKey != "" {
+		numAuthMethods++
+	}
+	if args.BearerToken != "" {
+		numAuthMethods++
+	}
+	if numAuthMethods > 1 {
+		return indexer.ErrMultipleAuthMethods
+	}
+
+	return nil
+}
+
+// createTransport will create the http transport used by the elastic client. The zero values of the
+// transport arguments keep the defaults of the http package
+func createTransport(args *ArgsIndexerFactory) (http.RoundTripper, error) {
+	tlsConfig, err := createTLSConfig(args)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if args.MaxIdleConns > 0 {
+		transport.MaxIdleConns = args.MaxIdleConns
+	}
+	if args.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = args.MaxIdleConnsPerHost
+	}
+	if args.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = args.IdleConnTimeout
+	}
+	if args.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = args.ResponseHeaderTimeout
+	}
+	if args.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   args.DialTimeout,
+			KeepAlive: defaultKeepAlive,
+		}).DialContext
+	}
+
+	if !args.CompressRequestBody {
+		return transport, nil
+	}
+
+	return &gzipRoundTripper{transport: transport}, nil
+}
+
+func createTLSConfig(args *ArgsIndexerFactory) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: args.InsecureSkipVerify,
+	}
+
+	if args.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(args.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("%w: %s", indexer.ErrInvalidCACertificate, args.CACertFile)
+		}
+	}
+
+	hasClientCert := args.ClientCertFile != ""
+	hasClientKey := args.ClientKeyFile != ""
+	if hasClientCert != hasClientKey {
+		return nil, indexer.ErrIncompleteClientCertificate
+	}
+	if hasClientCert {
+		clientCert, err := tls.LoadX509KeyPair(args.ClientCertFile, args.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// gzipRoundTripper compresses the body of every request before sending it with the wrapped transport
+type gzipRoundTripper struct {
+	transport http.RoundTripper
+}
+
+// RoundTrip compresses the request body and executes the request
+func (grt *gzipRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get(headerContentEncoding) != "" {
+		return grt.transport.RoundTrip(req)
+	}
+
+	compressedBody, err := compressBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	compressedReq := req.Clone(req.Context())
+	compressedReq.Body = ioutil.NopCloser(bytes.NewReader(compressedBody))
+	compressedReq.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressedBody)), nil
+	}
+	compressedReq.ContentLength = int64(len(compressedBody))
+	compressedReq.Header.Set(headerContentEncoding, gzipEncoding)
+
+	return grt.transport.RoundTrip(compressedReq)
+}
+
+func compressBody(req *http.Request) ([]byte, error) {
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	buff := &bytes.Buffer{}
+	writer := gzip.NewWriter(buff)
+	_, err := io.Copy(writer, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
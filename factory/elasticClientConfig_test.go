@@ -0,0 +1,113 @@
+package factory
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	indexer "github.com/ElrondNetwork/elastic-indexer-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateElasticConfig_BearerToken(t *testing.T) {
+	t.Parallel()
+
+	args := createMockIndexerFactoryArgs()
+	args.BearerToken = "token"
+
+	cfg, err := createElasticConfig(args)
+	require.Nil(t, err)
+	require.Equal(t, "Bearer token", cfg.Header.Get(headerAuthorization))
+	require.Equal(t, []string{args.Url}, cfg.Addresses)
+}
+
+func TestCreateElasticConfig_MultipleAuthMethodsShouldErr(t *testing.T) {
+	t.Parallel()
+
+	args := createMockIndexerFactoryArgs()
+	args.APIKey = "key"
+	args.BearerToken = "token"
+
+	_, err := createElasticConfig(args)
+	require.Equal(t, indexer.ErrMultipleAuthMethods, err)
+}
+
+func TestCreateTransport_ConnectionSettings(t *testing.T) {
+	t.Parallel()
+
+	args := createMockIndexerFactoryArgs()
+	args.MaxIdleConns = 10
+	args.MaxIdleConnsPerHost = 5
+	args.IdleConnTimeout = time.Minute
+	args.ResponseHeaderTimeout = time.Second
+	args.InsecureSkipVerify = true
+
+	transport, err := createTransport(args)
+	require.Nil(t, err)
+
+	httpTransport, ok := transport.(*http.Transport)
+	require.True(t, ok)
+	require.Equal(t, 10, httpTransport.MaxIdleConns)
+	require.Equal(t, 5, httpTransport.MaxIdleConnsPerHost)
+	require.Equal(t, time.Minute, httpTransport.IdleConnTimeout)
+	require.Equal(t, time.Second, httpTransport.ResponseHeaderTimeout)
+	require.True(t, httpTransport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestCreateTransport_TLSFilesErrors(t *testing.T) {
+	t.Parallel()
+
+	args := createMockIndexerFactoryArgs()
+	args.ClientCertFile = "client.crt"
+	_, err := createTransport(args)
+	require.Equal(t, indexer.ErrIncompleteClientCertificate, err)
+
+	invalidCAFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.Nil(t, ioutil.WriteFile(invalidCAFile, []byte("not a certificate"), os.ModePerm))
+
+	args = createMockIndexerFactoryArgs()
+	args.CACertFile = invalidCAFile
+	_, err = createTransport(args)
+	require.ErrorIs(t, err, indexer.ErrInvalidCACertificate)
+
+	args.CACertFile = filepath.Join(t.TempDir(), "missing.crt")
+	_, err = createTransport(args)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestGzipRoundTripper_ShouldCompressRequestBody(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat(`{"index":{"_id":"hash"}}`+"\n", 100)
+	receivedBody := ""
+	receivedEncoding := ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get(headerContentEncoding)
+		reader, err := gzip.NewReader(r.Body)
+		require.Nil(t, err)
+		decompressed, err := ioutil.ReadAll(reader)
+		require.Nil(t, err)
+		receivedBody = string(decompressed)
+	}))
+	defer ts.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.CompressRequestBody = true
+	transport, err := createTransport(args)
+	require.Nil(t, err)
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/_bulk", strings.NewReader(body))
+	resp, err := transport.RoundTrip(req)
+	require.Nil(t, err)
+	_ = resp.Body.Close()
+
+	require.Equal(t, gzipEncoding, receivedEncoding)
+	require.Equal(t, body, receivedBody)
+	require.Equal(t, "", req.Header.Get(headerContentEncoding))
+}
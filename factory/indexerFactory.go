@@ -2,13 +2,13 @@ package factory
 
 import (
 	"fmt"
+	"time"
 
 	indexer "github.com/ElrondNetwork/elastic-indexer-go"
 	"github.com/ElrondNetwork/elrond-go-core/core"
 	"github.com/ElrondNetwork/elrond-go-core/core/check"
 	"github.com/ElrondNetwork/elrond-go-core/hashing"
 	"github.com/ElrondNetwork/elrond-go-core/marshal"
-	"github.com/elastic/go-elasticsearch/v7"
 )
 
 // ArgsIndexerFactory holds all dependencies required by the data indexer factory in order to create
@@ -30,6 +30,26 @@ type ArgsIndexerFactory struct {
 	AccountsDB               indexer.AccountsAdapter
 	TransactionFeeCalculator indexer.FeesProcessorHandler
 	IsInImportDBMode         bool
+	RefreshMode              string
+	RefreshPolicies          map[string]string
+	BlocksBatchSize          int
+	BlocksBatchMaxBytes      int
+	BlocksBatchMaxAge        time.Duration
+	BulkMaxBytes             int
+	BulkMaxDocs              int
+	UseScaledNumericValues   bool
+	APIKey                   string
+	BearerToken              string
+	CompressRequestBody      bool
+	MaxIdleConns             int
+	MaxIdleConnsPerHost      int
+	IdleConnTimeout          time.Duration
+	DialTimeout              time.Duration
+	ResponseHeaderTimeout    time.Duration
+	CACertFile               string
+	ClientCertFile           string
+	ClientKeyFile            string
+	InsecureSkipVerify       bool
 }
 
 // NewIndexer will create a new instance of Indexer
@@ -61,21 +81,23 @@ func NewIndexer(args *ArgsIndexerFactory) (indexer.Indexer, error) {
 		ShardCoordinator: args.ShardCoordinator,
 		ElasticProcessor: elasticProcessor,
 		DataDispatcher:   dispatcher,
+		BatchFlushPeriod: args.BlocksBatchMaxAge,
 	}
 
 	return indexer.NewDataIndexer(arguments)
 }
 
-func createDatabaseClient(url, userName, password string) (indexer.DatabaseClientHandler, error) {
-	return indexer.NewElasticClient(elasticsearch.Config{
-		Addresses: []string{url},
-		Username:  userName,
-		Password:  password,
-	})
+func createDatabaseClient(args *ArgsIndexerFactory) (indexer.DatabaseClientHandler, error) {
+	cfg, err := createElasticConfig(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return indexer.NewElasticClient(cfg)
 }
 
 func createElasticProcessor(args *ArgsIndexerFactory) (indexer.ElasticProcessor, error) {
-	databaseClient, err := createDatabaseClient(args.Url, args.UserName, args.Password)
+	databaseClient, err := createDatabaseClient(args)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +107,11 @@ func createElasticProcessor(args *ArgsIndexerFactory) (indexer.ElasticProcessor,
 		return nil, err
 	}
 
+	refreshPolicies, err := createRefreshPolicies(args.RefreshMode, args.RefreshPolicies)
+	if err != nil {
+		return nil, err
+	}
+
 	enabledIndexesMap := make(map[string]struct{})
 	for _, index := range args.EnabledIndexes {
 		enabledIndexesMap[index] = struct{}{}
@@ -108,11 +135,37 @@ func createElasticProcessor(args *ArgsIndexerFactory) (indexer.ElasticProcessor,
 		TransactionFeeCalculator: args.TransactionFeeCalculator,
 		IsInImportDBMode:         args.IsInImportDBMode,
 		ShardCoordinator:         args.ShardCoordinator,
+		RefreshPolicies:          refreshPolicies,
+		BlocksBatchSize:          args.BlocksBatchSize,
+		BlocksBatchMaxBytes:      args.BlocksBatchMaxBytes,
+		BlocksBatchMaxAge:        args.BlocksBatchMaxAge,
+		BulkMaxBytes:             args.BulkMaxBytes,
+		BulkMaxDocs:              args.BulkMaxDocs,
+		UseScaledNumericValues:   args.UseScaledNumericValues,
 	}
 
 	return indexer.NewElasticProcessor(esIndexerArgs)
 }
 
+// createRefreshPolicies will create the refresh policies for the provided mode (defaults to the tip mode) and will
+// override them with the per-index policies, if any
+func createRefreshPolicies(refreshMode string, customPolicies map[string]string) (map[string]string, error) {
+	if refreshMode == "" {
+		refreshMode = indexer.TipRefreshMode
+	}
+
+	refreshPolicies, err := indexer.CreateRefreshPolicies(refreshMode)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, policy := range customPolicies {
+		refreshPolicies[index] = policy
+	}
+
+	return refreshPolicies, nil
+}
+
 func checkDataIndexerParams(arguments *ArgsIndexerFactory) error {
 	if arguments.IndexerCacheSize < 0 {
 		return indexer.ErrNegativeCacheSize
@@ -135,6 +188,9 @@ func checkDataIndexerParams(arguments *ArgsIndexerFactory) error {
 	if check.IfNil(arguments.TransactionFeeCalculator) {
 		return core.ErrNilTransactionFeeCalculator
 	}
+	if arguments.MaxIdleConns < 0 || arguments.MaxIdleConnsPerHost < 0 {
+		return indexer.ErrNegativeConnectionLimit
+	}
 
 	return nil
 }
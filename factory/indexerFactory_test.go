@@ -111,6 +111,35 @@ func TestNewIndexerFactory(t *testing.T) {
 			},
 			exError: core.ErrNilTransactionFeeCalculator,
 		},
+		{
+			name: "InvalidRefreshMode",
+			argsFunc: func() *ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.RefreshMode = "always"
+				return args
+			},
+			exError: indexer.ErrInvalidRefreshMode,
+		},
+		{
+			name: "NegativeMaxIdleConns",
+			argsFunc: func() *ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.MaxIdleConns = -1
+				return args
+			},
+			exError: indexer.ErrNegativeConnectionLimit,
+		},
+		{
+			name: "MultipleAuthMethods",
+			argsFunc: func() *ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.UserName = "user"
+				args.Password = "pass"
+				args.APIKey = "key"
+				return args
+			},
+			exError: indexer.ErrMultipleAuthMethods,
+		},
 		{
 			name: "All arguments ok",
 			argsFunc: func() *ArgsIndexerFactory {
@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"bytes"
+	"context"
 	"math/big"
 
 	"github.com/ElrondNetwork/elastic-indexer-go/data"
@@ -23,25 +24,38 @@ type DispatcherHandler interface {
 
 // ElasticProcessor defines the interface for the elastic search indexer
 type ElasticProcessor interface {
-	SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, txsSize int) error
+	SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error
+	UpdateNotarizedTransactions(header coreData.HeaderHandler, notarizedHeadersHashes []string) error
 	RemoveHeader(header coreData.HeaderHandler) error
 	RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
 	RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error
+	RemoveRoundsInfo(header coreData.HeaderHandler) error
+	RemoveEpochData(header coreData.HeaderHandler) error
+	RevertTokens(header coreData.HeaderHandler, body *block.Body) error
+	RevertDelegations(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccounts(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccountsHistory(header coreData.HeaderHandler) error
+	RevertAddressActivity(header coreData.HeaderHandler) error
 	SaveMiniblocks(header coreData.HeaderHandler, body *block.Body) (map[string]bool, error)
 	SaveTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool, mbsInDb map[string]bool) error
 	SaveValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error
 	SaveRoundsInfo(infos []*data.RoundInfo) error
 	SaveShardValidatorsPubKeys(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error
 	SaveAccounts(blockTimestamp uint64, accounts []*data.Account) error
+	FlushBatch(force bool) error
+	NotifyWhenFlushed(handler func())
 	IsInterfaceNil() bool
 }
 
 // DatabaseClientHandler is an interface that do requests to elasticsearch server
 type DatabaseClientHandler interface {
 	DoRequest(req *esapi.IndexRequest) error
-	DoBulkRequest(buff *bytes.Buffer, index string) error
+	DoBulkRequest(buff *bytes.Buffer, index string, refresh string) error
 	DoBulkRemove(index string, hashes []string) error
 	DoMultiGet(query objectsMap, index string) (objectsMap, error)
+	DoSearch(query objectsMap, index string) (objectsMap, error)
+	DoUpdateByQuery(query objectsMap, index string) error
+	DoDeleteByQuery(query objectsMap, index string) error
 
 	CheckAndCreateIndex(index string) error
 	CheckAndCreateAlias(alias string, index string) error
@@ -63,6 +77,7 @@ type FeesProcessorHandler interface {
 type Coordinator interface {
 	ComputeId(address []byte) uint32
 	SelfId() uint32
+	NumberOfShards() uint32
 	IsInterfaceNil() bool
 }
 
@@ -75,6 +90,8 @@ type Indexer interface {
 	SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32)
 	SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo)
 	SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler)
+	WaitForSavedItems(ctx context.Context) error
+	NotifyWhenSaved(handler func())
 	Close() error
 	IsInterfaceNil() bool
 	IsNilIndexer() bool
@@ -84,3 +101,13 @@ type AccountsAdapter interface {
 	LoadAccount(address []byte) (vmcommon.AccountHandler, error)
 	IsInterfaceNil() bool
 }
+
+// bulkBuffer defines the typed operations that can be added to the bulk requests sent to the elasticsearch server
+type bulkBuffer interface {
+	PutIndex(id string, doc []byte) error
+	PutUpdateDoc(id string, partialDoc []byte) error
+	PutUpdateScript(id string, script []byte) error
+	PutUpsert(id string, script []byte, doc []byte) error
+	PutDelete(id string) error
+	Buffers() []*bytes.Buffer
+}
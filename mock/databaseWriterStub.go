@@ -8,10 +8,13 @@ import (
 
 // DatabaseWriterStub -
 type DatabaseWriterStub struct {
-	DoRequestCalled     func(req *esapi.IndexRequest) error
-	DoBulkRequestCalled func(buff *bytes.Buffer, index string) error
-	DoBulkRemoveCalled  func(index string, hashes []string) error
-	DoMultiGetCalled    func(query map[string]interface{}, index string) (map[string]interface{}, error)
+	DoRequestCalled       func(req *esapi.IndexRequest) error
+	DoBulkRequestCalled   func(buff *bytes.Buffer, index string, refresh string) error
+	DoBulkRemoveCalled    func(index string, hashes []string) error
+	DoMultiGetCalled      func(query map[string]interface{}, index string) (map[string]interface{}, error)
+	DoSearchCalled        func(query map[string]interface{}, index string) (map[string]interface{}, error)
+	DoUpdateByQueryCalled func(query map[string]interface{}, index string) error
+	DoDeleteByQueryCalled func(query map[string]interface{}, index string) error
 }
 
 // DoRequest -
@@ -23,9 +26,9 @@ func (dwm *DatabaseWriterStub) DoRequest(req *esapi.IndexRequest) error {
 }
 
 // DoBulkRequest -
-func (dwm *DatabaseWriterStub) DoBulkRequest(buff *bytes.Buffer, index string) error {
+func (dwm *DatabaseWriterStub) DoBulkRequest(buff *bytes.Buffer, index string, refresh string) error {
 	if dwm.DoBulkRequestCalled != nil {
-		return dwm.DoBulkRequestCalled(buff, index)
+		return dwm.DoBulkRequestCalled(buff, index, refresh)
 	}
 	return nil
 }
@@ -39,6 +42,33 @@ func (dwm *DatabaseWriterStub) DoMultiGet(query map[string]interface{}, index st
 	return nil, nil
 }
 
+// DoSearch -
+func (dwm *DatabaseWriterStub) DoSearch(query map[string]interface{}, index string) (map[string]interface{}, error) {
+	if dwm.DoSearchCalled != nil {
+		return dwm.DoSearchCalled(query, index)
+	}
+
+	return nil, nil
+}
+
+// DoUpdateByQuery -
+func (dwm *DatabaseWriterStub) DoUpdateByQuery(query map[string]interface{}, index string) error {
+	if dwm.DoUpdateByQueryCalled != nil {
+		return dwm.DoUpdateByQueryCalled(query, index)
+	}
+
+	return nil
+}
+
+// DoDeleteByQuery -
+func (dwm *DatabaseWriterStub) DoDeleteByQuery(query map[string]interface{}, index string) error {
+	if dwm.DoDeleteByQueryCalled != nil {
+		return dwm.DoDeleteByQueryCalled(query, index)
+	}
+
+	return nil
+}
+
 // DoBulkRemove -
 func (dwm *DatabaseWriterStub) DoBulkRemove(index string, hashes []string) error {
 	if dwm.DoBulkRemoveCalled != nil {
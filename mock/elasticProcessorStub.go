@@ -9,22 +9,32 @@ import (
 
 // ElasticProcessorStub -
 type ElasticProcessorStub struct {
-	SaveHeaderCalled                 func(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, txsSize int) error
-	RemoveHeaderCalled               func(header coreData.HeaderHandler) error
-	RemoveMiniblocksCalled           func(header coreData.HeaderHandler, body *block.Body) error
-	RemoveTransactionsCalled         func(header coreData.HeaderHandler, body *block.Body) error
-	SaveMiniblocksCalled             func(header coreData.HeaderHandler, body *block.Body) (map[string]bool, error)
-	SaveTransactionsCalled           func(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool, mbsInDb map[string]bool) error
-	SaveValidatorsRatingCalled       func(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error
-	SaveRoundsInfoCalled             func(infos []*data.RoundInfo) error
-	SaveShardValidatorsPubKeysCalled func(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error
-	SaveAccountsCalled               func(timestamp uint64, acc []*data.Account) error
+	SaveHeaderCalled                  func(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error
+	RemoveHeaderCalled                func(header coreData.HeaderHandler) error
+	RemoveMiniblocksCalled            func(header coreData.HeaderHandler, body *block.Body) error
+	RemoveTransactionsCalled          func(header coreData.HeaderHandler, body *block.Body) error
+	RemoveRoundsInfoCalled            func(header coreData.HeaderHandler) error
+	RemoveEpochDataCalled             func(header coreData.HeaderHandler) error
+	RevertTokensCalled                func(header coreData.HeaderHandler, body *block.Body) error
+	RevertDelegationsCalled           func(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccountsCalled              func(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccountsHistoryCalled       func(header coreData.HeaderHandler) error
+	RevertAddressActivityCalled       func(header coreData.HeaderHandler) error
+	UpdateNotarizedTransactionsCalled func(header coreData.HeaderHandler, notarizedHeadersHashes []string) error
+	SaveMiniblocksCalled              func(header coreData.HeaderHandler, body *block.Body) (map[string]bool, error)
+	SaveTransactionsCalled            func(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool, mbsInDb map[string]bool) error
+	SaveValidatorsRatingCalled        func(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error
+	SaveRoundsInfoCalled              func(infos []*data.RoundInfo) error
+	SaveShardValidatorsPubKeysCalled  func(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error
+	SaveAccountsCalled                func(timestamp uint64, acc []*data.Account) error
+	FlushBatchCalled                  func(force bool) error
+	NotifyWhenFlushedCalled           func(handler func())
 }
 
 // SaveHeader -
-func (eim *ElasticProcessorStub) SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, txsSize int) error {
+func (eim *ElasticProcessorStub) SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error {
 	if eim.SaveHeaderCalled != nil {
-		return eim.SaveHeaderCalled(header, signersIndexes, body, notarizedHeadersHashes, txsSize)
+		return eim.SaveHeaderCalled(header, signersIndexes, body, notarizedHeadersHashes, pool, txsSize)
 	}
 	return nil
 }
@@ -53,6 +63,70 @@ func (eim *ElasticProcessorStub) RemoveTransactions(header coreData.HeaderHandle
 	return nil
 }
 
+// UpdateNotarizedTransactions -
+func (eim *ElasticProcessorStub) UpdateNotarizedTransactions(header coreData.HeaderHandler, notarizedHeadersHashes []string) error {
+	if eim.UpdateNotarizedTransactionsCalled != nil {
+		return eim.UpdateNotarizedTransactionsCalled(header, notarizedHeadersHashes)
+	}
+	return nil
+}
+
+// RemoveRoundsInfo -
+func (eim *ElasticProcessorStub) RemoveRoundsInfo(header coreData.HeaderHandler) error {
+	if eim.RemoveRoundsInfoCalled != nil {
+		return eim.RemoveRoundsInfoCalled(header)
+	}
+	return nil
+}
+
+// RemoveEpochData -
+func (eim *ElasticProcessorStub) RemoveEpochData(header coreData.HeaderHandler) error {
+	if eim.RemoveEpochDataCalled != nil {
+		return eim.RemoveEpochDataCalled(header)
+	}
+	return nil
+}
+
+// RevertTokens -
+func (eim *ElasticProcessorStub) RevertTokens(header coreData.HeaderHandler, body *block.Body) error {
+	if eim.RevertTokensCalled != nil {
+		return eim.RevertTokensCalled(header, body)
+	}
+	return nil
+}
+
+// RevertDelegations -
+func (eim *ElasticProcessorStub) RevertDelegations(header coreData.HeaderHandler, body *block.Body) error {
+	if eim.RevertDelegationsCalled != nil {
+		return eim.RevertDelegationsCalled(header, body)
+	}
+	return nil
+}
+
+// RevertAccounts -
+func (eim *ElasticProcessorStub) RevertAccounts(header coreData.HeaderHandler, body *block.Body) error {
+	if eim.RevertAccountsCalled != nil {
+		return eim.RevertAccountsCalled(header, body)
+	}
+	return nil
+}
+
+// RevertAccountsHistory -
+func (eim *ElasticProcessorStub) RevertAccountsHistory(header coreData.HeaderHandler) error {
+	if eim.RevertAccountsHistoryCalled != nil {
+		return eim.RevertAccountsHistoryCalled(header)
+	}
+	return nil
+}
+
+// RevertAddressActivity -
+func (eim *ElasticProcessorStub) RevertAddressActivity(header coreData.HeaderHandler) error {
+	if eim.RevertAddressActivityCalled != nil {
+		return eim.RevertAddressActivityCalled(header)
+	}
+	return nil
+}
+
 // SaveMiniblocks -
 func (eim *ElasticProcessorStub) SaveMiniblocks(header coreData.HeaderHandler, body *block.Body) (map[string]bool, error) {
 	if eim.SaveMiniblocksCalled != nil {
@@ -102,6 +176,25 @@ func (eim *ElasticProcessorStub) SaveAccounts(timestamp uint64, acc []*data.Acco
 	return nil
 }
 
+// FlushBatch -
+func (eim *ElasticProcessorStub) FlushBatch(force bool) error {
+	if eim.FlushBatchCalled != nil {
+		return eim.FlushBatchCalled(force)
+	}
+
+	return nil
+}
+
+// NotifyWhenFlushed -
+func (eim *ElasticProcessorStub) NotifyWhenFlushed(handler func()) {
+	if eim.NotifyWhenFlushedCalled != nil {
+		eim.NotifyWhenFlushedCalled(handler)
+		return
+	}
+
+	handler()
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (eim *ElasticProcessorStub) IsInterfaceNil() bool {
 	return eim == nil
@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"context"
+
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+)
+
+// IndexerStub -
+type IndexerStub struct {
+	SaveBlockCalled             func(args *indexer.ArgsSaveBlockData)
+	RevertIndexedBlockCalled    func(header coreData.HeaderHandler, body coreData.BodyHandler)
+	SaveRoundsInfoCalled        func(roundsInfos []*indexer.RoundInfo)
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys map[uint32][][]byte, epoch uint32)
+	SaveValidatorsRatingCalled  func(indexID string, infoRating []*indexer.ValidatorRatingInfo)
+	SaveAccountsCalled          func(blockTimestamp uint64, acc []coreData.UserAccountHandler)
+	WaitForSavedItemsCalled     func(ctx context.Context) error
+	NotifyWhenSavedCalled       func(handler func())
+	CloseCalled                 func() error
+}
+
+// SaveBlock -
+func (is *IndexerStub) SaveBlock(args *indexer.ArgsSaveBlockData) {
+	if is.SaveBlockCalled != nil {
+		is.SaveBlockCalled(args)
+	}
+}
+
+// RevertIndexedBlock -
+func (is *IndexerStub) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) {
+	if is.RevertIndexedBlockCalled != nil {
+		is.RevertIndexedBlockCalled(header, body)
+	}
+}
+
+// SaveRoundsInfo -
+func (is *IndexerStub) SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) {
+	if is.SaveRoundsInfoCalled != nil {
+		is.SaveRoundsInfoCalled(roundsInfos)
+	}
+}
+
+// SaveValidatorsPubKeys -
+func (is *IndexerStub) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) {
+	if is.SaveValidatorsPubKeysCalled != nil {
+		is.SaveValidatorsPubKeysCalled(validatorsPubKeys, epoch)
+	}
+}
+
+// SaveValidatorsRating -
+func (is *IndexerStub) SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) {
+	if is.SaveValidatorsRatingCalled != nil {
+		is.SaveValidatorsRatingCalled(indexID, infoRating)
+	}
+}
+
+// SaveAccounts -
+func (is *IndexerStub) SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler) {
+	if is.SaveAccountsCalled != nil {
+		is.SaveAccountsCalled(blockTimestamp, acc)
+	}
+}
+
+// WaitForSavedItems -
+func (is *IndexerStub) WaitForSavedItems(ctx context.Context) error {
+	if is.WaitForSavedItemsCalled != nil {
+		return is.WaitForSavedItemsCalled(ctx)
+	}
+
+	return nil
+}
+
+// NotifyWhenSaved -
+func (is *IndexerStub) NotifyWhenSaved(handler func()) {
+	if is.NotifyWhenSavedCalled != nil {
+		is.NotifyWhenSavedCalled(handler)
+		return
+	}
+
+	handler()
+}
+
+// Close -
+func (is *IndexerStub) Close() error {
+	if is.CloseCalled != nil {
+		return is.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsNilIndexer -
+func (is *IndexerStub) IsNilIndexer() bool {
+	return false
+}
+
+// IsInterfaceNil -
+func (is *IndexerStub) IsInterfaceNil() bool {
+	return is == nil
+}
@@ -5,12 +5,13 @@ import "github.com/ElrondNetwork/elrond-go-core/core"
 // ShardCoordinatorMock -
 type ShardCoordinatorMock struct {
 	SelfID          uint32
+	NumOfShards     uint32
 	ComputeIdCalled func(address []byte) uint32
 }
 
 // NumberOfShards -
 func (scm *ShardCoordinatorMock) NumberOfShards() uint32 {
-	panic("implement me")
+	return scm.NumOfShards
 }
 
 // ComputeId -
@@ -0,0 +1,136 @@
+package mock
+
+import (
+	"math/big"
+
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
+)
+
+// UserAccountStub -
+type UserAccountStub struct {
+	Address             []byte
+	Balance             *big.Int
+	Nonce               uint64
+	CodeHash            []byte
+	CodeMetadata        []byte
+	OwnerAddress        []byte
+	DeveloperReward     *big.Int
+	UserName            []byte
+	RetrieveValueCalled func(key []byte) ([]byte, error)
+}
+
+// GetCodeMetadata -
+func (uas *UserAccountStub) GetCodeMetadata() []byte {
+	return uas.CodeMetadata
+}
+
+// GetCodeHash -
+func (uas *UserAccountStub) GetCodeHash() []byte {
+	return uas.CodeHash
+}
+
+// GetRootHash -
+func (uas *UserAccountStub) GetRootHash() []byte {
+	return nil
+}
+
+// AccountDataHandler -
+func (uas *UserAccountStub) AccountDataHandler() vmcommon.AccountDataHandler {
+	return &accountDataHandlerStub{retrieveValueCalled: uas.RetrieveValueCalled}
+}
+
+// AddToBalance -
+func (uas *UserAccountStub) AddToBalance(_ *big.Int) error {
+	return nil
+}
+
+// GetBalance -
+func (uas *UserAccountStub) GetBalance() *big.Int {
+	if uas.Balance == nil {
+		return big.NewInt(0)
+	}
+
+	return uas.Balance
+}
+
+// ClaimDeveloperRewards -
+func (uas *UserAccountStub) ClaimDeveloperRewards(_ []byte) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+// GetDeveloperReward -
+func (uas *UserAccountStub) GetDeveloperReward() *big.Int {
+	if uas.DeveloperReward == nil {
+		return big.NewInt(0)
+	}
+
+	return uas.DeveloperReward
+}
+
+// ChangeOwnerAddress -
+func (uas *UserAccountStub) ChangeOwnerAddress(_ []byte, _ []byte) error {
+	return nil
+}
+
+// SetOwnerAddress -
+func (uas *UserAccountStub) SetOwnerAddress(address []byte) {
+	uas.OwnerAddress = address
+}
+
+// GetOwnerAddress -
+func (uas *UserAccountStub) GetOwnerAddress() []byte {
+	return uas.OwnerAddress
+}
+
+// SetUserName -
+func (uas *UserAccountStub) SetUserName(userName []byte) {
+	uas.UserName = userName
+}
+
+// GetUserName -
+func (uas *UserAccountStub) GetUserName() []byte {
+	return uas.UserName
+}
+
+// AddressBytes -
+func (uas *UserAccountStub) AddressBytes() []byte {
+	return uas.Address
+}
+
+// IncreaseNonce -
+func (uas *UserAccountStub) IncreaseNonce(nonce uint64) {
+	uas.Nonce += nonce
+}
+
+// GetNonce -
+func (uas *UserAccountStub) GetNonce() uint64 {
+	return uas.Nonce
+}
+
+// IsInterfaceNil -
+func (uas *UserAccountStub) IsInterfaceNil() bool {
+	return uas == nil
+}
+
+type accountDataHandlerStub struct {
+	retrieveValueCalled func(key []byte) ([]byte, error)
+}
+
+// RetrieveValue -
+func (adhs *accountDataHandlerStub) RetrieveValue(key []byte) ([]byte, error) {
+	if adhs.retrieveValueCalled != nil {
+		return adhs.retrieveValueCalled(key)
+	}
+
+	return nil, nil
+}
+
+// SaveKeyValue -
+func (adhs *accountDataHandlerStub) SaveKeyValue(_ []byte, _ []byte) error {
+	return nil
+}
+
+// IsInterfaceNil -
+func (adhs *accountDataHandlerStub) IsInterfaceNil() bool {
+	return adhs == nil
+}
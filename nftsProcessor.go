@@ -0,0 +1,533 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/esdt"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-go-core/marshal"
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
+)
+
+const (
+	attributesSeparator      = ";"
+	keyValueSeparator        = ":"
+	tagsSeparator            = ","
+	attributeTagsKey         = "tags"
+	attributeMetaDataKey     = "metadata"
+	minArgsNFTCreate         = 2
+	minArgsNFTWithNonce      = 2
+	minArgsNFTQuantityChange = 3
+	dataArgumentSeparator    = "@"
+	tokenIdentifierSeparator = "-"
+	minTickerLength          = 3
+	maxTickerLength          = 10
+	tokenRandomSuffixLength  = 6
+
+	// updateTokenScript sets the metadata of a token, when it is known, and applies the supply changes that were not
+	// applied yet. The token is removed once its supply reaches zero. The supply of the tokens created before they were
+	// indexed is not known, so these tokens are never removed
+	updateTokenScript = `if (params.token != null) { ctx._source.remove('tags'); ctx._source.remove('metadata'); ctx._source.putAll(params.token); } ` +
+		`for (change in params.supplyChanges) { ` +
+		`if (ctx._source.supply == null) { if (!change.created) { continue; } ctx._source.supply = '0'; ctx._source.appliedTxHashes = new ArrayList(); } ` +
+		`if (ctx._source.appliedTxHashes.contains(change.txHash)) { continue; } ` +
+		`ctx._source.supply = new BigInteger(ctx._source.supply).add(new BigInteger(change.value)).toString(); ` +
+		`ctx._source.appliedTxHashes.add(change.txHash); } ` +
+		`if (ctx._source.supply != null && new BigInteger(ctx._source.supply).signum() <= 0) { ctx.op = 'delete'; }`
+)
+
+// nftOperationKey identifies the token of an account that was affected by an NFT operation
+type nftOperationKey struct {
+	operator   string
+	identifier string
+	nonce      uint64
+}
+
+// nftSupplyChange is the quantity of a token created, added or burned by a transaction
+type nftSupplyChange struct {
+	txHash  string
+	value   *big.Int
+	created bool
+}
+
+// nftCreation is a creation of a token. The nonce of the transaction gives the order of the creations of an account
+type nftCreation struct {
+	txNonce      uint64
+	supplyChange *nftSupplyChange
+}
+
+// nftOperations holds the NFT operations of a block grouped by their effect on the nfts index
+type nftOperations struct {
+	// created holds the creations of tokens, the nonce of the created tokens is not known until the latest nonce is
+	// read from the account of the creator
+	created map[nftOperationKey][]*nftCreation
+	// updated holds the tokens whose metadata has to be loaded again
+	updated       map[nftOperationKey]struct{}
+	supplyChanges map[nftOperationKey][]*nftSupplyChange
+}
+
+// tokenUpdate holds the metadata of a token, when it is known, and the changes of its supply made by a block
+type tokenUpdate struct {
+	token         *data.Token
+	supplyChanges []*nftSupplyChange
+}
+
+type nftsProcessor struct {
+	accountsDB       AccountsAdapter
+	marshalizer      marshal.Marshalizer
+	pubkeyConverter  core.PubkeyConverter
+	shardCoordinator Coordinator
+}
+
+func newNFTsProcessor(
+	accountsDB AccountsAdapter,
+	marshalizer marshal.Marshalizer,
+	pubkeyConverter core.PubkeyConverter,
+	shardCoordinator Coordinator,
+) *nftsProcessor {
+	return &nftsProcessor{
+		accountsDB:       accountsDB,
+		marshalizer:      marshalizer,
+		pubkeyConverter:  pubkeyConverter,
+		shardCoordinator: shardCoordinator,
+	}
+}
+
+// getNFTOperations will detect the NFT operations from the transactions and the smart contract results of the block
+// that were executed in the current shard. The operations of the failed transactions and of the smart contract results
+// generated by them are skipped. The pool has to be keyed by the raw transaction hashes
+func (np *nftsProcessor) getNFTOperations(pool map[string]coreData.TransactionHandler, failedTxsHashes map[string]struct{}) *nftOperations {
+	operations := &nftOperations{
+		created:       make(map[nftOperationKey][]*nftCreation),
+		updated:       make(map[nftOperationKey]struct{}),
+		supplyChanges: make(map[nftOperationKey][]*nftSupplyChange),
+	}
+
+	for hash, txHandler := range pool {
+		var sender, txData, originalTxHash []byte
+		switch tx := txHandler.(type) {
+		case *transaction.Transaction:
+			sender, txData, originalTxHash = tx.SndAddr, tx.Data, []byte(hash)
+		case *smartContractResult.SmartContractResult:
+			sender, txData, originalTxHash = tx.SndAddr, tx.Data, tx.OriginalTxHash
+		default:
+			continue
+		}
+
+		_, isFailed := failedTxsHashes[hex.EncodeToString(originalTxHash)]
+		if isFailed || np.shardCoordinator.ComputeId(sender) != np.shardCoordinator.SelfId() {
+			continue
+		}
+
+		supplyChange := &nftSupplyChange{txHash: hex.EncodeToString([]byte(hash))}
+		np.addNFTOperation(operations, string(sender), txData, txHandler.GetNonce(), supplyChange)
+	}
+
+	return operations
+}
+
+func (np *nftsProcessor) addNFTOperation(
+	operations *nftOperations,
+	operator string,
+	txData []byte,
+	txNonce uint64,
+	supplyChange *nftSupplyChange,
+) {
+	args := strings.Split(string(txData), dataArgumentSeparator)
+	function, args := args[0], args[1:]
+
+	switch function {
+	case core.BuiltInFunctionESDTNFTCreate:
+		if len(args) < minArgsNFTCreate {
+			return
+		}
+	case core.BuiltInFunctionESDTNFTBurn, core.BuiltInFunctionESDTNFTAddQuantity:
+		if len(args) < minArgsNFTQuantityChange {
+			return
+		}
+	case core.BuiltInFunctionESDTNFTUpdateAttributes, core.BuiltInFunctionESDTNFTAddURI:
+		if len(args) < minArgsNFTWithNonce {
+			return
+		}
+	default:
+		return
+	}
+
+	identifier, err := hex.DecodeString(args[0])
+	if err != nil || !isValidTokenIdentifier(string(identifier)) {
+		return
+	}
+
+	key := nftOperationKey{
+		operator:   operator,
+		identifier: string(identifier),
+	}
+	if function == core.BuiltInFunctionESDTNFTCreate {
+		quantity, ok := decodeNFTQuantity(args[1])
+		if !ok {
+			return
+		}
+
+		supplyChange.value = quantity
+		supplyChange.created = true
+		operations.created[key] = append(operations.created[key], &nftCreation{
+			txNonce:      txNonce,
+			supplyChange: supplyChange,
+		})
+		return
+	}
+
+	nonce, err := hex.DecodeString(args[1])
+	if err != nil {
+		return
+	}
+
+	key.nonce = big.NewInt(0).SetBytes(nonce).Uint64()
+	switch function {
+	case core.BuiltInFunctionESDTNFTBurn, core.BuiltInFunctionESDTNFTAddQuantity:
+		quantity, ok := decodeNFTQuantity(args[2])
+		if !ok {
+			return
+		}
+
+		if function == core.BuiltInFunctionESDTNFTBurn {
+			quantity.Neg(quantity)
+		}
+		supplyChange.value = quantity
+		operations.supplyChanges[key] = append(operations.supplyChanges[key], supplyChange)
+	}
+
+	operations.updated[key] = struct{}{}
+}
+
+// prepareTokens will load the metadata of the tokens affected by the provided operations and will group the changes
+// of their supply. It returns the updates of the tokens keyed by the token identifiers
+func (np *nftsProcessor) prepareTokens(operations *nftOperations, timestamp uint64) map[string]*tokenUpdate {
+	updates := make(map[string]*tokenUpdate)
+
+	for key, creations := range operations.created {
+		latestNonce, err := np.getLatestNonce(key)
+		if err != nil {
+			log.Debug("nftsProcessor.prepareTokens: cannot get latest nonce", "identifier", key.identifier, "error", err)
+			continue
+		}
+
+		sort.Slice(creations, func(i, j int) bool {
+			if creations[i].txNonce != creations[j].txNonce {
+				return creations[i].txNonce < creations[j].txNonce
+			}
+			return creations[i].supplyChange.txHash < creations[j].supplyChange.txHash
+		})
+
+		numCreated := uint64(len(creations))
+		firstNonce := uint64(1)
+		if latestNonce > numCreated {
+			firstNonce = latestNonce - numCreated + 1
+		}
+		for index, creation := range creations {
+			key.nonce = firstNonce + uint64(index)
+			if key.nonce > latestNonce {
+				break
+			}
+
+			update := np.getTokenUpdate(updates, key, timestamp)
+			update.supplyChanges = append(update.supplyChanges, creation.supplyChange)
+		}
+	}
+
+	for key := range operations.updated {
+		update := np.getTokenUpdate(updates, key, timestamp)
+		update.supplyChanges = append(update.supplyChanges, operations.supplyChanges[key]...)
+	}
+
+	// the operations on tokens that could not be loaded are skipped, as nothing proves that the tokens exist. The
+	// burns are the exception: an account that burned all its units does not hold the token anymore, so these burns
+	// are applied only on the tokens that are already indexed
+	for identifier, update := range updates {
+		if update.token == nil && !hasOnlyBurns(update.supplyChanges) {
+			log.Debug("nftsProcessor.prepareTokens: skipped the operations of an unknown token", "identifier", identifier)
+			delete(updates, identifier)
+		}
+	}
+
+	return updates
+}
+
+// getTokenUpdate returns the update of the token of the provided key. The metadata is loaded from the account that
+// made the operation, so it is not known when the account does not hold the token anymore
+func (np *nftsProcessor) getTokenUpdate(updates map[string]*tokenUpdate, key nftOperationKey, timestamp uint64) *tokenUpdate {
+	identifier := computeTokenIdentifier(key.identifier, key.nonce)
+	update, ok := updates[identifier]
+	if !ok {
+		update = &tokenUpdate{
+			supplyChanges: make([]*nftSupplyChange, 0),
+		}
+		updates[identifier] = update
+	}
+
+	if update.token == nil {
+		update.token = np.loadToken(key, timestamp)
+	}
+
+	return update
+}
+
+func (np *nftsProcessor) loadToken(key nftOperationKey, timestamp uint64) *data.Token {
+	esdtToken, err := np.getESDTToken(key)
+	if err != nil {
+		log.Debug("nftsProcessor.loadToken: cannot load token",
+			"identifier", key.identifier, "nonce", key.nonce, "error", err)
+		return nil
+	}
+	if esdtToken == nil || esdtToken.TokenMetaData == nil {
+		return nil
+	}
+
+	return np.convertToken(key.identifier, esdtToken.TokenMetaData, timestamp)
+}
+
+func (np *nftsProcessor) convertToken(identifier string, metaData *esdt.MetaData, timestamp uint64) *data.Token {
+	tags, metaDataAttribute := decodeTokenAttributes(metaData.Attributes)
+
+	creator := ""
+	if len(metaData.Creator) > 0 {
+		creator = np.pubkeyConverter.Encode(metaData.Creator)
+	}
+
+	return &data.Token{
+		Identifier: computeTokenIdentifier(identifier, metaData.Nonce),
+		Token:      identifier,
+		Nonce:      metaData.Nonce,
+		Name:       string(metaData.Name),
+		Creator:    creator,
+		Royalties:  metaData.Royalties,
+		Hash:       metaData.Hash,
+		URIs:       metaData.URIs,
+		Attributes: metaData.Attributes,
+		Tags:       tags,
+		MetaData:   metaDataAttribute,
+		Timestamp:  time.Duration(timestamp),
+	}
+}
+
+func (np *nftsProcessor) getESDTToken(key nftOperationKey) (*esdt.ESDigitalToken, error) {
+	tokenKey := []byte(core.ElrondProtectedKeyPrefix + core.ESDTKeyIdentifier + key.identifier)
+	tokenKey = append(tokenKey, big.NewInt(0).SetUint64(key.nonce).Bytes()...)
+
+	serializedToken, err := np.retrieveValue(key.operator, tokenKey)
+	if err != nil || len(serializedToken) == 0 {
+		return nil, err
+	}
+
+	esdtToken := &esdt.ESDigitalToken{}
+	err = np.marshalizer.Unmarshal(esdtToken, serializedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return esdtToken, nil
+}
+
+func (np *nftsProcessor) getLatestNonce(key nftOperationKey) (uint64, error) {
+	nonceKey := []byte(core.ElrondProtectedKeyPrefix + core.ESDTNFTLatestNonceIdentifier + key.identifier)
+	latestNonce, err := np.retrieveValue(key.operator, nonceKey)
+	if err != nil {
+		return 0, err
+	}
+
+	return big.NewInt(0).SetBytes(latestNonce).Uint64(), nil
+}
+
+func (np *nftsProcessor) retrieveValue(address string, key []byte) ([]byte, error) {
+	account, err := np.accountsDB.LoadAccount([]byte(address))
+	if err != nil {
+		return nil, err
+	}
+
+	userAccount, ok := account.(vmcommon.UserAccountHandler)
+	if !ok {
+		return nil, ErrCannotCastAccountHandlerToUserAccount
+	}
+
+	return userAccount.AccountDataHandler().RetrieveValue(key)
+}
+
+// putTokenUpdate adds the operation that applies the update of a token. A token created by the block is indexed with
+// its supply, or removed if it was burned by the same block. The update of a token whose metadata is not known is
+// applied only if the token is already indexed
+func putTokenUpdate(identifier string, update *tokenUpdate, buffSlice bulkBuffer) error {
+	params := objectsMap{
+		"supplyChanges": serializeSupplyChanges(update.supplyChanges),
+	}
+	if update.token == nil {
+		return putUpdateScript(identifier, updateTokenScript, params, buffSlice)
+	}
+
+	doc := *update.token
+	if hasCreation(update.supplyChanges) {
+		supply, appliedTxHashes := sumSupplyChanges(update.supplyChanges)
+		if supply.Sign() <= 0 {
+			return buffSlice.PutDelete(identifier)
+		}
+
+		doc.Supply = supply.String()
+		doc.AppliedTxHashes = appliedTxHashes
+	}
+
+	params["token"] = update.token
+	script, err := json.Marshal(objectsMap{
+		"source": updateTokenScript,
+		"lang":   "painless",
+		"params": params,
+	})
+	if err != nil {
+		return err
+	}
+
+	serializedDoc, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpsert(identifier, script, serializedDoc)
+}
+
+func serializeSupplyChanges(supplyChanges []*nftSupplyChange) []objectsMap {
+	serialized := make([]objectsMap, 0, len(supplyChanges))
+	for _, supplyChange := range supplyChanges {
+		serialized = append(serialized, objectsMap{
+			"txHash":  supplyChange.txHash,
+			"value":   supplyChange.value.String(),
+			"created": supplyChange.created,
+		})
+	}
+
+	return serialized
+}
+
+func hasOnlyBurns(supplyChanges []*nftSupplyChange) bool {
+	for _, supplyChange := range supplyChanges {
+		if supplyChange.created || supplyChange.value.Sign() >= 0 {
+			return false
+		}
+	}
+
+	return len(supplyChanges) > 0
+}
+
+func hasCreation(supplyChanges []*nftSupplyChange) bool {
+	for _, supplyChange := range supplyChanges {
+		if supplyChange.created {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sumSupplyChanges(supplyChanges []*nftSupplyChange) (*big.Int, []string) {
+	supply := big.NewInt(0)
+	txHashes := make([]string, 0, len(supplyChanges))
+	for _, supplyChange := range supplyChanges {
+		supply.Add(supply, supplyChange.value)
+		txHashes = append(txHashes, supplyChange.txHash)
+	}
+
+	return supply, txHashes
+}
+
+// getFailedTransactionsHashes returns the hashes of the transactions that failed or are invalid
+func getFailedTransactionsHashes(txs []*data.Transaction) map[string]struct{} {
+	failedTxsHashes := make(map[string]struct{})
+	for _, tx := range txs {
+		if tx.Status == transaction.TxStatusInvalid.String() || tx.Status == transaction.TxStatusFail.String() {
+			failedTxsHashes[tx.Hash] = struct{}{}
+		}
+	}
+
+	return failedTxsHashes
+}
+
+func decodeNFTQuantity(encodedQuantity string) (*big.Int, bool) {
+	quantity, err := hex.DecodeString(encodedQuantity)
+	if err != nil {
+		return nil, false
+	}
+
+	value := big.NewInt(0).SetBytes(quantity)
+
+	return value, value.Sign() > 0
+}
+
+// isValidTokenIdentifier returns true if the identifier has the format of the ESDT tokens identifiers: an uppercase
+// alphanumeric ticker followed by a dash and by a random suffix of lowercase hex characters
+func isValidTokenIdentifier(identifier string) bool {
+	separatorIndex := strings.Index(identifier, tokenIdentifierSeparator)
+	if separatorIndex < 0 {
+		return false
+	}
+
+	ticker, randomSuffix := identifier[:separatorIndex], identifier[separatorIndex+1:]
+	if len(ticker) < minTickerLength || len(ticker) > maxTickerLength || len(randomSuffix) != tokenRandomSuffixLength {
+		return false
+	}
+	for _, c := range ticker {
+		isUpperLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if !isUpperLetter && !isDigit {
+			return false
+		}
+	}
+	for _, c := range randomSuffix {
+		isHexLetter := c >= 'a' && c <= 'f'
+		isDigit := c >= '0' && c <= '9'
+		if !isHexLetter && !isDigit {
+			return false
+		}
+	}
+
+	return true
+}
+
+// computeTokenIdentifier returns the identifier of a token with the provided nonce, as used by the protocol
+func computeTokenIdentifier(identifier string, nonce uint64) string {
+	return fmt.Sprintf("%s-%s", identifier, hex.EncodeToString(big.NewInt(0).SetUint64(nonce).Bytes()))
+}
+
+// decodeTokenAttributes extracts the tags and the metadata from attributes that use the standard
+// "tags:tag1,tag2;metadata:value" format
+func decodeTokenAttributes(attributes []byte) ([]string, string) {
+	var tags []string
+	metaData := ""
+
+	for _, attribute := range strings.Split(string(attributes), attributesSeparator) {
+		keyValue := strings.SplitN(attribute, keyValueSeparator, 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch strings.TrimSpace(keyValue[0]) {
+		case attributeTagsKey:
+			for _, tag := range strings.Split(keyValue[1], tagsSeparator) {
+				tag = strings.TrimSpace(tag)
+				if tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		case attributeMetaDataKey:
+			metaData = strings.TrimSpace(keyValue[1])
+		}
+	}
+
+	return tags, metaData
+}
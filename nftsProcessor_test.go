@@ -0,0 +1,258 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/esdt"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
+	"github.com/stretchr/testify/require"
+)
+
+func createNFTsProcessor(storage map[string][]byte) *nftsProcessor {
+	accountsDB := &mock.AccountsStub{
+		LoadAccountCalled: func(address []byte) (vmcommon.AccountHandler, error) {
+			return &mock.UserAccountStub{
+				Address: address,
+				RetrieveValueCalled: func(key []byte) ([]byte, error) {
+					return storage[string(key)], nil
+				},
+			}, nil
+		},
+	}
+
+	return newNFTsProcessor(accountsDB, &mock.MarshalizerMock{}, mock.NewPubkeyConverterMock(32), &mock.ShardCoordinatorMock{})
+}
+
+func createNFTData(function string, identifier string, args ...uint64) []byte {
+	txData := function + "@" + hex.EncodeToString([]byte(identifier))
+	for _, arg := range args {
+		txData += "@" + hex.EncodeToString(big.NewInt(0).SetUint64(arg).Bytes())
+	}
+
+	return []byte(txData)
+}
+
+func putESDTToken(t *testing.T, storage map[string][]byte, identifier string, metaData *esdt.MetaData) {
+	serializedToken, err := (&mock.MarshalizerMock{}).Marshal(&esdt.ESDigitalToken{
+		Value:         big.NewInt(1),
+		TokenMetaData: metaData,
+	})
+	require.Nil(t, err)
+
+	tokenKey := core.ElrondProtectedKeyPrefix + core.ESDTKeyIdentifier + identifier + string(big.NewInt(0).SetUint64(metaData.Nonce).Bytes())
+	storage[tokenKey] = serializedToken
+}
+
+func TestNFTsProcessor_GetNFTOperations(t *testing.T) {
+	t.Parallel()
+
+	np := createNFTsProcessor(nil)
+	np.shardCoordinator = &mock.ShardCoordinatorMock{
+		ComputeIdCalled: func(address []byte) uint32 {
+			if string(address) == "other shard" {
+				return 1
+			}
+			return 0
+		},
+	}
+
+	pool := map[string]coreData.TransactionHandler{
+		"h1":  &transaction.Transaction{Nonce: 1, SndAddr: []byte("creator"), Data: createNFTData(core.BuiltInFunctionESDTNFTCreate, "NFT-abcdef", 1)},
+		"h2":  &transaction.Transaction{Nonce: 2, SndAddr: []byte("creator"), Data: createNFTData(core.BuiltInFunctionESDTNFTCreate, "NFT-abcdef", 5)},
+		"h3":  &smartContractResult.SmartContractResult{SndAddr: []byte("owner"), OriginalTxHash: []byte("h0"), Data: createNFTData(core.BuiltInFunctionESDTNFTBurn, "NFT-abcdef", 1, 2)},
+		"h4":  &transaction.Transaction{SndAddr: []byte("owner"), Data: createNFTData(core.BuiltInFunctionESDTNFTUpdateAttributes, "NFT-abcdef", 2)},
+		"h5":  &transaction.Transaction{SndAddr: []byte("other shard"), Data: createNFTData(core.BuiltInFunctionESDTNFTAddURI, "NFT-abcdef", 3)},
+		"h6":  &transaction.Transaction{SndAddr: []byte("owner"), Data: []byte("transfer")},
+		"h7":  &transaction.Transaction{SndAddr: []byte("owner"), Data: []byte(core.BuiltInFunctionESDTNFTAddQuantity + "@zz@01@01")},
+		"h8":  &transaction.Transaction{SndAddr: []byte("owner"), Data: createNFTData(core.BuiltInFunctionESDTNFTBurn, "NFT-abcdef", 3, 1)},
+		"h9":  &smartContractResult.SmartContractResult{SndAddr: []byte("owner"), OriginalTxHash: []byte("h8"), Data: createNFTData(core.BuiltInFunctionESDTNFTAddQuantity, "NFT-abcdef", 4, 1)},
+		"h10": &transaction.Transaction{SndAddr: []byte("owner"), Data: createNFTData(core.BuiltInFunctionESDTNFTAddURI, "NFT-abcdef\" } }\n", 5)},
+		"h11": &transaction.Transaction{SndAddr: []byte("owner"), Data: createNFTData(core.BuiltInFunctionESDTNFTAddURI, "nft-abcdef", 5)},
+	}
+	failedTxsHashes := map[string]struct{}{
+		hex.EncodeToString([]byte("h8")): {},
+	}
+
+	operations := np.getNFTOperations(pool, failedTxsHashes)
+	creatorKey := nftOperationKey{operator: "creator", identifier: "NFT-abcdef"}
+	require.Len(t, operations.created, 1)
+	require.Len(t, operations.created[creatorKey], 2)
+
+	burnedKey := nftOperationKey{operator: "owner", identifier: "NFT-abcdef", nonce: 1}
+	require.Equal(t, map[nftOperationKey][]*nftSupplyChange{
+		burnedKey: {{txHash: hex.EncodeToString([]byte("h3")), value: big.NewInt(-2)}},
+	}, operations.supplyChanges)
+	require.Equal(t, map[nftOperationKey]struct{}{
+		burnedKey: {},
+		{operator: "owner", identifier: "NFT-abcdef", nonce: 2}: {},
+	}, operations.updated)
+}
+
+func TestNFTsProcessor_PrepareTokens(t *testing.T) {
+	t.Parallel()
+
+	storage := make(map[string][]byte)
+	storage[core.ElrondProtectedKeyPrefix+core.ESDTNFTLatestNonceIdentifier+"NFT-abcdef"] = big.NewInt(11).Bytes()
+	putESDTToken(t, storage, "NFT-abcdef", &esdt.MetaData{
+		Nonce:      10,
+		Name:       []byte("first"),
+		Creator:    []byte("creator"),
+		Attributes: []byte("tags:art, music;metadata:QmHash"),
+	})
+	putESDTToken(t, storage, "NFT-abcdef", &esdt.MetaData{Nonce: 11, Name: []byte("second")})
+
+	np := createNFTsProcessor(storage)
+	burnedKey := nftOperationKey{operator: "owner", identifier: "NFT-abcdef", nonce: 1}
+	burn := &nftSupplyChange{txHash: "burn", value: big.NewInt(-1)}
+	firstCreation := &nftSupplyChange{txHash: "first", value: big.NewInt(3), created: true}
+	secondCreation := &nftSupplyChange{txHash: "second", value: big.NewInt(1), created: true}
+	operations := &nftOperations{
+		created: map[nftOperationKey][]*nftCreation{
+			{operator: "creator", identifier: "NFT-abcdef"}: {
+				{txNonce: 8, supplyChange: secondCreation},
+				{txNonce: 7, supplyChange: firstCreation},
+			},
+		},
+		updated: map[nftOperationKey]struct{}{
+			burnedKey: {},
+			{operator: "owner", identifier: "NFT-abcdef", nonce: 20}: {},
+		},
+		supplyChanges: map[nftOperationKey][]*nftSupplyChange{burnedKey: {burn}},
+	}
+
+	updates := np.prepareTokens(operations, 5000)
+	require.Len(t, updates, 3)
+
+	burned := updates["NFT-abcdef-01"]
+	require.NotNil(t, burned)
+	require.Nil(t, burned.token)
+	require.Equal(t, []*nftSupplyChange{burn}, burned.supplyChanges)
+
+	first := updates["NFT-abcdef-0a"]
+	require.NotNil(t, first)
+	require.Equal(t, []*nftSupplyChange{firstCreation}, first.supplyChanges)
+	require.Equal(t, "NFT-abcdef", first.token.Token)
+	require.Equal(t, uint64(10), first.token.Nonce)
+	require.Equal(t, "first", first.token.Name)
+	require.Equal(t, hex.EncodeToString([]byte("creator")), first.token.Creator)
+	require.Equal(t, []string{"art", "music"}, first.token.Tags)
+	require.Equal(t, "QmHash", first.token.MetaData)
+
+	second := updates["NFT-abcdef-0b"]
+	require.NotNil(t, second)
+	require.Equal(t, []*nftSupplyChange{secondCreation}, second.supplyChanges)
+	require.Equal(t, "second", second.token.Name)
+	require.Equal(t, "", second.token.Creator)
+	require.Nil(t, second.token.Tags)
+}
+
+func TestPutTokenUpdate(t *testing.T) {
+	t.Parallel()
+
+	token := &data.Token{Identifier: "NFT-abcdef-01", Token: "NFT-abcdef", Nonce: 1}
+	creation := &nftSupplyChange{txHash: "create", value: big.NewInt(5), created: true}
+	burn := &nftSupplyChange{txHash: "burn", value: big.NewInt(-2)}
+
+	buffSlice := data.NewBufferSlice()
+	err := putTokenUpdate("created", &tokenUpdate{token: token, supplyChanges: []*nftSupplyChange{creation, burn}}, buffSlice)
+	require.Nil(t, err)
+	serialized := buffSlice.Buffers()[0].String()
+	require.Contains(t, serialized, `"upsert" : {"identifier":"NFT-abcdef-01","token":"NFT-abcdef","nonce":1,`)
+	require.Contains(t, serialized, `"supply":"3","appliedTxHashes":["create","burn"]`)
+	require.Contains(t, serialized, `"supplyChanges":[{"created":true,"txHash":"create","value":"5"},{"created":false,"txHash":"burn","value":"-2"}]`)
+
+	buffSlice = data.NewBufferSlice()
+	err = putTokenUpdate("burned", &tokenUpdate{supplyChanges: []*nftSupplyChange{burn}}, buffSlice)
+	require.Nil(t, err)
+	serialized = buffSlice.Buffers()[0].String()
+	require.Contains(t, serialized, `{"update":{"_id":"burned"}}`)
+	require.NotContains(t, serialized, "upsert")
+	require.NotContains(t, serialized, `"token"`)
+
+	buffSlice = data.NewBufferSlice()
+	burnAll := &nftSupplyChange{txHash: "burnAll", value: big.NewInt(-5)}
+	err = putTokenUpdate("createdAndBurned", &tokenUpdate{token: token, supplyChanges: []*nftSupplyChange{creation, burnAll}}, buffSlice)
+	require.Nil(t, err)
+	require.Equal(t, `{"delete":{"_id":"createdAndBurned"}}`+"\n", buffSlice.Buffers()[0].String())
+}
+
+func TestGetFailedTransactionsHashes(t *testing.T) {
+	t.Parallel()
+
+	txs := []*data.Transaction{
+		{Hash: "success", Status: transaction.TxStatusSuccess.String()},
+		{Hash: "fail", Status: transaction.TxStatusFail.String()},
+		{Hash: "invalid", Status: transaction.TxStatusInvalid.String()},
+	}
+	require.Equal(t, map[string]struct{}{"fail": {}, "invalid": {}}, getFailedTransactionsHashes(txs))
+}
+
+func TestIsValidTokenIdentifier(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isValidTokenIdentifier("NFT-abcdef"))
+	require.True(t, isValidTokenIdentifier("TOKEN12345-012345"))
+	require.False(t, isValidTokenIdentifier("NF-abcdef"))
+	require.False(t, isValidTokenIdentifier("TOKEN123456-abcdef"))
+	require.False(t, isValidTokenIdentifier("nft-abcdef"))
+	require.False(t, isValidTokenIdentifier("NFT-ABCDEF"))
+	require.False(t, isValidTokenIdentifier("NFT-abcde"))
+	require.False(t, isValidTokenIdentifier("NFTabcdef"))
+	require.False(t, isValidTokenIdentifier(`NFT-abc"\n`))
+}
+
+func TestDecodeTokenAttributes(t *testing.T) {
+	t.Parallel()
+
+	tags, metaData := decodeTokenAttributes([]byte("metadata:QmHash;tags:a,,b ;other:value"))
+	require.Equal(t, []string{"a", "b"}, tags)
+	require.Equal(t, "QmHash", metaData)
+
+	tags, metaData = decodeTokenAttributes([]byte("random bytes"))
+	require.Nil(t, tags)
+	require.Equal(t, "", metaData)
+}
+
+func TestElasticProcessor_IndexTokensShouldWriteInTheNFTsIndex(t *testing.T) {
+	t.Parallel()
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[tokensIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	elasticProc := epInt.(*elasticProcessor)
+	elasticProc.nftsProcessor = createNFTsProcessor(nil)
+	pool := &indexer.Pool{
+		Txs: map[string]coreData.TransactionHandler{
+			"h1": &transaction.Transaction{SndAddr: []byte("owner"), Data: createNFTData(core.BuiltInFunctionESDTNFTBurn, "NFT-abcdef", 1, 2)},
+		},
+	}
+
+	err = elasticProc.indexTokens(5000, pool, nil)
+	require.Nil(t, err)
+	require.Empty(t, bulkRequests)
+
+	elasticProc.enabledIndexes[nftsIndex] = struct{}{}
+	err = elasticProc.indexTokens(5000, pool, nil)
+	require.Nil(t, err)
+	require.Len(t, bulkRequests, 1)
+	require.Contains(t, bulkRequests[nftsIndex], `{"update":{"_id":"NFT-abcdef-01"}}`)
+}
@@ -1,6 +1,8 @@
 package indexer
 
 import (
+	"context"
+
 	"github.com/ElrondNetwork/elrond-go-core/data"
 	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
 )
@@ -38,6 +40,16 @@ func (ni *NilIndexer) SaveValidatorsPubKeys(_ map[uint32][][]byte, _ uint32) {
 func (ni *NilIndexer) SaveAccounts(_ uint64, _ []data.UserAccountHandler) {
 }
 
+// WaitForSavedItems returns immediately as nothing is saved
+func (ni *NilIndexer) WaitForSavedItems(_ context.Context) error {
+	return nil
+}
+
+// NotifyWhenSaved calls the provided handler right away as nothing is saved
+func (ni *NilIndexer) NotifyWhenSaved(handler func()) {
+	handler()
+}
+
 // Close will do nothing
 func (ni *NilIndexer) Close() error {
 	return nil
@@ -0,0 +1,146 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+)
+
+const (
+	notarizedAtSourceField      = "notarizedAtSourceInMetaNonce"
+	notarizedAtDestinationField = "notarizedAtDestinationInMetaNonce"
+
+	setNotarizationScript = `ctx._source[params.field] = params.nonce`
+)
+
+// getNotarizedMiniblocksHashes returns the hashes of the miniblocks of the shard headers notarized by the provided
+// metachain header, split by the side on which the notarized shard processed them
+func getNotarizedMiniblocksHashes(header coreData.HeaderHandler, notarizedHeadersHashes []string) ([]string, []string) {
+	sourceHashes := make([]string, 0)
+	destinationHashes := make([]string, 0)
+	metaBlock, ok := header.(*block.MetaBlock)
+	if !ok {
+		return sourceHashes, destinationHashes
+	}
+
+	notarizedHeaders := make(map[string]struct{}, len(notarizedHeadersHashes))
+	for _, headerHash := range notarizedHeadersHashes {
+		notarizedHeaders[headerHash] = struct{}{}
+	}
+
+	for _, shardData := range metaBlock.ShardInfo {
+		_, isNotarized := notarizedHeaders[hex.EncodeToString(shardData.HeaderHash)]
+		if !isNotarized {
+			continue
+		}
+
+		for _, mbHeader := range shardData.ShardMiniBlockHeaders {
+			mbHash := hex.EncodeToString(mbHeader.Hash)
+			if mbHeader.SenderShardID == shardData.ShardID {
+				sourceHashes = append(sourceHashes, mbHash)
+			}
+			if mbHeader.ReceiverShardID == shardData.ShardID {
+				destinationHashes = append(destinationHashes, mbHash)
+			}
+		}
+	}
+
+	return sourceHashes, destinationHashes
+}
+
+// putMiniblocksNotarization adds the bulk operations that set the provided metachain nonce on the miniblocks. The
+// miniblocks that are not indexed yet are created with the nonce only and are completed by their shards
+func putMiniblocksNotarization(mbsHashes []string, field string, metaNonce uint64, buffSlice bulkBuffer) error {
+	script, err := json.Marshal(objectsMap{
+		"source": setNotarizationScript,
+		"lang":   "painless",
+		"params": objectsMap{
+			"field": field,
+			"nonce": metaNonce,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	doc, err := json.Marshal(objectsMap{field: metaNonce})
+	if err != nil {
+		return err
+	}
+
+	for _, mbHash := range mbsHashes {
+		err = buffSlice.PutUpsert(mbHash, script, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLateTransactionsNotarization returns, keyed by the transactions hashes, the metachain nonces found on the
+// miniblocks of the provided transactions, as the miniblocks were notarized before the transactions were indexed
+func getLateTransactionsNotarization(txs []*data.Transaction, miniblocks map[string]*data.Miniblock) map[string]objectsMap {
+	notarizations := make(map[string]objectsMap)
+	for _, tx := range txs {
+		mb, ok := miniblocks[tx.MBHash]
+		if !ok {
+			continue
+		}
+
+		fields := objectsMap{}
+		if mb.NotarizedAtSourceInMetaNonce > 0 {
+			fields[notarizedAtSourceField] = mb.NotarizedAtSourceInMetaNonce
+		}
+		if mb.NotarizedAtDestinationInMetaNonce > 0 {
+			fields[notarizedAtDestinationField] = mb.NotarizedAtDestinationInMetaNonce
+		}
+		if len(fields) > 0 {
+			notarizations[tx.Hash] = fields
+		}
+	}
+
+	return notarizations
+}
+
+// getDecodedMiniblocksMultiGet returns the miniblocks found by a multi get request, keyed by their hashes
+func getDecodedMiniblocksMultiGet(response objectsMap) map[string]*data.Miniblock {
+	miniblocks := make(map[string]*data.Miniblock)
+	interfaceSlice, ok := response["docs"].([]interface{})
+	if !ok {
+		return miniblocks
+	}
+
+	for _, element := range interfaceSlice {
+		obj, ok := element.(objectsMap)
+		if !ok {
+			continue
+		}
+
+		found, _ := obj["found"].(bool)
+		mbHash, _ := obj["_id"].(string)
+		if !found {
+			continue
+		}
+
+		serializedMb, err := json.Marshal(obj["_source"])
+		if err != nil {
+			continue
+		}
+
+		mb := &data.Miniblock{}
+		err = json.Unmarshal(serializedMb, mb)
+		if err != nil {
+			log.Debug("indexer: cannot decode miniblock", "hash", mbHash, "error", err)
+			continue
+		}
+
+		mb.Hash = mbHash
+		miniblocks[mbHash] = mb
+	}
+
+	return miniblocks
+}
@@ -0,0 +1,72 @@
+package indexer
+
+import (
+	"math/big"
+)
+
+const numDecimalsInFloatBalance = 10
+
+// numericValuesConverter converts the amounts of the native token in denominated numeric values, which can be summed
+// and range filtered. The amounts are rounded to numDecimalsInFloatBalance decimals with big integers, so the float
+// values are the closest ones to the rounded amounts. The optional scaled values hold the same rounded amounts as
+// longs, which make the aggregations exact. The zero value converts with no denomination and without scaled values
+type numericValuesConverter struct {
+	denomination    int
+	useScaledValues bool
+}
+
+func newNumericValuesConverter(denomination int, useScaledValues bool) numericValuesConverter {
+	if denomination < 0 {
+		denomination = 0
+	}
+
+	return numericValuesConverter{
+		denomination:    denomination,
+		useScaledValues: useScaledValues,
+	}
+}
+
+// computeFloat returns the denominated value of the provided amount
+func (nvc numericValuesConverter) computeFloat(value *big.Int) float64 {
+	if value == nil {
+		return 0
+	}
+
+	result, _ := big.NewRat(0, 1).SetFrac(nvc.computeRounded(value), pow10(numDecimalsInFloatBalance)).Float64()
+	return result
+}
+
+// computeScaled returns the denominated value of the provided amount multiplied by 10^numDecimalsInFloatBalance.
+// Nothing is returned if the scaled values are disabled or if the value does not fit in a long
+func (nvc numericValuesConverter) computeScaled(value *big.Int) *int64 {
+	if !nvc.useScaledValues || value == nil {
+		return nil
+	}
+
+	rounded := nvc.computeRounded(value)
+	if !rounded.IsInt64() {
+		log.Debug("indexer: value too large for a scaled numeric field", "value", value.String())
+		return nil
+	}
+
+	scaled := rounded.Int64()
+	return &scaled
+}
+
+// computeRounded returns value * 10^numDecimalsInFloatBalance / 10^denomination, rounded half away from zero
+func (nvc numericValuesConverter) computeRounded(value *big.Int) *big.Int {
+	divider := pow10(nvc.denomination)
+	numerator := big.NewInt(0).Mul(value, pow10(numDecimalsInFloatBalance))
+	quotient, remainder := big.NewInt(0).QuoRem(numerator, divider, big.NewInt(0))
+
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(divider) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return quotient
+}
+
+func pow10(exponent int) *big.Int {
+	return big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
@@ -0,0 +1,56 @@
+package indexer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericValuesConverter_ComputeFloat(t *testing.T) {
+	t.Parallel()
+
+	nvc := newNumericValuesConverter(18, false)
+
+	tests := []struct {
+		value  string
+		output float64
+	}{
+		{value: "0", output: 0},
+		{value: "1", output: 0},
+		{value: "50000000", output: 0.0000000001},
+		{value: "49999999", output: 0},
+		{value: "-50000000", output: -0.0000000001},
+		{value: "1000000000000000000", output: 1},
+		{value: "123456789123456789123456789", output: 123456789.1234567891},
+		{value: "20000000000000000000000000001", output: 20000000000},
+	}
+	for _, tt := range tests {
+		value, _ := big.NewInt(0).SetString(tt.value, 10)
+		require.Equal(t, tt.output, nvc.computeFloat(value), tt.value)
+	}
+
+	require.Equal(t, float64(0), nvc.computeFloat(nil))
+	require.Equal(t, float64(12), numericValuesConverter{}.computeFloat(big.NewInt(12)))
+}
+
+func TestNumericValuesConverter_ComputeScaled(t *testing.T) {
+	t.Parallel()
+
+	value, _ := big.NewInt(0).SetString("123456789123456789123", 10)
+
+	nvc := newNumericValuesConverter(18, false)
+	require.Nil(t, nvc.computeScaled(value))
+
+	nvc = newNumericValuesConverter(18, true)
+	scaled := nvc.computeScaled(value)
+	require.NotNil(t, scaled)
+	require.Equal(t, int64(1234567891235), *scaled)
+	require.Nil(t, nvc.computeScaled(nil))
+
+	tooLarge, _ := big.NewInt(0).SetString("1000000000000000000000000000", 10)
+	require.Nil(t, nvc.computeScaled(tooLarge))
+
+	nvc = newNumericValuesConverter(-1, true)
+	require.Equal(t, int64(70000000000), *nvc.computeScaled(big.NewInt(7)))
+}
@@ -0,0 +1,35 @@
+package payloads
+
+import "math/big"
+
+// Account holds the account fields that are indexed. It implements the UserAccountHandler interface so it can be
+// passed directly to the indexer
+type Account struct {
+	Address []byte   `json:"address"`
+	Nonce   uint64   `json:"nonce"`
+	Balance *big.Int `json:"balance"`
+}
+
+// GetBalance returns the balance of the account
+func (a *Account) GetBalance() *big.Int {
+	if a.Balance == nil {
+		return big.NewInt(0)
+	}
+
+	return a.Balance
+}
+
+// GetNonce returns the nonce of the account
+func (a *Account) GetNonce() uint64 {
+	return a.Nonce
+}
+
+// AddressBytes returns the address of the account
+func (a *Account) AddressBytes() []byte {
+	return a.Address
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (a *Account) IsInterfaceNil() bool {
+	return a == nil
+}
@@ -0,0 +1,472 @@
+package payloads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/check"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/marshal"
+)
+
+var errPayloadRejected = errors.New("payload rejected by the indexer service")
+
+// defaultMaxPendingPayloads is the limit of the payloads waiting to be saved used when none is configured
+const defaultMaxPendingPayloads = 10000
+
+// ArgsIndexerClient holds the arguments needed to create a new indexer client. When MaxPendingPayloads payloads wait
+// to be saved, the new payloads are dropped if DropWhenFull is set, otherwise the calls block until there is room
+type ArgsIndexerClient struct {
+	URL                string
+	Marshalizer        marshal.Marshalizer
+	RetryInterval      time.Duration
+	HTTPClient         *http.Client
+	MaxPendingPayloads int
+	DropWhenFull       bool
+}
+
+type savedHandler struct {
+	id      uint64
+	handler func()
+}
+
+// indexerClient is used by the node instead of the in-process indexer. Every call is converted into a payload that is
+// sent to the indexer service. The payloads are sent in order and are kept until the indexer service reports them as
+// saved, so they can be resent if the indexer service restarts before saving them
+type indexerClient struct {
+	creator       *payloadsCreator
+	url           string
+	retryInterval time.Duration
+	httpClient    *http.Client
+
+	mutPending   sync.Mutex
+	pending      []*Payload
+	numSent      int
+	onSaved      []savedHandler
+	maxPending   int
+	dropWhenFull bool
+	lastID       uint64
+	chNewItem    chan struct{}
+	chFreeSlot   chan struct{}
+	ctx          context.Context
+	cancelFunc   func()
+	chSendEnded  chan struct{}
+}
+
+// NewIndexerClient will create a new indexer client that sends the payloads to the indexer service
+func NewIndexerClient(args ArgsIndexerClient) (*indexerClient, error) {
+	if args.URL == "" {
+		return nil, ErrEmptyURL
+	}
+	if check.IfNil(args.Marshalizer) {
+		return nil, ErrNilMarshalizer
+	}
+	if args.RetryInterval <= 0 {
+		return nil, ErrInvalidRetryInterval
+	}
+
+	httpClient := args.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	maxPending := args.MaxPendingPayloads
+	if maxPending <= 0 {
+		maxPending = defaultMaxPendingPayloads
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	ic := &indexerClient{
+		creator:       &payloadsCreator{marshalizer: args.Marshalizer},
+		url:           strings.TrimSuffix(args.URL, "/") + PayloadsPath,
+		retryInterval: args.RetryInterval,
+		httpClient:    httpClient,
+		pending:       make([]*Payload, 0),
+		onSaved:       make([]savedHandler, 0),
+		maxPending:    maxPending,
+		dropWhenFull:  args.DropWhenFull,
+		// the ids start from the current time so the payloads sent after a restart are not considered duplicates
+		lastID:      uint64(time.Now().UnixNano()),
+		chNewItem:   make(chan struct{}, 1),
+		chFreeSlot:  make(chan struct{}, 1),
+		ctx:         ctx,
+		cancelFunc:  cancelFunc,
+		chSendEnded: make(chan struct{}),
+	}
+
+	go ic.sendPayloads(ctx)
+
+	return ic, nil
+}
+
+// SaveBlock will send the block data to the indexer service
+func (ic *indexerClient) SaveBlock(args *indexer.ArgsSaveBlockData) {
+	if args == nil {
+		return
+	}
+
+	data, err := ic.creator.createSaveBlockData(args)
+	ic.addPayload(SaveBlockType, data, err)
+}
+
+// RevertIndexedBlock will send the reverted block to the indexer service
+func (ic *indexerClient) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) {
+	data, err := ic.creator.createRevertBlockData(header, body)
+	ic.addPayload(RevertBlockType, data, err)
+}
+
+// SaveRoundsInfo will send the rounds information to the indexer service
+func (ic *indexerClient) SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) {
+	data, err := json.Marshal(&ArgsSaveRounds{RoundsInfo: roundsInfos})
+	ic.addPayload(SaveRoundsType, data, err)
+}
+
+// SaveValidatorsPubKeys will send the validators public keys to the indexer service
+func (ic *indexerClient) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) {
+	data, err := json.Marshal(&ArgsSaveValidatorsPubKeys{ValidatorsPubKeys: validatorsPubKeys, Epoch: epoch})
+	ic.addPayload(SaveValidatorsPubKeysType, data, err)
+}
+
+// SaveValidatorsRating will send the validators rating to the indexer service
+func (ic *indexerClient) SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) {
+	data, err := json.Marshal(&ArgsSaveValidatorsRating{IndexID: indexID, InfoRating: infoRating})
+	ic.addPayload(SaveValidatorsRatingType, data, err)
+}
+
+// SaveAccounts will send the accounts to the indexer service
+func (ic *indexerClient) SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler) {
+	data, err := ic.creator.createSaveAccountsData(blockTimestamp, acc)
+	ic.addPayload(SaveAccountsType, data, err)
+}
+
+func (ic *indexerClient) addPayload(payloadType string, data []byte, err error) {
+	if err != nil {
+		log.Warn("indexerClient: cannot create payload", "type", payloadType, "error", err)
+		return
+	}
+
+	ic.mutPending.Lock()
+	for len(ic.pending) >= ic.maxPending {
+		ic.mutPending.Unlock()
+		if ic.dropWhenFull {
+			log.Error("indexerClient: too many payloads wait to be saved, payload dropped", "type", payloadType)
+			return
+		}
+
+		select {
+		case <-ic.ctx.Done():
+			log.Warn("indexerClient: closed while waiting to add a payload, payload dropped", "type", payloadType)
+			return
+		case <-ic.chFreeSlot:
+		}
+		ic.mutPending.Lock()
+	}
+
+	ic.lastID++
+	ic.pending = append(ic.pending, &Payload{
+		ID:   ic.lastID,
+		Type: payloadType,
+		Data: data,
+	})
+	hasFreeSlots := len(ic.pending) < ic.maxPending
+	ic.mutPending.Unlock()
+
+	// another call might wait for the slot signaled before this one took it
+	if hasFreeSlots {
+		ic.signalFreeSlot()
+	}
+
+	select {
+	case ic.chNewItem <- struct{}{}:
+	default:
+	}
+}
+
+// nextToSend returns the first payload that was not acknowledged yet, with the ID of the oldest unsaved payload. The
+// returned bool is true if there are payloads that were not saved
+func (ic *indexerClient) nextToSend() (*Payload, bool) {
+	ic.mutPending.Lock()
+	defer ic.mutPending.Unlock()
+
+	hasUnsaved := len(ic.pending) > 0
+	if ic.numSent >= len(ic.pending) {
+		return nil, hasUnsaved
+	}
+
+	payload := *ic.pending[ic.numSent]
+	payload.OldestUnsavedID = ic.pending[0].ID
+
+	return &payload, hasUnsaved
+}
+
+func (ic *indexerClient) markSent() {
+	ic.mutPending.Lock()
+	ic.numSent++
+	ic.mutPending.Unlock()
+}
+
+// removeRejected drops the first payload that was not acknowledged yet, as the indexer service will never accept it
+func (ic *indexerClient) removeRejected() {
+	ic.mutPending.Lock()
+	ic.pending = append(ic.pending[:ic.numSent], ic.pending[ic.numSent+1:]...)
+	handlers := ic.extractSavedHandlersUnprotected()
+	ic.mutPending.Unlock()
+
+	callHandlers(handlers)
+	ic.signalFreeSlot()
+}
+
+// removeSaved drops the payloads saved by the indexer service, which are always the oldest ones
+func (ic *indexerClient) removeSaved(savedID uint64) {
+	ic.mutPending.Lock()
+	numSaved := 0
+	for numSaved < len(ic.pending) && ic.pending[numSaved].ID <= savedID {
+		numSaved++
+	}
+	ic.pending = ic.pending[numSaved:]
+	ic.numSent -= numSaved
+	if ic.numSent < 0 {
+		ic.numSent = 0
+	}
+	handlers := ic.extractSavedHandlersUnprotected()
+	ic.mutPending.Unlock()
+
+	if numSaved == 0 {
+		return
+	}
+
+	callHandlers(handlers)
+	ic.signalFreeSlot()
+}
+
+func (ic *indexerClient) extractSavedHandlersUnprotected() []func() {
+	oldestUnsavedID := uint64(math.MaxUint64)
+	if len(ic.pending) > 0 {
+		oldestUnsavedID = ic.pending[0].ID
+	}
+
+	handlers := make([]func(), 0)
+	numCalled := 0
+	for numCalled < len(ic.onSaved) && ic.onSaved[numCalled].id < oldestUnsavedID {
+		handlers = append(handlers, ic.onSaved[numCalled].handler)
+		numCalled++
+	}
+	ic.onSaved = ic.onSaved[numCalled:]
+
+	return handlers
+}
+
+func callHandlers(handlers []func()) {
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// resendUnsaved will send again all the payloads that were not saved, starting with the oldest one
+func (ic *indexerClient) resendUnsaved() {
+	ic.mutPending.Lock()
+	ic.numSent = 0
+	ic.mutPending.Unlock()
+}
+
+// lastSentID returns the ID of the last acknowledged payload that was not saved yet, or 0 if there is none
+func (ic *indexerClient) lastSentID() uint64 {
+	ic.mutPending.Lock()
+	defer ic.mutPending.Unlock()
+
+	if ic.numSent == 0 {
+		return 0
+	}
+
+	return ic.pending[ic.numSent-1].ID
+}
+
+func (ic *indexerClient) signalFreeSlot() {
+	select {
+	case ic.chFreeSlot <- struct{}{}:
+	default:
+	}
+}
+
+// NumPendingPayloads returns the number of payloads that were not saved yet
+func (ic *indexerClient) NumPendingPayloads() int {
+	ic.mutPending.Lock()
+	defer ic.mutPending.Unlock()
+
+	return len(ic.pending)
+}
+
+func (ic *indexerClient) sendPayloads(ctx context.Context) {
+	defer close(ic.chSendEnded)
+
+	for {
+		payload, hasUnsaved := ic.nextToSend()
+		if payload != nil {
+			ic.sendPayload(ctx, payload)
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		// all the payloads were acknowledged, the ones waiting to be saved are checked from time to time
+		var chCheckSaved <-chan time.Time
+		if hasUnsaved {
+			chCheckSaved = time.After(ic.retryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ic.chNewItem:
+		case <-chCheckSaved:
+			ic.checkSaved(ctx)
+		}
+	}
+}
+
+func (ic *indexerClient) sendPayload(ctx context.Context, payload *Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Error("indexerClient: cannot marshal payload, payload dropped", "id", payload.ID, "type", payload.Type, "error", err)
+		ic.removeRejected()
+		return
+	}
+
+	ack, err := ic.doRequest(ctx, http.MethodPost, body)
+	if err == nil && ack.ID != payload.ID {
+		err = fmt.Errorf("%w: sent %d, acknowledged %d", ErrWrongAck, payload.ID, ack.ID)
+	}
+
+	switch {
+	case err == nil:
+		ic.markSent()
+		ic.removeSaved(ack.Saved)
+	case errors.Is(err, errPayloadRejected):
+		log.Error("indexerClient: payload dropped", "id", payload.ID, "type", payload.Type, "error", err)
+		ic.removeRejected()
+	case errors.Is(err, errResendUnsaved):
+		log.Debug("indexerClient: resending the payloads that were not saved", "id", payload.ID, "oldest unsaved id", payload.OldestUnsavedID)
+		ic.resendUnsaved()
+	default:
+		log.Debug("indexerClient: cannot send payload, will retry", "id", payload.ID, "type", payload.Type, "error", err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(ic.retryInterval):
+		}
+	}
+}
+
+// checkSaved asks the indexer service which payloads were saved. If the indexer service did not process all the
+// acknowledged payloads, it restarted meanwhile and the payloads that were not saved are sent again
+func (ic *indexerClient) checkSaved(ctx context.Context) {
+	status, err := ic.doRequest(ctx, http.MethodGet, nil)
+	if err != nil {
+		log.Debug("indexerClient: cannot get the status of the indexer service", "error", err)
+		return
+	}
+
+	ic.removeSaved(status.Saved)
+	if status.ID < ic.lastSentID() {
+		log.Debug("indexerClient: the indexer service restarted, resending the payloads that were not saved", "last processed id", status.ID)
+		ic.resendUnsaved()
+	}
+}
+
+func (ic *indexerClient) doRequest(ctx context.Context, method string, body []byte) (*Ack, error) {
+	req, err := http.NewRequestWithContext(ctx, method, ic.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ic.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: %s", errPayloadRejected, string(respBody))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("%w: %s", errResendUnsaved, string(respBody))
+	default:
+		return nil, fmt.Errorf("indexer service responded with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	ack := &Ack{}
+	err = json.Unmarshal(respBody, ack)
+	if err != nil {
+		return nil, err
+	}
+
+	return ack, nil
+}
+
+// NotifyWhenSaved will call the provided handler once the indexer service saved all the payloads added before the call
+func (ic *indexerClient) NotifyWhenSaved(handler func()) {
+	ic.mutPending.Lock()
+	if len(ic.pending) == 0 {
+		ic.mutPending.Unlock()
+		handler()
+		return
+	}
+
+	ic.onSaved = append(ic.onSaved, savedHandler{id: ic.lastID, handler: handler})
+	ic.mutPending.Unlock()
+}
+
+// WaitForSavedItems will block until the indexer service saved all the payloads, or until the provided context is done
+func (ic *indexerClient) WaitForSavedItems(ctx context.Context) error {
+	for ic.NumPendingPayloads() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ic.retryInterval):
+		}
+	}
+
+	return nil
+}
+
+// Close will stop sending the payloads. The payloads that were not saved are lost
+func (ic *indexerClient) Close() error {
+	ic.cancelFunc()
+	<-ic.chSendEnded
+
+	numPending := ic.NumPendingPayloads()
+	if numPending > 0 {
+		log.Warn("indexerClient: closed with payloads that were not saved", "num payloads", numPending)
+	}
+
+	return nil
+}
+
+// IsNilIndexer returns false because this is not a nil indexer
+func (ic *indexerClient) IsNilIndexer() bool {
+	return false
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ic *indexerClient) IsInterfaceNil() bool {
+	return ic == nil
+}
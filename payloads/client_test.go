@@ -0,0 +1,253 @@
+package payloads
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	indexerGo "github.com/ElrondNetwork/elastic-indexer-go"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsIndexerClient(url string) ArgsIndexerClient {
+	return ArgsIndexerClient{
+		URL:           url,
+		Marshalizer:   &mock.MarshalizerMock{},
+		RetryInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestNewIndexerClient(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsIndexerClient("")
+	_, err := NewIndexerClient(args)
+	require.Equal(t, ErrEmptyURL, err)
+
+	args = createMockArgsIndexerClient("http://localhost")
+	args.Marshalizer = nil
+	_, err = NewIndexerClient(args)
+	require.Equal(t, ErrNilMarshalizer, err)
+
+	args = createMockArgsIndexerClient("http://localhost")
+	args.RetryInterval = 0
+	_, err = NewIndexerClient(args)
+	require.Equal(t, ErrInvalidRetryInterval, err)
+
+	args = createMockArgsIndexerClient("http://localhost")
+	client, err := NewIndexerClient(args)
+	require.Nil(t, err)
+	require.False(t, client.IsNilIndexer())
+
+	var _ indexerGo.Indexer = client
+	require.Nil(t, client.Close())
+}
+
+func TestIndexerClient_ResendsUntilAcknowledged(t *testing.T) {
+	t.Parallel()
+
+	receivedRounds := make(chan []*indexer.RoundInfo, 10)
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) {
+			receivedRounds <- roundsInfos
+		},
+	})
+	receiver, _ := NewPayloadsReceiver(processor)
+
+	numRequests := int32(0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, PayloadsPath, r.URL.Path)
+
+		// the first request is processed but the acknowledgement is lost, the second one fails
+		switch atomic.AddInt32(&numRequests, 1) {
+		case 1:
+			receiver.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			receiver.ServeHTTP(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	client, _ := NewIndexerClient(createMockArgsIndexerClient(ts.URL))
+	client.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 1}})
+	client.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 2}})
+
+	require.Equal(t, uint64(1), (<-receivedRounds)[0].Index)
+	require.Equal(t, uint64(2), (<-receivedRounds)[0].Index)
+	require.Eventually(t, func() bool {
+		return client.NumPendingPayloads() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Len(t, receivedRounds, 0)
+	require.Equal(t, int32(4), atomic.LoadInt32(&numRequests))
+
+	require.Nil(t, client.Close())
+}
+
+type savingIndexerService struct {
+	mutex          sync.Mutex
+	receiver       http.Handler
+	receivedRounds []uint64
+	unsaved        []func()
+}
+
+// restart replaces the receiver, losing the payloads that were processed but not saved
+func (sis *savingIndexerService) restart() {
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) {
+			sis.receivedRounds = append(sis.receivedRounds, roundsInfos[0].Index)
+		},
+		NotifyWhenSavedCalled: func(handler func()) {
+			sis.unsaved = append(sis.unsaved, handler)
+		},
+	})
+	receiver, _ := NewPayloadsReceiver(processor)
+
+	sis.mutex.Lock()
+	sis.receiver = receiver
+	sis.unsaved = nil
+	sis.mutex.Unlock()
+}
+
+func (sis *savingIndexerService) save() {
+	sis.mutex.Lock()
+	defer sis.mutex.Unlock()
+
+	for _, handler := range sis.unsaved {
+		handler()
+	}
+	sis.unsaved = nil
+}
+
+func (sis *savingIndexerService) getReceivedRounds() []uint64 {
+	sis.mutex.Lock()
+	defer sis.mutex.Unlock()
+
+	return append([]uint64{}, sis.receivedRounds...)
+}
+
+func (sis *savingIndexerService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sis.mutex.Lock()
+	defer sis.mutex.Unlock()
+
+	sis.receiver.ServeHTTP(w, r)
+}
+
+func TestIndexerClient_KeepsThePayloadsUntilSaved(t *testing.T) {
+	t.Parallel()
+
+	service := &savingIndexerService{}
+	service.restart()
+	ts := httptest.NewServer(service)
+	defer ts.Close()
+
+	client, _ := NewIndexerClient(createMockArgsIndexerClient(ts.URL))
+	client.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 1}})
+	client.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 2}})
+
+	numNotified := int32(0)
+	client.NotifyWhenSaved(func() {
+		atomic.AddInt32(&numNotified, 1)
+	})
+
+	require.Eventually(t, func() bool {
+		return len(service.getReceivedRounds()) == 2
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 2, client.NumPendingPayloads())
+	require.Equal(t, int32(0), atomic.LoadInt32(&numNotified))
+
+	// the acknowledged payloads are resent as the restarted service did not save them
+	service.restart()
+	require.Eventually(t, func() bool {
+		return len(service.getReceivedRounds()) == 4
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, []uint64{1, 2, 1, 2}, service.getReceivedRounds())
+	require.Equal(t, 2, client.NumPendingPayloads())
+
+	service.save()
+	require.Eventually(t, func() bool {
+		return client.NumPendingPayloads() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&numNotified))
+
+	// nothing waits to be saved, so the handler is called right away
+	client.NotifyWhenSaved(func() {
+		atomic.AddInt32(&numNotified, 1)
+	})
+	require.Equal(t, int32(2), atomic.LoadInt32(&numNotified))
+
+	require.Nil(t, client.Close())
+}
+
+func TestIndexerClient_RejectedPayloadIsDropped(t *testing.T) {
+	t.Parallel()
+
+	numRequests := int32(0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&numRequests, 1)
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	client, _ := NewIndexerClient(createMockArgsIndexerClient(ts.URL))
+	client.SaveValidatorsRating("0_1", nil)
+
+	require.Eventually(t, func() bool {
+		return client.NumPendingPayloads() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+
+	require.Nil(t, client.Close())
+}
+
+func TestIndexerClient_PendingPayloadsLimit(t *testing.T) {
+	t.Parallel()
+
+	chRelease := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-chRelease
+		http.Error(w, "not available", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	defer close(chRelease)
+
+	args := createMockArgsIndexerClient(ts.URL)
+	args.MaxPendingPayloads = 2
+	args.DropWhenFull = true
+	client, _ := NewIndexerClient(args)
+	for i := 0; i < 5; i++ {
+		client.SaveValidatorsRating("0_1", nil)
+	}
+	require.Equal(t, 2, client.NumPendingPayloads())
+	require.Nil(t, client.Close())
+
+	args.DropWhenFull = false
+	client, _ = NewIndexerClient(args)
+	client.SaveValidatorsRating("0_1", nil)
+	client.SaveValidatorsRating("0_1", nil)
+
+	chAdded := make(chan struct{})
+	go func() {
+		client.SaveValidatorsRating("0_1", nil)
+		close(chAdded)
+	}()
+
+	select {
+	case <-chAdded:
+		require.Fail(t, "the call should block while the pending payloads reached the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the blocked call returns once the client is closed
+	require.Nil(t, client.Close())
+	<-chAdded
+	require.Equal(t, 2, client.NumPendingPayloads())
+}
@@ -0,0 +1,158 @@
+package payloads
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/check"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/marshal"
+)
+
+// payloadsCreator converts the indexer calls into payloads. The blockchain structures are marshalled with the
+// marshalizer of the node while the payload data is always json
+type payloadsCreator struct {
+	marshalizer marshal.Marshalizer
+}
+
+func (pc *payloadsCreator) createSaveBlockData(args *indexer.ArgsSaveBlockData) ([]byte, error) {
+	headerType, header, err := pc.marshalHeader(args.Header)
+	if err != nil {
+		return nil, err
+	}
+	body, err := pc.marshalBody(args.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	saveBlock := &ArgsSaveBlock{
+		HeaderHash:             args.HeaderHash,
+		HeaderType:             headerType,
+		Header:                 header,
+		Body:                   body,
+		SignersIndexes:         args.SignersIndexes,
+		NotarizedHeadersHashes: args.NotarizedHeadersHashes,
+	}
+
+	pool := args.TransactionsPool
+	if pool == nil {
+		pool = &indexer.Pool{}
+	}
+	txsMaps := []struct {
+		txs        map[string]coreData.TransactionHandler
+		serialized *map[string][]byte
+	}{
+		{pool.Txs, &saveBlock.Txs},
+		{pool.Scrs, &saveBlock.Scrs},
+		{pool.Rewards, &saveBlock.Rewards},
+		{pool.Invalid, &saveBlock.Invalid},
+		{pool.Receipts, &saveBlock.Receipts},
+	}
+	for _, txsMap := range txsMaps {
+		*txsMap.serialized, err = pc.marshalTxs(txsMap.txs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	saveBlock.Logs, err = pc.marshalLogs(pool.Logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(saveBlock)
+}
+
+func (pc *payloadsCreator) createRevertBlockData(header coreData.HeaderHandler, body coreData.BodyHandler) ([]byte, error) {
+	headerType, serializedHeader, err := pc.marshalHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	serializedBody, err := pc.marshalBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&ArgsRevertBlock{
+		HeaderType: headerType,
+		Header:     serializedHeader,
+		Body:       serializedBody,
+	})
+}
+
+func (pc *payloadsCreator) createSaveAccountsData(blockTimestamp uint64, accounts []coreData.UserAccountHandler) ([]byte, error) {
+	args := &ArgsSaveAccounts{
+		BlockTimestamp: blockTimestamp,
+		Accounts:       make([]*Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		if check.IfNil(account) {
+			continue
+		}
+
+		args.Accounts = append(args.Accounts, &Account{
+			Address: account.AddressBytes(),
+			Nonce:   account.GetNonce(),
+			Balance: account.GetBalance(),
+		})
+	}
+
+	return json.Marshal(args)
+}
+
+func (pc *payloadsCreator) marshalHeader(header coreData.HeaderHandler) (string, []byte, error) {
+	var headerType string
+	switch header.(type) {
+	case *block.Header:
+		headerType = ShardHeaderType
+	case *block.MetaBlock:
+		headerType = MetaHeaderType
+	default:
+		return "", nil, fmt.Errorf("%w: %T", ErrUnknownHeaderType, header)
+	}
+
+	serializedHeader, err := pc.marshalizer.Marshal(header)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return headerType, serializedHeader, nil
+}
+
+func (pc *payloadsCreator) marshalBody(body coreData.BodyHandler) ([]byte, error) {
+	if check.IfNil(body) {
+		return nil, nil
+	}
+
+	return pc.marshalizer.Marshal(body)
+}
+
+func (pc *payloadsCreator) marshalTxs(txs map[string]coreData.TransactionHandler) (map[string][]byte, error) {
+	serializedTxs := make(map[string][]byte, len(txs))
+	for hash, tx := range txs {
+		serializedTx, err := pc.marshalizer.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		serializedTxs[hash] = serializedTx
+	}
+
+	return serializedTxs, nil
+}
+
+func (pc *payloadsCreator) marshalLogs(logs map[string]coreData.LogHandler) (map[string][]byte, error) {
+	serializedLogs := make(map[string][]byte, len(logs))
+	for hash, txLog := range logs {
+		serializedLog, err := pc.marshalizer.Marshal(txLog)
+		if err != nil {
+			return nil, err
+		}
+
+		serializedLogs[hash] = serializedLog
+	}
+
+	return serializedLogs, nil
+}
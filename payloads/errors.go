@@ -0,0 +1,30 @@
+package payloads
+
+import "errors"
+
+// ErrNilMarshalizer signals that a nil marshalizer has been provided
+var ErrNilMarshalizer = errors.New("nil marshalizer")
+
+// ErrNilIndexer signals that a nil indexer has been provided
+var ErrNilIndexer = errors.New("nil indexer")
+
+// ErrUnknownPayloadType signals that a payload with an unknown type has been received
+var ErrUnknownPayloadType = errors.New("unknown payload type")
+
+// ErrUnsupportedPayloadType signals that a payload with a type the indexer service cannot index has been received
+var ErrUnsupportedPayloadType = errors.New("unsupported payload type")
+
+// ErrUnknownHeaderType signals that a header with an unknown type has been received
+var ErrUnknownHeaderType = errors.New("unknown header type")
+
+// ErrNilPayload signals that a nil payload has been received
+var ErrNilPayload = errors.New("nil payload")
+
+// ErrEmptyURL signals that an empty url of the indexer service has been provided
+var ErrEmptyURL = errors.New("empty indexer service url")
+
+// ErrInvalidRetryInterval signals that an invalid retry interval has been provided
+var ErrInvalidRetryInterval = errors.New("invalid retry interval")
+
+// ErrWrongAck signals that the indexer service acknowledged another payload than the one that was sent
+var ErrWrongAck = errors.New("wrong payload acknowledged")
@@ -0,0 +1,17 @@
+package payloads
+
+import (
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+)
+
+// IndexerHandler defines the indexer methods that are called for the received payloads
+type IndexerHandler interface {
+	SaveBlock(args *indexer.ArgsSaveBlockData)
+	RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler)
+	SaveRoundsInfo(roundsInfos []*indexer.RoundInfo)
+	SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32)
+	SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo)
+	NotifyWhenSaved(handler func())
+	IsInterfaceNil() bool
+}
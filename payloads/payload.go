@@ -0,0 +1,91 @@
+package payloads
+
+import (
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+)
+
+const (
+	// SaveBlockType is the type of the payloads that hold the data of a committed block
+	SaveBlockType = "saveBlock"
+	// RevertBlockType is the type of the payloads that hold a reverted block
+	RevertBlockType = "revertBlock"
+	// SaveRoundsType is the type of the payloads that hold the rounds information
+	SaveRoundsType = "saveRounds"
+	// SaveValidatorsRatingType is the type of the payloads that hold the validators rating
+	SaveValidatorsRatingType = "saveValidatorsRating"
+	// SaveValidatorsPubKeysType is the type of the payloads that hold the validators public keys of an epoch
+	SaveValidatorsPubKeysType = "saveValidatorsPubKeys"
+	// SaveAccountsType is the type of the payloads that hold a set of accounts
+	SaveAccountsType = "saveAccounts"
+)
+
+const (
+	// ShardHeaderType identifies a shard block header
+	ShardHeaderType = "shard"
+	// MetaHeaderType identifies a metachain block header
+	MetaHeaderType = "meta"
+)
+
+// Payload is the envelope sent from the node to the indexer service. The ID is used to acknowledge the payload and
+// to avoid indexing twice a payload that was resent. OldestUnsavedID is the ID of the oldest payload the node still
+// keeps, so the indexer service can ask for all of them again after a restart
+type Payload struct {
+	ID              uint64 `json:"id"`
+	OldestUnsavedID uint64 `json:"oldestUnsavedID"`
+	Type            string `json:"type"`
+	Data            []byte `json:"data"`
+}
+
+// Ack is the response of the indexer service for a payload that was handed to the indexer. Saved is the highest ID of
+// the payloads whose content was saved, together with the content of all the payloads sent before it
+type Ack struct {
+	ID    uint64 `json:"id"`
+	Saved uint64 `json:"saved"`
+}
+
+// ArgsSaveBlock holds the data of a committed block. The header, the body and the transactions are marshalled with
+// the marshalizer of the node
+type ArgsSaveBlock struct {
+	HeaderHash             []byte            `json:"headerHash"`
+	HeaderType             string            `json:"headerType"`
+	Header                 []byte            `json:"header"`
+	Body                   []byte            `json:"body"`
+	SignersIndexes         []uint64          `json:"signersIndexes"`
+	NotarizedHeadersHashes []string          `json:"notarizedHeadersHashes"`
+	Txs                    map[string][]byte `json:"txs"`
+	Scrs                   map[string][]byte `json:"scrs"`
+	Rewards                map[string][]byte `json:"rewards"`
+	Invalid                map[string][]byte `json:"invalid"`
+	Receipts               map[string][]byte `json:"receipts"`
+	Logs                   map[string][]byte `json:"logs"`
+}
+
+// ArgsRevertBlock holds the marshalled header and body of a reverted block
+type ArgsRevertBlock struct {
+	HeaderType string `json:"headerType"`
+	Header     []byte `json:"header"`
+	Body       []byte `json:"body"`
+}
+
+// ArgsSaveRounds holds the rounds information
+type ArgsSaveRounds struct {
+	RoundsInfo []*indexer.RoundInfo `json:"roundsInfo"`
+}
+
+// ArgsSaveValidatorsRating holds the rating of the validators
+type ArgsSaveValidatorsRating struct {
+	IndexID    string                         `json:"indexID"`
+	InfoRating []*indexer.ValidatorRatingInfo `json:"infoRating"`
+}
+
+// ArgsSaveValidatorsPubKeys holds the validators public keys of an epoch
+type ArgsSaveValidatorsPubKeys struct {
+	ValidatorsPubKeys map[uint32][][]byte `json:"validatorsPubKeys"`
+	Epoch             uint32              `json:"epoch"`
+}
+
+// ArgsSaveAccounts holds the accounts to be saved
+type ArgsSaveAccounts struct {
+	BlockTimestamp uint64     `json:"blockTimestamp"`
+	Accounts       []*Account `json:"accounts"`
+}
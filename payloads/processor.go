@@ -0,0 +1,281 @@
+package payloads
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/check"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/data/receipt"
+	"github.com/ElrondNetwork/elrond-go-core/data/rewardTx"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-go-core/marshal"
+)
+
+type payloadsProcessor struct {
+	marshalizer marshal.Marshalizer
+	indexer     IndexerHandler
+	handlers    map[string]func(data []byte) error
+
+	mutSaved    sync.RWMutex
+	lastSavedID uint64
+}
+
+// NewPayloadsProcessor will create a new instance of payloads processor that unmarshalls the received payloads and
+// passes them to the provided indexer
+func NewPayloadsProcessor(marshalizer marshal.Marshalizer, indexerHandler IndexerHandler) (*payloadsProcessor, error) {
+	if check.IfNil(marshalizer) {
+		return nil, ErrNilMarshalizer
+	}
+	if check.IfNil(indexerHandler) {
+		return nil, ErrNilIndexer
+	}
+
+	pp := &payloadsProcessor{
+		marshalizer: marshalizer,
+		indexer:     indexerHandler,
+	}
+	pp.handlers = map[string]func(data []byte) error{
+		SaveBlockType:             pp.saveBlock,
+		RevertBlockType:           pp.revertBlock,
+		SaveRoundsType:            pp.saveRounds,
+		SaveValidatorsRatingType:  pp.saveValidatorsRating,
+		SaveValidatorsPubKeysType: pp.saveValidatorsPubKeys,
+		SaveAccountsType:          pp.saveAccounts,
+	}
+
+	return pp, nil
+}
+
+// ProcessPayload will unmarshal the provided payload and will pass its content to the indexer. The payload becomes
+// the last saved one once the indexer saved its content, without forcing the blocks batch to be sent
+func (pp *payloadsProcessor) ProcessPayload(payload *Payload) error {
+	if payload == nil {
+		return ErrNilPayload
+	}
+
+	handler, ok := pp.handlers[payload.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPayloadType, payload.Type)
+	}
+
+	err := handler(payload.Data)
+	if err != nil {
+		return err
+	}
+
+	id := payload.ID
+	pp.indexer.NotifyWhenSaved(func() {
+		pp.setLastSavedID(id)
+	})
+
+	return nil
+}
+
+func (pp *payloadsProcessor) setLastSavedID(id uint64) {
+	pp.mutSaved.Lock()
+	if id > pp.lastSavedID {
+		pp.lastSavedID = id
+	}
+	pp.mutSaved.Unlock()
+}
+
+// LastSavedID returns the highest ID of the processed payloads whose content was saved by the indexer
+func (pp *payloadsProcessor) LastSavedID() uint64 {
+	pp.mutSaved.RLock()
+	defer pp.mutSaved.RUnlock()
+
+	return pp.lastSavedID
+}
+
+func (pp *payloadsProcessor) saveBlock(data []byte) error {
+	args := &ArgsSaveBlock{}
+	err := json.Unmarshal(data, args)
+	if err != nil {
+		return err
+	}
+
+	header, err := pp.unmarshalHeader(args.HeaderType, args.Header)
+	if err != nil {
+		return err
+	}
+	body, err := pp.unmarshalBody(args.Body)
+	if err != nil {
+		return err
+	}
+
+	pool := &indexer.Pool{}
+	pool.Txs, err = pp.unmarshalTxs(args.Txs, func() coreData.TransactionHandler { return &transaction.Transaction{} })
+	if err != nil {
+		return err
+	}
+	pool.Scrs, err = pp.unmarshalTxs(args.Scrs, func() coreData.TransactionHandler { return &smartContractResult.SmartContractResult{} })
+	if err != nil {
+		return err
+	}
+	pool.Rewards, err = pp.unmarshalTxs(args.Rewards, func() coreData.TransactionHandler { return &rewardTx.RewardTx{} })
+	if err != nil {
+		return err
+	}
+	pool.Invalid, err = pp.unmarshalTxs(args.Invalid, func() coreData.TransactionHandler { return &transaction.Transaction{} })
+	if err != nil {
+		return err
+	}
+	pool.Receipts, err = pp.unmarshalTxs(args.Receipts, func() coreData.TransactionHandler { return &receipt.Receipt{} })
+	if err != nil {
+		return err
+	}
+	pool.Logs, err = pp.unmarshalLogs(args.Logs)
+	if err != nil {
+		return err
+	}
+
+	pp.indexer.SaveBlock(&indexer.ArgsSaveBlockData{
+		HeaderHash:             args.HeaderHash,
+		Body:                   body,
+		Header:                 header,
+		SignersIndexes:         args.SignersIndexes,
+		NotarizedHeadersHashes: args.NotarizedHeadersHashes,
+		TransactionsPool:       pool,
+	})
+
+	return nil
+}
+
+func (pp *payloadsProcessor) revertBlock(data []byte) error {
+	args := &ArgsRevertBlock{}
+	err := json.Unmarshal(data, args)
+	if err != nil {
+		return err
+	}
+
+	header, err := pp.unmarshalHeader(args.HeaderType, args.Header)
+	if err != nil {
+		return err
+	}
+	body, err := pp.unmarshalBody(args.Body)
+	if err != nil {
+		return err
+	}
+
+	pp.indexer.RevertIndexedBlock(header, body)
+
+	return nil
+}
+
+func (pp *payloadsProcessor) saveRounds(data []byte) error {
+	args := &ArgsSaveRounds{}
+	err := json.Unmarshal(data, args)
+	if err != nil {
+		return err
+	}
+
+	pp.indexer.SaveRoundsInfo(args.RoundsInfo)
+
+	return nil
+}
+
+func (pp *payloadsProcessor) saveValidatorsRating(data []byte) error {
+	args := &ArgsSaveValidatorsRating{}
+	err := json.Unmarshal(data, args)
+	if err != nil {
+		return err
+	}
+
+	pp.indexer.SaveValidatorsRating(args.IndexID, args.InfoRating)
+
+	return nil
+}
+
+func (pp *payloadsProcessor) saveValidatorsPubKeys(data []byte) error {
+	args := &ArgsSaveValidatorsPubKeys{}
+	err := json.Unmarshal(data, args)
+	if err != nil {
+		return err
+	}
+
+	pp.indexer.SaveValidatorsPubKeys(args.ValidatorsPubKeys, args.Epoch)
+
+	return nil
+}
+
+// saveAccounts rejects the accounts, as the accounts and accountshistory indexes need the state of the node, which
+// the indexer service does not have
+func (pp *payloadsProcessor) saveAccounts(_ []byte) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedPayloadType, SaveAccountsType)
+}
+
+func (pp *payloadsProcessor) unmarshalHeader(headerType string, serializedHeader []byte) (coreData.HeaderHandler, error) {
+	var header coreData.HeaderHandler
+	switch headerType {
+	case ShardHeaderType:
+		header = &block.Header{}
+	case MetaHeaderType:
+		header = &block.MetaBlock{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHeaderType, headerType)
+	}
+
+	err := pp.marshalizer.Unmarshal(header, serializedHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+func (pp *payloadsProcessor) unmarshalBody(serializedBody []byte) (*block.Body, error) {
+	body := &block.Body{}
+	if len(serializedBody) == 0 {
+		return body, nil
+	}
+
+	err := pp.marshalizer.Unmarshal(body, serializedBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (pp *payloadsProcessor) unmarshalTxs(
+	serializedTxs map[string][]byte,
+	createTx func() coreData.TransactionHandler,
+) (map[string]coreData.TransactionHandler, error) {
+	txs := make(map[string]coreData.TransactionHandler, len(serializedTxs))
+	for hash, serializedTx := range serializedTxs {
+		tx := createTx()
+		err := pp.marshalizer.Unmarshal(tx, serializedTx)
+		if err != nil {
+			return nil, err
+		}
+
+		txs[hash] = tx
+	}
+
+	return txs, nil
+}
+
+func (pp *payloadsProcessor) unmarshalLogs(serializedLogs map[string][]byte) (map[string]coreData.LogHandler, error) {
+	logs := make(map[string]coreData.LogHandler, len(serializedLogs))
+	for hash, serializedLog := range serializedLogs {
+		txLog := &transaction.Log{}
+		err := pp.marshalizer.Unmarshal(txLog, serializedLog)
+		if err != nil {
+			return nil, err
+		}
+
+		logs[hash] = txLog
+	}
+
+	return logs, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pp *payloadsProcessor) IsInterfaceNil() bool {
+	return pp == nil
+}
@@ -0,0 +1,107 @@
+package payloads
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/ElrondNetwork/elrond-go-core/data/receipt"
+	"github.com/ElrondNetwork/elrond-go-core/data/rewardTx"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPayloadsProcessor(t *testing.T) {
+	t.Parallel()
+
+	pp, err := NewPayloadsProcessor(nil, &mock.IndexerStub{})
+	require.Nil(t, pp)
+	require.Equal(t, ErrNilMarshalizer, err)
+
+	pp, err = NewPayloadsProcessor(&mock.MarshalizerMock{}, nil)
+	require.Nil(t, pp)
+	require.Equal(t, ErrNilIndexer, err)
+
+	pp, err = NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{})
+	require.Nil(t, err)
+	require.False(t, pp.IsInterfaceNil())
+}
+
+func TestPayloadsProcessor_ProcessPayloadErrors(t *testing.T) {
+	t.Parallel()
+
+	pp, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{})
+
+	err := pp.ProcessPayload(nil)
+	require.Equal(t, ErrNilPayload, err)
+
+	err = pp.ProcessPayload(&Payload{Type: "unknown"})
+	require.True(t, errors.Is(err, ErrUnknownPayloadType))
+
+	err = pp.ProcessPayload(&Payload{Type: RevertBlockType, Data: []byte(`{"headerType":"unknown"}`)})
+	require.True(t, errors.Is(err, ErrUnknownHeaderType))
+}
+
+func TestPayloadsProcessor_SaveBlockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	marshalizer := &mock.MarshalizerMock{}
+	args := &indexer.ArgsSaveBlockData{
+		HeaderHash: []byte("hash"),
+		Header:     &block.MetaBlock{Nonce: 10, Round: 11},
+		Body: &block.Body{MiniBlocks: []*block.MiniBlock{
+			{TxHashes: [][]byte{[]byte("tx")}},
+		}},
+		SignersIndexes:         []uint64{1, 2},
+		NotarizedHeadersHashes: []string{"notarized"},
+		TransactionsPool: &indexer.Pool{
+			Txs:      map[string]coreData.TransactionHandler{"tx": &transaction.Transaction{Nonce: 1, Value: big.NewInt(5)}},
+			Scrs:     map[string]coreData.TransactionHandler{"scr": &smartContractResult.SmartContractResult{Nonce: 2, Value: big.NewInt(6)}},
+			Rewards:  map[string]coreData.TransactionHandler{"reward": &rewardTx.RewardTx{Round: 3, Value: big.NewInt(7)}},
+			Invalid:  map[string]coreData.TransactionHandler{"invalid": &transaction.Transaction{Nonce: 4, Value: big.NewInt(8)}},
+			Receipts: map[string]coreData.TransactionHandler{"receipt": &receipt.Receipt{Value: big.NewInt(9)}},
+			Logs:     map[string]coreData.LogHandler{"tx": &transaction.Log{Address: []byte("addr")}},
+		},
+	}
+
+	creator := &payloadsCreator{marshalizer: marshalizer}
+	data, err := creator.createSaveBlockData(args)
+	require.Nil(t, err)
+
+	var savedArgs *indexer.ArgsSaveBlockData
+	pp, _ := NewPayloadsProcessor(marshalizer, &mock.IndexerStub{
+		SaveBlockCalled: func(args *indexer.ArgsSaveBlockData) {
+			savedArgs = args
+		},
+	})
+
+	err = pp.ProcessPayload(&Payload{ID: 1, Type: SaveBlockType, Data: data})
+	require.Nil(t, err)
+	require.Equal(t, args, savedArgs)
+}
+
+func TestPayloadsProcessor_SaveAccountsIsRejected(t *testing.T) {
+	t.Parallel()
+
+	creator := &payloadsCreator{marshalizer: &mock.MarshalizerMock{}}
+	data, err := creator.createSaveAccountsData(100, []coreData.UserAccountHandler{
+		&Account{Address: []byte("addr"), Nonce: 2, Balance: big.NewInt(1000)},
+	})
+	require.Nil(t, err)
+
+	called := false
+	pp, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveAccountsCalled: func(blockTimestamp uint64, acc []coreData.UserAccountHandler) {
+			called = true
+		},
+	})
+
+	err = pp.ProcessPayload(&Payload{ID: 1, Type: SaveAccountsType, Data: data})
+	require.True(t, errors.Is(err, ErrUnsupportedPayloadType))
+	require.False(t, called)
+}
@@ -0,0 +1,130 @@
+package payloads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ElrondNetwork/elrond-go-core/core/check"
+	logger "github.com/ElrondNetwork/elrond-go-logger"
+)
+
+var log = logger.GetOrCreate("core/indexer/payloads")
+
+// PayloadsPath is the path on which the indexer service receives the payloads
+const PayloadsPath = "/payloads"
+
+var errResendUnsaved = errors.New("the indexer service restarted, the payloads that were not saved must be resent")
+
+// PayloadProcessor defines what a payloads processor should be able to do
+type PayloadProcessor interface {
+	ProcessPayload(payload *Payload) error
+	LastSavedID() uint64
+	IsInterfaceNil() bool
+}
+
+type payloadsReceiver struct {
+	mutex           sync.Mutex
+	processor       PayloadProcessor
+	lastProcessedID uint64
+}
+
+// NewPayloadsReceiver will create a new http handler that receives the payloads sent by the node. A payload is
+// acknowledged as soon as it was handed to the indexer and every acknowledgement carries the highest ID of the saved
+// payloads, so the node keeps and resends the payloads lost by a crash of the service
+func NewPayloadsReceiver(processor PayloadProcessor) (*payloadsReceiver, error) {
+	if check.IfNil(processor) {
+		return nil, ErrNilIndexer
+	}
+
+	return &payloadsReceiver{
+		processor: processor,
+	}, nil
+}
+
+// ServeHTTP will process the payload from the request body and will respond with its acknowledgement. A GET request
+// returns the last processed and the last saved payload IDs
+func (pr *payloadsReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		pr.writeAck(w, pr.getLastProcessedID())
+	case http.MethodPost:
+		pr.receivePayload(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (pr *payloadsReceiver) receivePayload(w http.ResponseWriter, r *http.Request) {
+	payload := &Payload{}
+	err := json.NewDecoder(r.Body).Decode(payload)
+	if err != nil {
+		log.Warn("payloadsReceiver.ServeHTTP: cannot decode payload", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = pr.processPayload(payload)
+	if errors.Is(err, errResendUnsaved) {
+		log.Debug("payloadsReceiver.ServeHTTP: asking for the unsaved payloads", "id", payload.ID, "oldest unsaved id", payload.OldestUnsavedID)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Warn("payloadsReceiver.ServeHTTP: cannot process payload", "id", payload.ID, "type", payload.Type, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pr.writeAck(w, payload.ID)
+}
+
+func (pr *payloadsReceiver) writeAck(w http.ResponseWriter, id uint64) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(&Ack{ID: id, Saved: pr.processor.LastSavedID()})
+	if err != nil {
+		log.Warn("payloadsReceiver.ServeHTTP: cannot write ack", "id", id, "error", err)
+	}
+}
+
+func (pr *payloadsReceiver) getLastProcessedID() uint64 {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	return pr.lastProcessedID
+}
+
+func (pr *payloadsReceiver) processPayload(payload *Payload) error {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	// after a restart, the payloads that were acknowledged but not saved are lost, so the node has to start again
+	// from the oldest payload it keeps
+	isFirstPayload := pr.lastProcessedID == 0
+	if isFirstPayload && payload.OldestUnsavedID != 0 && payload.ID != payload.OldestUnsavedID {
+		return fmt.Errorf("%w: received %d, expected %d", errResendUnsaved, payload.ID, payload.OldestUnsavedID)
+	}
+
+	// the sender resends the payloads that were not saved, in order, so a payload with an already seen id was
+	// processed before
+	if payload.ID <= pr.lastProcessedID {
+		log.Debug("payloadsReceiver: payload already processed", "id", payload.ID, "type", payload.Type)
+		return nil
+	}
+
+	err := pr.processor.ProcessPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	pr.lastProcessedID = payload.ID
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (pr *payloadsReceiver) IsInterfaceNil() bool {
+	return pr == nil
+}
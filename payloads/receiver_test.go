@@ -0,0 +1,135 @@
+package payloads
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/stretchr/testify/require"
+)
+
+func sendPayload(receiver http.Handler, payload *Payload) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, PayloadsPath, bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestPayloadsReceiver_AlreadyProcessedPayloadIsOnlyAcknowledged(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveValidatorsPubKeysCalled: func(validatorsPubKeys map[uint32][][]byte, epoch uint32) {
+			numCalls++
+			require.Equal(t, uint32(7), epoch)
+		},
+	})
+	receiver, err := NewPayloadsReceiver(processor)
+	require.Nil(t, err)
+
+	data, _ := json.Marshal(&ArgsSaveValidatorsPubKeys{Epoch: 7})
+	payload := &Payload{ID: 5, Type: SaveValidatorsPubKeysType, Data: data}
+	for i := 0; i < 2; i++ {
+		recorder := sendPayload(receiver, payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		ack := &Ack{}
+		require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), ack))
+		require.Equal(t, uint64(5), ack.ID)
+	}
+	require.Equal(t, 1, numCalls)
+}
+
+func TestPayloadsReceiver_InvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) {
+			called = true
+		},
+	})
+	receiver, _ := NewPayloadsReceiver(processor)
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, PayloadsPath, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PayloadsPath, bytes.NewBufferString("not json")))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = sendPayload(receiver, &Payload{ID: 1, Type: "unknown"})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// the rejected payload should not be considered as processed
+	recorder = sendPayload(receiver, &Payload{ID: 1, Type: SaveRoundsType, Data: []byte(`{}`)})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.True(t, called)
+}
+
+func TestPayloadsReceiver_AckCarriesTheLastSavedPayload(t *testing.T) {
+	t.Parallel()
+
+	unsaved := make([]func(), 0)
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		NotifyWhenSavedCalled: func(handler func()) {
+			unsaved = append(unsaved, handler)
+		},
+	})
+	receiver, _ := NewPayloadsReceiver(processor)
+
+	// the payloads are acknowledged without waiting for their content to be saved
+	for id := uint64(3); id <= 4; id++ {
+		recorder := sendPayload(receiver, &Payload{ID: id, OldestUnsavedID: 3, Type: SaveRoundsType, Data: []byte(`{}`)})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, Ack{ID: id, Saved: 0}, decodeAck(t, recorder))
+	}
+
+	unsaved[0]()
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, PayloadsPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, Ack{ID: 4, Saved: 3}, decodeAck(t, recorder))
+
+	unsaved[1]()
+	recorder = sendPayload(receiver, &Payload{ID: 5, OldestUnsavedID: 4, Type: SaveRoundsType, Data: []byte(`{}`)})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, Ack{ID: 5, Saved: 4}, decodeAck(t, recorder))
+}
+
+func TestPayloadsReceiver_AsksForTheUnsavedPayloadsAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	numProcessed := 0
+	processor, _ := NewPayloadsProcessor(&mock.MarshalizerMock{}, &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) {
+			numProcessed++
+		},
+	})
+	receiver, _ := NewPayloadsReceiver(processor)
+
+	recorder := sendPayload(receiver, &Payload{ID: 5, OldestUnsavedID: 3, Type: SaveRoundsType, Data: []byte(`{}`)})
+	require.Equal(t, http.StatusConflict, recorder.Code)
+	require.Equal(t, 0, numProcessed)
+
+	recorder = sendPayload(receiver, &Payload{ID: 3, OldestUnsavedID: 3, Type: SaveRoundsType, Data: []byte(`{}`)})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = sendPayload(receiver, &Payload{ID: 5, OldestUnsavedID: 3, Type: SaveRoundsType, Data: []byte(`{}`)})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, 2, numProcessed)
+}
+
+func decodeAck(t *testing.T, recorder *httptest.ResponseRecorder) Ack {
+	ack := Ack{}
+	require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &ack))
+
+	return ack
+}
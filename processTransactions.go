@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ElrondNetwork/elastic-indexer-go/data"
 	"github.com/ElrondNetwork/elrond-go-core/core"
@@ -25,6 +26,18 @@ const (
 	minimumNumberOfSmartContractResults = 2
 )
 
+// preparedResults holds the data prepared from the transactions of a block
+type preparedResults struct {
+	transactions             []*data.Transaction
+	alteredAccounts          map[string]*data.AlteredAccount
+	tokensRegistryOperations []*tokenRegistryOperation
+	scDeploys                *scDeploysResults
+	validatorsOperations     *validatorsOperations
+	delegationOperations     []*delegationOperation
+	scrsOfPreviousTxs        map[string][]data.ScResult
+	addressActivities        []*data.AddressActivity
+}
+
 type txDatabaseProcessor struct {
 	*commonProcessor
 	hasher           hashing.Hasher
@@ -63,15 +76,13 @@ func (tdp *txDatabaseProcessor) prepareTransactionsForDatabase(
 	header coreData.HeaderHandler,
 	txPool map[string]coreData.TransactionHandler,
 	selfShardID uint32,
-) ([]*data.Transaction, map[string]struct{}) {
+) *preparedResults {
 	transactions, rewardsTxs, alteredAddresses := tdp.groupNormalTxsAndRewards(body, txPool, header, selfShardID)
 	//we can not iterate smart contract results directly on the miniblocks contained in the block body
 	// as some miniblocks might be missing. Example: intra-shard miniblock that holds smart contract results
 	scResults := groupSmartContractResults(txPool)
 	tdp.addScrsReceiverToAlteredAccounts(alteredAddresses, scResults)
 
-	transactions = tdp.setTransactionSearchOrder(transactions)
-
 	countScResults := make(map[string]int)
 	for scHash, scResult := range scResults {
 		tx, ok := transactions[string(scResult.OriginalTxHash)]
@@ -138,18 +149,30 @@ func (tdp *txDatabaseProcessor) prepareTransactionsForDatabase(
 	//	tx.Log = tdp.prepareTxLog(txLog)
 	//}
 
-	return append(convertMapTxsToSlice(transactions), rewardsTxs...), alteredAddresses
+	preparedTxs := append(convertMapTxsToSlice(transactions), rewardsTxs...)
+	scrsOfPreviousTxs := tdp.groupScrsOfPreviousTxs(scResults)
+
+	return &preparedResults{
+		transactions:             preparedTxs,
+		alteredAccounts:          alteredAddresses,
+		tokensRegistryOperations: tdp.getTokensRegistryOperations(body, transactions, selfShardID),
+		scDeploys:                tdp.getScDeploys(transactions, selfShardID),
+		validatorsOperations:     tdp.getValidatorsOperations(body, transactions, selfShardID),
+		delegationOperations:     tdp.getDelegationOperations(body, transactions, selfShardID),
+		scrsOfPreviousTxs:        scrsOfPreviousTxs,
+		addressActivities:        tdp.getAddressActivities(header, preparedTxs, scrsOfPreviousTxs, selfShardID),
+	}
 }
 
 func (tdp *txDatabaseProcessor) addScrsReceiverToAlteredAccounts(
-	alteredAddress map[string]struct{},
+	alteredAddress map[string]*data.AlteredAccount,
 	scrs map[string]*smartContractResult.SmartContractResult,
 ) {
 	for _, scr := range scrs {
 		shardID := tdp.shardCoordinator.ComputeId(scr.RcvAddr)
 		if shardID == tdp.shardCoordinator.SelfId() {
 			encodedReceiverAddress := tdp.addressPubkeyConverter.Encode(scr.RcvAddr)
-			alteredAddress[encodedReceiverAddress] = struct{}{}
+			addAlteredAccount(alteredAddress, encodedReceiverAddress, hex.EncodeToString(scr.OriginalTxHash), false)
 		}
 	}
 }
@@ -265,17 +288,23 @@ func (tdp *txDatabaseProcessor) groupNormalTxsAndRewards(
 ) (
 	map[string]*data.Transaction,
 	[]*data.Transaction,
-	map[string]struct{},
+	map[string]*data.AlteredAccount,
 ) {
-	alteredAddresses := make(map[string]struct{})
+	alteredAddresses := make(map[string]*data.AlteredAccount)
 	transactions := make(map[string]*data.Transaction)
 	rewardsTxs := make([]*data.Transaction, 0)
 
-	for _, mb := range body.MiniBlocks {
+	headerHash, err := core.CalculateHash(tdp.marshalizer, tdp.hasher, header)
+	if err != nil {
+		log.Debug("indexer: cannot compute header hash", "error", err)
+	}
+
+	for mbIndex, mb := range body.MiniBlocks {
 		mbHash, err := core.CalculateHash(tdp.marshalizer, tdp.hasher, mb)
 		if err != nil {
 			continue
 		}
+		txsPositions := getTxsPositionsInMiniblock(mb)
 
 		mbTxStatus := transaction.TxStatusPending.String()
 		if selfShardID == mb.ReceiverShardID {
@@ -287,7 +316,10 @@ func (tdp *txDatabaseProcessor) groupNormalTxsAndRewards(
 			txs := getTransactions(txPool, mb.TxHashes)
 			for hash, tx := range txs {
 				dbTx := tdp.commonProcessor.buildTransaction(tx, []byte(hash), mbHash, mb, header, mbTxStatus)
+				setProcessingBlockInfo(dbTx, mb, headerHash, header, selfShardID)
+				dbTx.SearchOrder = computeTxSearchOrder(header.GetShardID(), mbIndex, txsPositions[hash])
 				addToAlteredAddresses(dbTx, alteredAddresses, mb, selfShardID, false)
+				tdp.addRelayedInnerAddressesToAlteredAccounts(dbTx, alteredAddresses, selfShardID)
 				if tdp.shouldIndex(selfShardID, mb.ReceiverShardID) {
 					transactions[hash] = dbTx
 				}
@@ -297,11 +329,14 @@ func (tdp *txDatabaseProcessor) groupNormalTxsAndRewards(
 			txs := getTransactions(txPool, mb.TxHashes)
 			for hash, tx := range txs {
 				dbTx := tdp.commonProcessor.buildTransaction(tx, []byte(hash), mbHash, mb, header, transaction.TxStatusInvalid.String())
+				setProcessingBlockInfo(dbTx, mb, headerHash, header, selfShardID)
+				dbTx.SearchOrder = computeTxSearchOrder(header.GetShardID(), mbIndex, txsPositions[hash])
 				addToAlteredAddresses(dbTx, alteredAddresses, mb, selfShardID, false)
 
 				dbTx.GasUsed = dbTx.GasLimit
 				fee := tdp.commonProcessor.txFeeCalculator.ComputeTxFeeBasedOnGasUsed(tx, dbTx.GasUsed)
 				dbTx.Fee = fee.String()
+				dbTx.Operation = operationInvalid
 
 				transactions[hash] = dbTx
 				delete(txPool, hash)
@@ -310,6 +345,8 @@ func (tdp *txDatabaseProcessor) groupNormalTxsAndRewards(
 			rTxs := getRewardsTransaction(txPool, mb.TxHashes)
 			for hash, rtx := range rTxs {
 				dbTx := tdp.commonProcessor.buildRewardTransaction(rtx, []byte(hash), mbHash, mb, header, mbTxStatus)
+				setProcessingBlockInfo(dbTx, mb, headerHash, header, selfShardID)
+				dbTx.SearchOrder = computeTxSearchOrder(header.GetShardID(), mbIndex, txsPositions[hash])
 				addToAlteredAddresses(dbTx, alteredAddresses, mb, selfShardID, true)
 				if tdp.shouldIndex(selfShardID, mb.ReceiverShardID) {
 					rewardsTxs = append(rewardsTxs, dbTx)
@@ -324,6 +361,35 @@ func (tdp *txDatabaseProcessor) groupNormalTxsAndRewards(
 	return transactions, rewardsTxs, alteredAddresses
 }
 
+// setProcessingBlockInfo records the block of the current shard in which the transaction was processed, on the
+// source side, on the destination side or on both of them for the intra-shard transactions
+func setProcessingBlockInfo(
+	dbTx *data.Transaction,
+	mb *block.MiniBlock,
+	headerHash []byte,
+	header coreData.HeaderHandler,
+	selfShardID uint32,
+) {
+	encodedHeaderHash := hex.EncodeToString(headerHash)
+	timestamp := time.Duration(header.GetTimeStamp())
+	// the metachain blocks are notarized by themselves
+	isMetachain := selfShardID == core.MetachainShardId
+	if mb.SenderShardID == selfShardID {
+		dbTx.SourceBlockHash = encodedHeaderHash
+		dbTx.SourceTimestamp = timestamp
+		if isMetachain {
+			dbTx.NotarizedAtSourceInMetaNonce = header.GetNonce()
+		}
+	}
+	if mb.ReceiverShardID == selfShardID {
+		dbTx.DestinationBlockHash = encodedHeaderHash
+		dbTx.DestinationTimestamp = timestamp
+		if isMetachain {
+			dbTx.NotarizedAtDestinationInMetaNonce = header.GetNonce()
+		}
+	}
+}
+
 func (tdp *txDatabaseProcessor) shouldIndex(selfShardID uint32, destinationShardID uint32) bool {
 	if !tdp.isInImportMode {
 		return true
@@ -332,25 +398,25 @@ func (tdp *txDatabaseProcessor) shouldIndex(selfShardID uint32, destinationShard
 	return selfShardID == destinationShardID
 }
 
-func (tdp *txDatabaseProcessor) setTransactionSearchOrder(transactions map[string]*data.Transaction) map[string]*data.Transaction {
-	currentOrder := uint32(0)
-	for _, tx := range transactions {
-		tx.SearchOrder = currentOrder
-		currentOrder++
+// getTxsPositionsInMiniblock returns the position of each transaction hash within the provided miniblock
+func getTxsPositionsInMiniblock(mb *block.MiniBlock) map[string]int {
+	positions := make(map[string]int, len(mb.TxHashes))
+	for idx, txHash := range mb.TxHashes {
+		positions[string(txHash)] = idx
 	}
 
-	return transactions
+	return positions
 }
 
 func addToAlteredAddresses(
 	tx *data.Transaction,
-	alteredAddresses map[string]struct{},
+	alteredAddresses map[string]*data.AlteredAccount,
 	miniBlock *block.MiniBlock,
 	selfShardID uint32,
 	isRewardTx bool,
 ) {
 	if selfShardID == miniBlock.SenderShardID && !isRewardTx {
-		alteredAddresses[tx.Sender] = struct{}{}
+		addAlteredAccount(alteredAddresses, tx.Sender, tx.Hash, true)
 	}
 
 	if tx.Status == transaction.TxStatusInvalid.String() {
@@ -358,7 +424,47 @@ func addToAlteredAddresses(
 	}
 
 	if selfShardID == miniBlock.ReceiverShardID || miniBlock.ReceiverShardID == core.AllShardId {
-		alteredAddresses[tx.Receiver] = struct{}{}
+		addAlteredAccount(alteredAddresses, tx.Receiver, tx.Hash, false)
+	}
+}
+
+// addAlteredAccount records an account altered by the provided transaction. An account that already is in the map
+// keeps its transaction hash and only becomes a sender
+func addAlteredAccount(alteredAddresses map[string]*data.AlteredAccount, address string, txHash string, isSender bool) {
+	alteredAccount, ok := alteredAddresses[address]
+	if !ok {
+		alteredAddresses[address] = &data.AlteredAccount{
+			IsSender: isSender,
+			TxHash:   txHash,
+		}
+		return
+	}
+
+	alteredAccount.IsSender = alteredAccount.IsSender || isSender
+}
+
+// addRelayedInnerAddressesToAlteredAccounts will add the sender and the receiver of the user transaction of a relayed
+// transaction to the altered accounts, as the relayed transaction alters their accounts through its results
+func (tdp *txDatabaseProcessor) addRelayedInnerAddressesToAlteredAccounts(
+	tx *data.Transaction,
+	alteredAddresses map[string]*data.AlteredAccount,
+	selfShardID uint32,
+) {
+	if tx.Status == transaction.TxStatusInvalid.String() {
+		return
+	}
+
+	for _, encodedAddress := range []string{tx.InnerSender, tx.InnerReceiver} {
+		if encodedAddress == "" {
+			continue
+		}
+
+		address, err := tdp.addressPubkeyConverter.Decode(encodedAddress)
+		if err != nil || tdp.shardCoordinator.ComputeId(address) != selfShardID {
+			continue
+		}
+
+		addAlteredAccount(alteredAddresses, encodedAddress, tx.Hash, encodedAddress == tx.InnerSender)
 	}
 }
 
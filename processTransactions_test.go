@@ -19,6 +19,46 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func createTestTxDatabaseProcessor(shardCoordinator *mock.ShardCoordinatorMock) *txDatabaseProcessor {
+	return newTxDatabaseProcessor(
+		&mock.HasherMock{},
+		&mock.MarshalizerMock{},
+		mock.NewPubkeyConverterMock(32),
+		mock.NewPubkeyConverterMock(32),
+		&mock.EconomicsHandlerStub{},
+		false,
+		shardCoordinator,
+	)
+}
+
+// createSystemSCCallsBody returns a body with a single miniblock that holds the provided transactions, sent from
+// shard 0 to metachain
+func createSystemSCCallsBody(txHashes ...string) *block.Body {
+	miniblock := &block.MiniBlock{
+		Type:            block.TxBlock,
+		SenderShardID:   0,
+		ReceiverShardID: core.MetachainShardId,
+		TxHashes:        make([][]byte, 0, len(txHashes)),
+	}
+	for _, txHash := range txHashes {
+		miniblock.TxHashes = append(miniblock.TxHashes, []byte(txHash))
+	}
+
+	return &block.Body{MiniBlocks: []*block.MiniBlock{miniblock}}
+}
+
+// prepareSystemSCCalls prepares the transactions of the provided body as they are indexed by the provided shard
+func prepareSystemSCCalls(body *block.Body, pool map[string]coreData.TransactionHandler, selfShardID uint32) *preparedResults {
+	txDbProc := createTestTxDatabaseProcessor(&mock.ShardCoordinatorMock{SelfID: selfShardID})
+
+	var header coreData.HeaderHandler = &block.Header{Round: 1}
+	if selfShardID == core.MetachainShardId {
+		header = &block.MetaBlock{Round: 1}
+	}
+
+	return txDbProc.prepareTransactionsForDatabase(body, header, pool, selfShardID)
+}
+
 func TestPrepareTransactionsForDatabase(t *testing.T) {
 	t.Parallel()
 
@@ -131,7 +171,7 @@ func TestPrepareTransactionsForDatabase(t *testing.T) {
 		&mock.ShardCoordinatorMock{},
 	)
 
-	transactions, _ := txDbProc.prepareTransactionsForDatabase(body, header, txPool, 0)
+	transactions := txDbProc.prepareTransactionsForDatabase(body, header, txPool, 0).transactions
 	assert.Equal(t, 7, len(transactions))
 
 }
@@ -240,23 +280,25 @@ func TestRelayedTransactions(t *testing.T) {
 		&mock.ShardCoordinatorMock{},
 	)
 
-	transactions, _ := txDbProc.prepareTransactionsForDatabase(body, header, txPool, 0)
+	transactions := txDbProc.prepareTransactionsForDatabase(body, header, txPool, 0).transactions
 	assert.Equal(t, 1, len(transactions))
 	assert.Equal(t, 3, len(transactions[0].SmartContractResults))
 	assert.Equal(t, transaction.TxStatusSuccess.String(), transactions[0].Status)
 }
 
-func TestSetTransactionSearchOrder(t *testing.T) {
+func TestPrepareTransactionsForDatabase_SearchOrder(t *testing.T) {
 	t.Parallel()
-	txHash1 := []byte("txHash1")
-	tx1 := &data.Transaction{}
-
-	txHash2 := []byte("txHash2")
-	tx2 := &data.Transaction{}
 
-	txPool := map[string]*data.Transaction{
-		string(txHash1): tx1,
-		string(txHash2): tx2,
+	txPool := map[string]coreData.TransactionHandler{
+		"tx1": &transaction.Transaction{Value: big.NewInt(0)},
+		"tx2": &transaction.Transaction{Value: big.NewInt(0)},
+		"tx3": &transaction.Transaction{Value: big.NewInt(0)},
+	}
+	body := &block.Body{
+		MiniBlocks: []*block.MiniBlock{
+			{TxHashes: [][]byte{[]byte("tx2"), []byte("tx1")}, Type: block.TxBlock},
+			{TxHashes: [][]byte{[]byte("tx3")}, Type: block.TxBlock},
+		},
 	}
 
 	txDbProc := newTxDatabaseProcessor(
@@ -269,17 +311,16 @@ func TestSetTransactionSearchOrder(t *testing.T) {
 		&mock.ShardCoordinatorMock{},
 	)
 
-	transactions := txDbProc.setTransactionSearchOrder(txPool)
-	assert.True(t, txPoolHasSearchOrder(transactions, 0))
-	assert.True(t, txPoolHasSearchOrder(transactions, 1))
-
-	transactions = txDbProc.setTransactionSearchOrder(txPool)
-	assert.True(t, txPoolHasSearchOrder(transactions, 0))
-	assert.True(t, txPoolHasSearchOrder(transactions, 1))
-
-	transactions = txDbProc.setTransactionSearchOrder(txPool)
-	assert.True(t, txPoolHasSearchOrder(transactions, 0))
-	assert.True(t, txPoolHasSearchOrder(transactions, 1))
+	txs := txDbProc.prepareTransactionsForDatabase(body, &block.Header{ShardID: 1}, txPool, 1).transactions
+	searchOrders := make(map[string]uint64)
+	for _, tx := range txs {
+		searchOrders[tx.Hash] = tx.SearchOrder
+	}
+	require.Equal(t, map[string]uint64{
+		hex.EncodeToString([]byte("tx2")): 1030000000000,
+		hex.EncodeToString([]byte("tx1")): 1030000000001,
+		hex.EncodeToString([]byte("tx3")): 1030001000000,
+	}, searchOrders)
 }
 
 func TestGetGasUsedFromReceipt_RefundedGas(t *testing.T) {
@@ -461,7 +502,7 @@ func TestAlteredAddresses(t *testing.T) {
 		shardCoordinator: shardCoordinator,
 	}
 
-	_, alteredAddresses := txProc.prepareTransactionsForDatabase(body, hdr, txPool, selfShardID)
+	alteredAddresses := txProc.prepareTransactionsForDatabase(body, hdr, txPool, selfShardID).alteredAccounts
 	require.Equal(t, len(expectedAlteredAccounts), len(alteredAddresses))
 
 	for addrActual := range alteredAddresses {
@@ -472,16 +513,6 @@ func TestAlteredAddresses(t *testing.T) {
 	}
 }
 
-func txPoolHasSearchOrder(txPool map[string]*data.Transaction, searchOrder uint32) bool {
-	for _, tx := range txPool {
-		if tx.SearchOrder == searchOrder {
-			return true
-		}
-	}
-
-	return false
-}
-
 func TestIsSCRForSenderWithGasUsed(t *testing.T) {
 	t.Parallel()
 
@@ -548,7 +579,7 @@ func TestCheckGasUsedInvalidTransaction(t *testing.T) {
 		string(recHash1): rec1,
 	}
 
-	txs, _ := txProc.prepareTransactionsForDatabase(body, header, txPool, 0)
+	txs := txProc.prepareTransactionsForDatabase(body, header, txPool, 0).transactions
 	require.Len(t, txs, 1)
 	require.Equal(t, tx1.GasLimit, txs[0].GasUsed)
 }
@@ -597,7 +628,83 @@ func TestCheckGasUsedRelayedTransaction(t *testing.T) {
 		string(scResHash1): scRes1,
 	}
 
-	txs, _ := txProc.prepareTransactionsForDatabase(body, header, txPool, 0)
+	txs := txProc.prepareTransactionsForDatabase(body, header, txPool, 0).transactions
 	require.Len(t, txs, 1)
 	require.Equal(t, tx1.GasLimit, txs[0].GasUsed)
 }
+
+func TestAddRelayedInnerAddressesToAlteredAccounts(t *testing.T) {
+	t.Parallel()
+
+	txProc := newTxDatabaseProcessor(
+		&mock.HasherMock{},
+		&mock.MarshalizerMock{},
+		mock.NewPubkeyConverterMock(32),
+		mock.NewPubkeyConverterMock(32),
+		&mock.EconomicsHandlerStub{},
+		false,
+		&mock.ShardCoordinatorMock{
+			ComputeIdCalled: func(address []byte) uint32 {
+				if string(address) == "other shard" {
+					return 1
+				}
+				return 0
+			},
+		},
+	)
+
+	tx := &data.Transaction{
+		Hash:          "hash",
+		InnerSender:   hex.EncodeToString([]byte("user")),
+		InnerReceiver: hex.EncodeToString([]byte("other shard")),
+	}
+	alteredAddresses := make(map[string]*data.AlteredAccount)
+	txProc.addRelayedInnerAddressesToAlteredAccounts(tx, alteredAddresses, 0)
+	require.Equal(t, map[string]*data.AlteredAccount{
+		hex.EncodeToString([]byte("user")): {IsSender: true, TxHash: "hash"},
+	}, alteredAddresses)
+
+	tx.Status = transaction.TxStatusInvalid.String()
+	alteredAddresses = make(map[string]*data.AlteredAccount)
+	txProc.addRelayedInnerAddressesToAlteredAccounts(tx, alteredAddresses, 0)
+	require.Empty(t, alteredAddresses)
+}
+
+func TestSetProcessingBlockInfo(t *testing.T) {
+	t.Parallel()
+
+	header := &block.MetaBlock{Nonce: 7, TimeStamp: 100}
+	crossShardMb := &block.MiniBlock{SenderShardID: 0, ReceiverShardID: 1}
+
+	srcTx := &data.Transaction{}
+	setProcessingBlockInfo(srcTx, crossShardMb, []byte("hash"), &block.Header{TimeStamp: 100}, 0)
+	require.Equal(t, &data.Transaction{
+		SourceBlockHash: hex.EncodeToString([]byte("hash")),
+		SourceTimestamp: 100,
+	}, srcTx)
+
+	dstTx := &data.Transaction{}
+	setProcessingBlockInfo(dstTx, crossShardMb, []byte("hash"), &block.Header{TimeStamp: 100}, 1)
+	require.Equal(t, &data.Transaction{
+		DestinationBlockHash: hex.EncodeToString([]byte("hash")),
+		DestinationTimestamp: 100,
+	}, dstTx)
+
+	metaTx := &data.Transaction{}
+	setProcessingBlockInfo(metaTx, &block.MiniBlock{SenderShardID: core.MetachainShardId, ReceiverShardID: 0}, []byte("hash"), header, core.MetachainShardId)
+	require.Equal(t, &data.Transaction{
+		SourceBlockHash:              hex.EncodeToString([]byte("hash")),
+		SourceTimestamp:              100,
+		NotarizedAtSourceInMetaNonce: 7,
+	}, metaTx)
+}
+
+func TestAddAlteredAccount(t *testing.T) {
+	t.Parallel()
+
+	alteredAddresses := make(map[string]*data.AlteredAccount)
+	addAlteredAccount(alteredAddresses, "addr", "hash1", false)
+	addAlteredAccount(alteredAddresses, "addr", "hash2", true)
+	addAlteredAccount(alteredAddresses, "addr", "hash3", false)
+	require.Equal(t, map[string]*data.AlteredAccount{"addr": {IsSender: true, TxHash: "hash1"}}, alteredAddresses)
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
 )
 
 func encode(obj objectsMap) (bytes.Buffer, error) {
@@ -15,12 +17,12 @@ func encode(obj objectsMap) (bytes.Buffer, error) {
 	return buff, nil
 }
 
-func getDocumentsByIDsQuery(hashes []string) objectsMap {
+func getDocumentsByIDsQuery(hashes []string, withSource bool) objectsMap {
 	interfaceSlice := make([]interface{}, len(hashes))
 	for idx := range hashes {
 		interfaceSlice[idx] = objectsMap{
 			"_id":     hashes[idx],
-			"_source": false,
+			"_source": withSource,
 		}
 	}
 
@@ -29,6 +31,135 @@ func getDocumentsByIDsQuery(hashes []string) objectsMap {
 	}
 }
 
+// getLastShardBlockQuery returns the search query that matches the block with the highest nonce of a shard
+func getLastShardBlockQuery(shardID uint32) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"term": objectsMap{
+				"shardId": shardID,
+			},
+		},
+		"sort": []objectsMap{
+			{"nonce": objectsMap{"order": "desc"}},
+		},
+		"size": 1,
+	}
+}
+
+// getEpochStartBlockQuery returns the search query that matches the first metachain block of an epoch, which is the
+// block that started the epoch
+func getEpochStartBlockQuery(epoch uint32) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"shardId": core.MetachainShardId}},
+					{"term": objectsMap{"epoch": epoch}},
+				},
+			},
+		},
+		"sort": []objectsMap{
+			{"nonce": objectsMap{"order": "asc"}},
+		},
+		"size": 1,
+	}
+}
+
+// getNotarizedTransactionsQuery returns the update by query that sets the provided metachain nonce on the transactions
+// of the provided miniblocks. The nonce field selects the side on which the miniblocks were notarized
+func getNotarizedTransactionsQuery(mbsHashes []string, nonceField string, metaNonce uint64) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"terms": objectsMap{
+				"miniBlockHash": mbsHashes,
+			},
+		},
+		"script": objectsMap{
+			"source": setNotarizationScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"field": nonceField,
+				"nonce": metaNonce,
+			},
+		},
+	}
+}
+
+// getValidatorsTopUpQuery returns the update by query that adds the provided top-ups to the top-up of all the
+// validators of an owner
+func getValidatorsTopUpQuery(owner string, topUps []*topUpOperation) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"type": validatorDocType}},
+					{"term": objectsMap{"owner": owner}},
+				},
+			},
+		},
+		"script": objectsMap{
+			"source": addTopUpScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"topUps": serializeTopUps(topUps),
+			},
+		},
+	}
+}
+
+// getPreviousEligibleValidatorsQuery returns the update by query that moves out of the eligible list the validators of
+// a shard that were eligible in an epoch before the provided one. They join the list set by their last operation, or
+// the waiting list
+func getPreviousEligibleValidatorsQuery(shardID uint32, epoch uint32) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"type": validatorDocType}},
+					{"term": objectsMap{"list": eligibleList}},
+					{"term": objectsMap{"shardId": shardID}},
+					{"range": objectsMap{"epoch": objectsMap{"lt": epoch}}},
+				},
+			},
+		},
+		"script": objectsMap{
+			"source": leaveEligibleListScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"list":  waitingList,
+				"epoch": epoch,
+			},
+		},
+	}
+}
+
+// getRevertEpochValidatorsQuery returns the query that restores the validators made eligible or moved out of the
+// eligible list by the start of the provided epoch
+func getRevertEpochValidatorsQuery(epoch uint32) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"type": validatorDocType}},
+				},
+				"should": []objectsMap{
+					{"term": objectsMap{"epoch": epoch}},
+					{"term": objectsMap{"leftEligibleInEpoch": epoch}},
+				},
+				"minimum_should_match": 1,
+			},
+		},
+		"script": objectsMap{
+			"source": revertEpochValidatorsScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"epoch": epoch,
+				"list":  eligibleList,
+			},
+		},
+	}
+}
+
 func prepareHashesForBulkRemove(hashes []string) objectsMap {
 	return objectsMap{
 		"query": objectsMap{
@@ -39,3 +170,51 @@ func prepareHashesForBulkRemove(hashes []string) objectsMap {
 		},
 	}
 }
+
+// getBlockEntriesQuery returns the query that matches the entries written by a block, like the balances history or
+// the address activity entries
+func getBlockEntriesQuery(shardID uint32, blockNonce uint64) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"shardId": shardID}},
+					{"term": objectsMap{"blockNonce": blockNonce}},
+				},
+			},
+		},
+	}
+}
+
+// getValidatorsStatisticsFromRoundQuery returns the update by query that subtracts from the validators statistics of a
+// shard the contributions of the rounds starting with the provided round
+func getValidatorsStatisticsFromRoundQuery(shardID uint32, round uint64) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"bool": objectsMap{
+				"filter": []objectsMap{
+					{"term": objectsMap{"shardId": shardID}},
+					{"range": objectsMap{"lastRound": objectsMap{"gte": round}}},
+				},
+			},
+		},
+		"script": objectsMap{
+			"source": revertValidatorStatisticsScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"round": round,
+			},
+		},
+	}
+}
+
+// getEpochDocumentsQuery returns the query that matches the documents of an epoch
+func getEpochDocumentsQuery(epoch uint32) objectsMap {
+	return objectsMap{
+		"query": objectsMap{
+			"term": objectsMap{
+				"epoch": epoch,
+			},
+		},
+	}
+}
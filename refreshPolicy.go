@@ -0,0 +1,35 @@
+package indexer
+
+import "fmt"
+
+// CreateRefreshPolicies will return the refresh policy of every index for the provided refresh mode
+func CreateRefreshPolicies(refreshMode string) (map[string]string, error) {
+	var policy string
+	switch refreshMode {
+	case SyncRefreshMode:
+		policy = RefreshPolicyFalse
+	case TipRefreshMode:
+		policy = RefreshPolicyWaitFor
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRefreshMode, refreshMode)
+	}
+
+	refreshPolicies := make(map[string]string, len(indexes))
+	for _, index := range indexes {
+		refreshPolicies[index] = policy
+	}
+
+	return refreshPolicies, nil
+}
+
+func checkRefreshPolicies(refreshPolicies map[string]string) error {
+	for index, policy := range refreshPolicies {
+		switch policy {
+		case RefreshPolicyTrue, RefreshPolicyWaitFor, RefreshPolicyFalse:
+		default:
+			return fmt.Errorf("%w: %s for index %s", ErrInvalidRefreshPolicy, policy, index)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package indexer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRefreshPolicies(t *testing.T) {
+	t.Parallel()
+
+	refreshPolicies, err := CreateRefreshPolicies(SyncRefreshMode)
+	require.Nil(t, err)
+	require.Len(t, refreshPolicies, len(indexes))
+	for _, index := range indexes {
+		require.Equal(t, RefreshPolicyFalse, refreshPolicies[index])
+	}
+
+	refreshPolicies, err = CreateRefreshPolicies(TipRefreshMode)
+	require.Nil(t, err)
+	for _, index := range indexes {
+		require.Equal(t, RefreshPolicyWaitFor, refreshPolicies[index])
+	}
+
+	refreshPolicies, err = CreateRefreshPolicies("unknown")
+	require.True(t, errors.Is(err, ErrInvalidRefreshMode))
+	require.Nil(t, refreshPolicies)
+}
+
+func TestCheckRefreshPolicies(t *testing.T) {
+	t.Parallel()
+
+	err := checkRefreshPolicies(map[string]string{
+		blockIndex: RefreshPolicyTrue,
+		txIndex:    RefreshPolicyWaitFor,
+		roundIndex: RefreshPolicyFalse,
+	})
+	require.Nil(t, err)
+
+	err = checkRefreshPolicies(map[string]string{blockIndex: "sometimes"})
+	require.True(t, errors.Is(err, ErrInvalidRefreshPolicy))
+}
@@ -0,0 +1,220 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/ElrondNetwork/elrond-go-core/hashing/keccak"
+)
+
+const (
+	upgradeContractFunction = "upgradeContract"
+
+	minArgsDeploy  = 2
+	minArgsUpgrade = 2
+
+	setDeployScript = `ctx._source.deployTxHash = params.deploy.deployTxHash; ` +
+		`ctx._source.deployer = params.deploy.deployer; ` +
+		`ctx._source.timestamp = params.deploy.timestamp; ` +
+		`ctx._source.codeHash = params.deploy.codeHash; ` +
+		`ctx._source.codeMetadata = params.deploy.codeMetadata;`
+	addUpgradeScript = `if (ctx._source.upgrades == null) { ctx._source.upgrades = new ArrayList(); } ` +
+		`for (upgrade in ctx._source.upgrades) { if (upgrade.upgradeTxHash == params.upgrade.upgradeTxHash) { return; } } ` +
+		`ctx._source.upgrades.add(params.upgrade);`
+)
+
+// scDeploysResults holds the smart contracts deployed and upgraded by the transactions of a block, keyed by the
+// encoded addresses of the contracts
+type scDeploysResults struct {
+	deploys  map[string]*data.ScDeployInfo
+	upgrades map[string][]*data.ScUpgradeInfo
+}
+
+// getScDeploys returns the successful deployments and upgrades of smart contracts that were executed in the current
+// shard. A contract is deployed in the shard of its deployer, while an upgrade is executed in the shard of the contract
+func (tdp *txDatabaseProcessor) getScDeploys(transactions map[string]*data.Transaction, selfShardID uint32) *scDeploysResults {
+	results := &scDeploysResults{
+		deploys:  make(map[string]*data.ScDeployInfo),
+		upgrades: make(map[string][]*data.ScUpgradeInfo),
+	}
+
+	for _, tx := range transactions {
+		if tx.Status != transaction.TxStatusSuccess.String() {
+			continue
+		}
+
+		isDeploy := tx.Operation == operationSCDeploy && tx.SenderShard == selfShardID
+		if isDeploy {
+			tdp.addScDeploy(tx, results)
+			continue
+		}
+
+		isUpgrade := tx.Operation == operationSCCall && tx.Function == upgradeContractFunction && tx.ReceiverShard == selfShardID
+		if isUpgrade {
+			tdp.addScUpgrade(tx, results)
+		}
+	}
+
+	return results
+}
+
+func (tdp *txDatabaseProcessor) addScDeploy(tx *data.Transaction, results *scDeploysResults) {
+	code, args, ok := decodeCodeArguments(tx.Data)
+	if !ok || len(args) < minArgsDeploy {
+		return
+	}
+
+	deployer, err := tdp.addressPubkeyConverter.Decode(tx.Sender)
+	if err != nil {
+		log.Debug("indexer: cannot decode deployer address", "hash", tx.Hash, "error", err)
+		return
+	}
+
+	scAddress, err := tdp.computeScAddress(deployer, tx.Nonce, args[0])
+	if err != nil {
+		log.Debug("indexer: cannot compute deployed contract address", "hash", tx.Hash, "error", err)
+		return
+	}
+
+	encodedSCAddress := tdp.addressPubkeyConverter.Encode(scAddress)
+	results.deploys[encodedSCAddress] = &data.ScDeployInfo{
+		TxHash:       tx.Hash,
+		Deployer:     tx.Sender,
+		Timestamp:    tx.Timestamp,
+		CodeHash:     hex.EncodeToString(tdp.hasher.Compute(string(code))),
+		CodeMetadata: getDeployedCodeMetadata(tx.SmartContractResults, encodedSCAddress, args[1]),
+		Upgrades:     make([]*data.ScUpgradeInfo, 0),
+	}
+}
+
+func (tdp *txDatabaseProcessor) addScUpgrade(tx *data.Transaction, results *scDeploysResults) {
+	_, args, ok := decodeCallArguments(tx.Data)
+	if !ok || len(args) < minArgsUpgrade {
+		return
+	}
+
+	results.upgrades[tx.Receiver] = append(results.upgrades[tx.Receiver], &data.ScUpgradeInfo{
+		TxHash:       tx.Hash,
+		Upgrader:     tx.Sender,
+		Timestamp:    tx.Timestamp,
+		CodeHash:     hex.EncodeToString(tdp.hasher.Compute(string(args[0]))),
+		CodeMetadata: getDeployedCodeMetadata(tx.SmartContractResults, tx.Receiver, args[1]),
+	})
+}
+
+// scAddressHasher is the hasher used by the protocol to derive the addresses of the deployed smart contracts,
+// regardless of the hasher configured for the indexer
+var scAddressHasher = keccak.NewKeccak()
+
+// computeScAddress derives the address of a deployed smart contract in the same way as the protocol does: the keccak
+// hash of the deployer address and nonce, prefixed by the virtual machine type and suffixed by the deployer shard bytes
+func (tdp *txDatabaseProcessor) computeScAddress(deployer []byte, nonce uint64, vmType []byte) ([]byte, error) {
+	if len(vmType) != core.VMTypeLen {
+		return nil, fmt.Errorf("%w: invalid vm type length %d", ErrInvalidScDeploy, len(vmType))
+	}
+	if len(deployer) < core.ShardIdentiferLen {
+		return nil, fmt.Errorf("%w: invalid deployer address length %d", ErrInvalidScDeploy, len(deployer))
+	}
+
+	nonceBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonceBytes, nonce)
+	deployerAndNonce := append(append(make([]byte, 0, len(deployer)+len(nonceBytes)), deployer...), nonceBytes...)
+
+	scAddress := scAddressHasher.Compute(string(deployerAndNonce))
+	if len(scAddress) < core.NumInitCharactersForScAddress+core.ShardIdentiferLen {
+		return nil, fmt.Errorf("%w: hash too short", ErrInvalidScDeploy)
+	}
+
+	prefix := append(make([]byte, core.NumInitCharactersForScAddress-core.VMTypeLen), vmType...)
+	copy(scAddress[:core.NumInitCharactersForScAddress], prefix)
+	copy(scAddress[len(scAddress)-core.ShardIdentiferLen:], deployer[len(deployer)-core.ShardIdentiferLen:])
+
+	return scAddress, nil
+}
+
+// getDeployedCodeMetadata returns the code metadata of the contract from the smart contract results that created the
+// contract account, falling back on the code metadata argument of the transaction
+func getDeployedCodeMetadata(scrs []data.ScResult, encodedSCAddress string, codeMetadataArg []byte) string {
+	for _, scr := range scrs {
+		if scr.Receiver == encodedSCAddress && len(scr.CodeMetadata) > 0 {
+			return hex.EncodeToString(scr.CodeMetadata)
+		}
+	}
+
+	return hex.EncodeToString(codeMetadataArg)
+}
+
+// decodeCodeArguments splits the data field of a deploy transaction in the deployed code and its hex decoded arguments
+func decodeCodeArguments(txData []byte) ([]byte, [][]byte, bool) {
+	encodedCode, args, ok := decodeCallArguments(txData)
+	if !ok {
+		return nil, nil, false
+	}
+
+	code, err := hex.DecodeString(encodedCode)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return code, args, true
+}
+
+func serializeScDeploys(results *scDeploysResults, buffSlice bulkBuffer) error {
+	for address, deploy := range results.deploys {
+		serializedDeploy, err := json.Marshal(deploy)
+		if err != nil {
+			return err
+		}
+
+		script, err := json.Marshal(objectsMap{
+			"source": setDeployScript,
+			"lang":   "painless",
+			"params": objectsMap{"deploy": deploy},
+		})
+		if err != nil {
+			return err
+		}
+
+		// the upgrades history is kept when the deployment is indexed again
+		err = buffSlice.PutUpsert(address, script, serializedDeploy)
+		if err != nil {
+			return err
+		}
+	}
+
+	for address, upgrades := range results.upgrades {
+		for _, upgrade := range upgrades {
+			err := putScUpgrade(address, upgrade, buffSlice)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func putScUpgrade(address string, upgrade *data.ScUpgradeInfo, buffSlice bulkBuffer) error {
+	script, err := json.Marshal(objectsMap{
+		"source": addUpgradeScript,
+		"lang":   "painless",
+		"params": objectsMap{"upgrade": upgrade},
+	})
+	if err != nil {
+		return err
+	}
+
+	serializedDoc, err := json.Marshal(&data.ScDeployInfo{
+		Upgrades: []*data.ScUpgradeInfo{upgrade},
+	})
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpsert(address, script, serializedDoc)
+}
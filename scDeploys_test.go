@@ -0,0 +1,134 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxDatabaseProcessor_ComputeScAddress(t *testing.T) {
+	t.Parallel()
+
+	txDbProc := createTestTxDatabaseProcessor(&mock.ShardCoordinatorMock{})
+	// erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th
+	deployer, _ := hex.DecodeString("0139472eff6886771a982f3083da5d421f24c29181e63888228dc81ca60d69e1")
+
+	scAddress, err := txDbProc.computeScAddress(deployer, 0, []byte{5, 0})
+	require.Nil(t, err)
+	// erd1qqqqqqqqqqqqqpgqak8zt22wl2ph4tswtyc39namqx6ysa2sd8ss4xmlj3
+	require.Equal(t, "00000000000000000500ed8e25a94efa837aae0e593112cfbb01b448755069e1", hex.EncodeToString(scAddress))
+
+	scAddress, err = txDbProc.computeScAddress(deployer, 42, []byte{5, 0})
+	require.Nil(t, err)
+	// erd1qqqqqqqqqqqqqpgq3ytm9m8dpeud35v3us20vsafp77smqghd8ss4jtm0q
+	require.Equal(t, "000000000000000005008917b2eced0e78d8d191e414f643a90fbd0d811769e1", hex.EncodeToString(scAddress))
+
+	_, err = txDbProc.computeScAddress(deployer, 0, []byte{5})
+	require.ErrorIs(t, err, ErrInvalidScDeploy)
+}
+
+func TestTxDatabaseProcessor_GetScDeploys(t *testing.T) {
+	t.Parallel()
+
+	txDbProc := createTestTxDatabaseProcessor(&mock.ShardCoordinatorMock{})
+	deployer := []byte("deployer address of 32 bytes....")
+	encodedDeployer := hex.EncodeToString(deployer)
+	scAddress, _ := txDbProc.computeScAddress(deployer, 3, []byte{5, 0})
+	encodedSCAddress := hex.EncodeToString(scAddress)
+	code := []byte("code")
+	encodedCodeHash := hex.EncodeToString(txDbProc.hasher.Compute(string(code)))
+
+	transactions := map[string]*data.Transaction{
+		"deploy": {
+			Hash:      "deploy",
+			Nonce:     3,
+			Sender:    encodedDeployer,
+			Data:      []byte(hex.EncodeToString(code) + "@0500@0100"),
+			Status:    "success",
+			Operation: operationSCDeploy,
+			Timestamp: 100,
+			SmartContractResults: []data.ScResult{
+				{Receiver: encodedSCAddress, CodeMetadata: []byte{5, 6}},
+			},
+		},
+		"failedDeploy": {
+			Hash:      "failedDeploy",
+			Nonce:     4,
+			Sender:    encodedDeployer,
+			Data:      []byte(hex.EncodeToString(code) + "@0500@0100"),
+			Status:    "fail",
+			Operation: operationSCDeploy,
+		},
+		"upgrade": {
+			Hash:      "upgrade",
+			Sender:    encodedDeployer,
+			Receiver:  encodedSCAddress,
+			Data:      []byte(upgradeContractFunction + "@" + hex.EncodeToString(code) + "@0102"),
+			Status:    "success",
+			Operation: operationSCCall,
+			Function:  upgradeContractFunction,
+			Timestamp: 200,
+		},
+		"upgradeOtherShard": {
+			Hash:          "upgradeOtherShard",
+			Sender:        encodedDeployer,
+			Receiver:      encodedSCAddress,
+			Data:          []byte(upgradeContractFunction + "@" + hex.EncodeToString(code) + "@0102"),
+			Status:        "success",
+			Operation:     operationSCCall,
+			Function:      upgradeContractFunction,
+			ReceiverShard: 1,
+		},
+	}
+
+	results := txDbProc.getScDeploys(transactions, 0)
+	require.Equal(t, map[string]*data.ScDeployInfo{
+		encodedSCAddress: {
+			TxHash:       "deploy",
+			Deployer:     encodedDeployer,
+			Timestamp:    100,
+			CodeHash:     encodedCodeHash,
+			CodeMetadata: "0506",
+			Upgrades:     []*data.ScUpgradeInfo{},
+		},
+	}, results.deploys)
+	require.Equal(t, map[string][]*data.ScUpgradeInfo{
+		encodedSCAddress: {
+			{
+				TxHash:       "upgrade",
+				Upgrader:     encodedDeployer,
+				Timestamp:    200,
+				CodeHash:     encodedCodeHash,
+				CodeMetadata: "0102",
+			},
+		},
+	}, results.upgrades)
+}
+
+func TestSerializeScDeploys(t *testing.T) {
+	t.Parallel()
+
+	results := &scDeploysResults{
+		deploys: map[string]*data.ScDeployInfo{
+			"sc1": {TxHash: "deploy", Deployer: "deployer", Upgrades: []*data.ScUpgradeInfo{}},
+		},
+		upgrades: map[string][]*data.ScUpgradeInfo{
+			"sc2": {{TxHash: "upgrade", Upgrader: "upgrader"}},
+		},
+	}
+
+	buffSlice := data.NewBufferSlice()
+	err := serializeScDeploys(results, buffSlice)
+	require.Nil(t, err)
+
+	serialized := buffSlice.Buffers()[0].String()
+	require.True(t, strings.HasPrefix(serialized, `{"update":{"_id":"sc1"}}`))
+	require.Contains(t, serialized, `"params":{"deploy":{"deployTxHash":"deploy","deployer":"deployer"`)
+	require.Contains(t, serialized, `{"update":{"_id":"sc2"}}`)
+	require.Contains(t, serialized, `"params":{"upgrade":{"upgradeTxHash":"upgrade","upgrader":"upgrader"`)
+	require.Contains(t, serialized, `"upgrades":[{"upgradeTxHash":"upgrade"`)
+}
@@ -11,6 +11,21 @@ var AccountsHistory = Object{
 	},
 	"mappings": Object{
 		"properties": Object{
+			"address": Object{
+				"type": "keyword",
+			},
+			"balanceChange": Object{
+				"type": "keyword",
+			},
+			"txHash": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"blockNonce": Object{
+				"type": "long",
+			},
 			"timestamp": Object{
 				"type": "date",
 			},
@@ -0,0 +1,51 @@
+package noKibana
+
+// AddressActivity will hold the configuration for the address activity index
+var AddressActivity = Object{
+	"index_patterns": Array{
+		"addressactivity-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+		"index": Object{
+			"sort.field": Array{
+				"timestamp",
+			},
+			"sort.order": Array{
+				"desc",
+			},
+		},
+	},
+	"mappings": Object{
+		"properties": Object{
+			"address": Object{
+				"type": "keyword",
+			},
+			"txHash": Object{
+				"type": "keyword",
+			},
+			"originalTxHash": Object{
+				"type": "keyword",
+			},
+			"direction": Object{
+				"type": "keyword",
+			},
+			"token": Object{
+				"type": "keyword",
+			},
+			"value": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"blockNonce": Object{
+				"type": "long",
+			},
+		},
+	},
+}
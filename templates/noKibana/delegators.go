@@ -0,0 +1,50 @@
+package noKibana
+
+// Delegators will hold the configuration for the delegators index
+var Delegators = Object{
+	"index_patterns": Array{
+		"delegators-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"delegator": Object{
+				"type": "keyword",
+			},
+			"contract": Object{
+				"type": "keyword",
+			},
+			"activeStake": Object{
+				"type": "keyword",
+			},
+			"claimedRewards": Object{
+				"type": "keyword",
+			},
+			"appliedTxHashes": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+			"unstaked": Object{
+				"properties": Object{
+					"txHash": Object{
+						"type": "keyword",
+					},
+					"value": Object{
+						"type": "keyword",
+					},
+					"timestamp": Object{
+						"type": "date",
+					},
+					"withdrawTxHash": Object{
+						"type": "keyword",
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,40 @@
+package noKibana
+
+// NFTs will hold the configuration for the nfts index
+var NFTs = Object{
+	"index_patterns": Array{
+		"nfts-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"identifier": Object{
+				"type": "keyword",
+			},
+			"token": Object{
+				"type": "keyword",
+			},
+			"nonce": Object{
+				"type": "long",
+			},
+			"creator": Object{
+				"type": "keyword",
+			},
+			"tags": Object{
+				"type": "keyword",
+			},
+			"supply": Object{
+				"type": "keyword",
+			},
+			"appliedTxHashes": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+		},
+	},
+}
@@ -0,0 +1,35 @@
+package noKibana
+
+// Providers will hold the configuration for the providers index
+var Providers = Object{
+	"index_patterns": Array{
+		"providers-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"contract": Object{
+				"type": "keyword",
+			},
+			"owner": Object{
+				"type": "keyword",
+			},
+			"serviceFee": Object{
+				"type": "long",
+			},
+			"maxCap": Object{
+				"type": "keyword",
+			},
+			"updates": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+		},
+	},
+}
@@ -9,15 +9,22 @@ var Rating = Object{
 		"number_of_shards":   1,
 		"number_of_replicas": 0,
 	},
-
 	"mappings": Object{
 		"properties": Object{
-			"validatorsRating": Object{
-				"properties": Object{
-					"rating": Object{
-						"type": "float",
-					},
-				},
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"rating": Object{
+				"type": "float",
+			},
+			"timestamp": Object{
+				"type": "date",
 			},
 		},
 	},
@@ -0,0 +1,48 @@
+package noKibana
+
+// Tokens will hold the configuration for the tokens index
+var Tokens = Object{
+	"index_patterns": Array{
+		"tokens-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"identifier": Object{
+				"type": "keyword",
+			},
+			"token": Object{
+				"type": "keyword",
+			},
+			"ticker": Object{
+				"type": "keyword",
+			},
+			"type": Object{
+				"type": "keyword",
+			},
+			"owner": Object{
+				"type": "keyword",
+			},
+			"roles": Object{
+				"type": "nested",
+				"properties": Object{
+					"role": Object{
+						"type": "keyword",
+					},
+					"addresses": Object{
+						"type": "keyword",
+					},
+				},
+			},
+			"frozen": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+		},
+	},
+}
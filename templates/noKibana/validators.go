@@ -9,4 +9,51 @@ var Validators = Object{
 		"number_of_shards":   1,
 		"number_of_replicas": 0,
 	},
+	"mappings": Object{
+		"properties": Object{
+			"blsKey": Object{
+				"type": "keyword",
+			},
+			"type": Object{
+				"type": "keyword",
+			},
+			"list": Object{
+				"type": "keyword",
+			},
+			"operationList": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"owner": Object{
+				"type": "keyword",
+			},
+			"stake": Object{
+				"type": "keyword",
+			},
+			"topUp": Object{
+				"type": "keyword",
+			},
+			"topUpTxHashes": Object{
+				"type": "keyword",
+			},
+			"leftEligibleInEpoch": Object{
+				"type": "long",
+			},
+			"previousState": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+			"rating": Object{
+				"type": "float",
+			},
+			"publicKeys": Object{
+				"type": "keyword",
+			},
+		},
+	},
 }
@@ -0,0 +1,41 @@
+package noKibana
+
+// ValidatorsStatistics will hold the configuration for the validatorsstatistics index
+var ValidatorsStatistics = Object{
+	"index_patterns": Array{
+		"validatorsstatistics-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"blsKey": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"proposed": Object{
+				"type": "long",
+			},
+			"missed": Object{
+				"type": "long",
+			},
+			"signed": Object{
+				"type": "long",
+			},
+			"lastRound": Object{
+				"type": "long",
+			},
+			"recentRounds": Object{
+				"type":    "object",
+				"enabled": false,
+			},
+		},
+	},
+}
@@ -21,6 +21,33 @@ var Accounts = Object{
 			"balanceNum": Object{
 				"type": "double",
 			},
+			"balanceScaled": Object{
+				"type": "long",
+			},
+			"userName": Object{
+				"type": "keyword",
+			},
+			"isSmartContract": Object{
+				"type": "boolean",
+			},
+			"codeHash": Object{
+				"type": "keyword",
+			},
+			"isUpgradeable": Object{
+				"type": "boolean",
+			},
+			"isReadable": Object{
+				"type": "boolean",
+			},
+			"isPayable": Object{
+				"type": "boolean",
+			},
+			"ownerAddress": Object{
+				"type": "keyword",
+			},
+			"developerReward": Object{
+				"type": "keyword",
+			},
 		},
 	},
 }
@@ -23,9 +23,27 @@ var Blocks = Object{
 			"nonce": Object{
 				"type": "long",
 			},
+			"proposerBlsKey": Object{
+				"type": "keyword",
+			},
+			"validatorsBlsKeys": Object{
+				"type": "keyword",
+			},
 			"timestamp": Object{
 				"type": "date",
 			},
+			"accumulatedFeesNum": Object{
+				"type": "double",
+			},
+			"accumulatedFeesScaled": Object{
+				"type": "long",
+			},
+			"developerFeesNum": Object{
+				"type": "double",
+			},
+			"developerFeesScaled": Object{
+				"type": "long",
+			},
 		},
 	},
 }
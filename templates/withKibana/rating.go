@@ -12,12 +12,20 @@ var Rating = Object{
 	},
 	"mappings": Object{
 		"properties": Object{
-			"validatorsRating": Object{
-				"properties": Object{
-					"rating": Object{
-						"type": "float",
-					},
-				},
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"rating": Object{
+				"type": "float",
+			},
+			"timestamp": Object{
+				"type": "date",
 			},
 		},
 	},
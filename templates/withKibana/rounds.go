@@ -10,4 +10,11 @@ var Rounds = Object{
 		"number_of_replicas": 0,
 		"opendistro.index_state_management.rollover_alias": "rounds",
 	},
+	"mappings": Object{
+		"properties": Object{
+			"signersBlsKeys": Object{
+				"type": "keyword",
+			},
+		},
+	},
 }
@@ -0,0 +1,50 @@
+package withKibana
+
+// ScDeploys will hold the configuration for the scdeploys index
+var ScDeploys = Object{
+	"index_patterns": Array{
+		"scdeploys-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"deployTxHash": Object{
+				"type": "keyword",
+			},
+			"deployer": Object{
+				"type": "keyword",
+			},
+			"codeHash": Object{
+				"type": "keyword",
+			},
+			"codeMetadata": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+			"upgrades": Object{
+				"properties": Object{
+					"upgradeTxHash": Object{
+						"type": "keyword",
+					},
+					"upgrader": Object{
+						"type": "keyword",
+					},
+					"codeHash": Object{
+						"type": "keyword",
+					},
+					"codeMetadata": Object{
+						"type": "keyword",
+					},
+					"timestamp": Object{
+						"type": "date",
+					},
+				},
+			},
+		},
+	},
+}
@@ -26,6 +26,82 @@ var Transactions = Object{
 			"timestamp": Object{
 				"type": "date",
 			},
+			"miniBlockHash": Object{
+				"type": "keyword",
+			},
+			"sourceBlockHash": Object{
+				"type": "keyword",
+			},
+			"sourceTimestamp": Object{
+				"type": "date",
+			},
+			"destinationBlockHash": Object{
+				"type": "keyword",
+			},
+			"destinationTimestamp": Object{
+				"type": "date",
+			},
+			"notarizedAtSourceInMetaNonce": Object{
+				"type": "long",
+			},
+			"notarizedAtDestinationInMetaNonce": Object{
+				"type": "long",
+			},
+			"operation": Object{
+				"type": "keyword",
+			},
+			"function": Object{
+				"type": "keyword",
+			},
+			"tokens": Object{
+				"type": "keyword",
+			},
+			"esdtValues": Object{
+				"type": "keyword",
+			},
+			"receivers": Object{
+				"type": "keyword",
+			},
+			"innerSender": Object{
+				"type": "keyword",
+			},
+			"innerReceiver": Object{
+				"type": "keyword",
+			},
+			"innerFunction": Object{
+				"type": "keyword",
+			},
+			"valueNum": Object{
+				"type": "double",
+			},
+			"valueScaled": Object{
+				"type": "long",
+			},
+			"feeNum": Object{
+				"type": "double",
+			},
+			"feeScaled": Object{
+				"type": "long",
+			},
+			"scResults": Object{
+				"properties": Object{
+					"operation": Object{
+						"type": "keyword",
+					},
+					"function": Object{
+						"type": "keyword",
+					},
+					"tokens": Object{
+						"type": "keyword",
+					},
+					"esdtValues": Object{
+						"type": "keyword",
+					},
+					"receivers": Object{
+						"type": "keyword",
+					},
+				},
+			},
 		},
 	},
 }
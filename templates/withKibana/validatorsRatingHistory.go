@@ -0,0 +1,31 @@
+package withKibana
+
+// ValidatorsRatingHistory will hold the configuration for the validatorsratinghistory index
+var ValidatorsRatingHistory = Object{
+	"index_patterns": Array{
+		"validatorsratinghistory-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"publicKey": Object{
+				"type": "keyword",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"epoch": Object{
+				"type": "long",
+			},
+			"rating": Object{
+				"type": "float",
+			},
+			"timestamp": Object{
+				"type": "date",
+			},
+		},
+	},
+}
@@ -0,0 +1,320 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+)
+
+const (
+	issueFungibleFunction     = "issue"
+	issueSemiFungibleFunction = "issueSemiFungible"
+	issueNonFungibleFunction  = "issueNonFungible"
+	registerMetaESDTFunction  = "registerMetaESDT"
+	setSpecialRoleFunction    = "setSpecialRole"
+	transferOwnershipFunction = "transferOwnership"
+	pauseFunction             = "pause"
+	unPauseFunction           = "unPause"
+	freezeFunction            = "freeze"
+	unFreezeFunction          = "unFreeze"
+
+	// metaESDT defines the string for the token type of ESDT tokens registered with registerMetaESDT
+	metaESDT = "MetaESDT"
+
+	minArgsIssueFungible  = 4
+	minArgsIssueNFT       = 2
+	minArgsRegisterMeta   = 3
+	minArgsSetSpecialRole = 3
+	minArgsWithAddress    = 2
+	minArgsWithIdentifier = 1
+)
+
+// esdtSCAddress is the address of the system smart contract that issues and manages the ESDT tokens
+var esdtSCAddress = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 255, 255}
+
+const (
+	setRolesScript = `if (ctx._source.roles == null) { ctx._source.roles = new ArrayList(); } ` +
+		`for (role in params.roles) { def entry = null; ` +
+		`for (item in ctx._source.roles) { if (item.role == role) { entry = item; break; } } ` +
+		`if (entry == null) { entry = ['role': role, 'addresses': new ArrayList()]; ctx._source.roles.add(entry); } ` +
+		`if (!entry.addresses.contains(params.address)) { entry.addresses.add(params.address); } }`
+	unsetRolesScript = `if (ctx._source.roles != null) { for (item in ctx._source.roles) { ` +
+		`if (params.roles.contains(item.role)) { int idx = item.addresses.indexOf(params.address); ` +
+		`if (idx >= 0) { item.addresses.remove(idx); } } } ` +
+		`ctx._source.roles.removeIf(item -> item.addresses.isEmpty()); }`
+	setOwnerScript  = `ctx._source.owner = params.address;`
+	setPausedScript = `ctx._source.paused = params.paused;`
+	freezeScript    = `if (ctx._source.frozen == null) { ctx._source.frozen = new ArrayList(); } ` +
+		`if (!ctx._source.frozen.contains(params.address)) { ctx._source.frozen.add(params.address); }`
+	unFreezeScript = `if (ctx._source.frozen != null) { int idx = ctx._source.frozen.indexOf(params.address); ` +
+		`if (idx >= 0) { ctx._source.frozen.remove(idx); } }`
+)
+
+// tokenRegistryOperation holds a successful call to the system ESDT smart contract that alters the tokens registry
+type tokenRegistryOperation struct {
+	function   string
+	identifier string
+	sender     string
+	address    string
+	roles      []string
+	token      *data.TokenInfo
+}
+
+// getTokensRegistryOperations returns the successful calls to the system ESDT smart contract in the execution order of
+// the provided block. The outcome of these calls is known only in metachain, so the other shards do not return any
+// operation. The transactions map has to be keyed by the raw transaction hashes
+func (tdp *txDatabaseProcessor) getTokensRegistryOperations(
+	body *block.Body,
+	transactions map[string]*data.Transaction,
+	selfShardID uint32,
+) []*tokenRegistryOperation {
+	operations := make([]*tokenRegistryOperation, 0)
+	if selfShardID != core.MetachainShardId || body == nil {
+		return operations
+	}
+
+	encodedESDTSCAddress := tdp.addressPubkeyConverter.Encode(esdtSCAddress)
+	for _, mb := range body.MiniBlocks {
+		if mb.Type != block.TxBlock || mb.ReceiverShardID != core.MetachainShardId {
+			continue
+		}
+
+		for _, txHash := range mb.TxHashes {
+			tx, ok := transactions[string(txHash)]
+			if !ok || tx.Receiver != encodedESDTSCAddress {
+				continue
+			}
+
+			operation, ok := tdp.parseTokenRegistryOperation(tx, encodedESDTSCAddress)
+			if ok {
+				operations = append(operations, operation)
+			}
+		}
+	}
+
+	return operations
+}
+
+func (tdp *txDatabaseProcessor) parseTokenRegistryOperation(tx *data.Transaction, encodedESDTSCAddress string) (*tokenRegistryOperation, bool) {
+	if tx.Status == transaction.TxStatusInvalid.String() || tx.Status == transaction.TxStatusFail.String() {
+		return nil, false
+	}
+
+	function, args, ok := decodeCallArguments(tx.Data)
+	if !ok {
+		return nil, false
+	}
+
+	operation := &tokenRegistryOperation{
+		function: function,
+		sender:   tx.Sender,
+	}
+
+	switch function {
+	case issueFungibleFunction, issueSemiFungibleFunction, issueNonFungibleFunction, registerMetaESDTFunction:
+		token, isValid := createIssuedToken(function, args)
+		if !isValid {
+			return nil, false
+		}
+
+		operation.identifier = getIssuedTokenIdentifier(tx.SmartContractResults, encodedESDTSCAddress)
+		if operation.identifier == "" {
+			return nil, false
+		}
+
+		token.Identifier = operation.identifier
+		token.Token = operation.identifier
+		token.Owner = tx.Sender
+		token.Timestamp = tx.Timestamp
+		operation.token = token
+
+		return operation, true
+	case setSpecialRoleFunction:
+		if len(args) < minArgsSetSpecialRole {
+			return nil, false
+		}
+		operation.address = tdp.addressPubkeyConverter.Encode(args[1])
+		for _, role := range args[2:] {
+			operation.roles = append(operation.roles, string(role))
+		}
+	case transferOwnershipFunction, freezeFunction, unFreezeFunction:
+		if len(args) < minArgsWithAddress {
+			return nil, false
+		}
+		operation.address = tdp.addressPubkeyConverter.Encode(args[1])
+	case pauseFunction, unPauseFunction:
+		if len(args) < minArgsWithIdentifier {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	if !isESDTCallSuccessful(tx.SmartContractResults, encodedESDTSCAddress) {
+		return nil, false
+	}
+
+	operation.identifier = string(args[0])
+
+	return operation, true
+}
+
+func createIssuedToken(function string, args [][]byte) (*data.TokenInfo, bool) {
+	token := &data.TokenInfo{
+		Roles:  make([]*data.TokenRoles, 0),
+		Frozen: make([]string, 0),
+	}
+
+	switch function {
+	case issueFungibleFunction:
+		if len(args) < minArgsIssueFungible {
+			return nil, false
+		}
+		token.Type = core.FungibleESDT
+		token.Decimals = big.NewInt(0).SetBytes(args[3]).Uint64()
+	case issueSemiFungibleFunction:
+		if len(args) < minArgsIssueNFT {
+			return nil, false
+		}
+		token.Type = core.SemiFungibleESDT
+	case issueNonFungibleFunction:
+		if len(args) < minArgsIssueNFT {
+			return nil, false
+		}
+		token.Type = core.NonFungibleESDT
+	case registerMetaESDTFunction:
+		if len(args) < minArgsRegisterMeta {
+			return nil, false
+		}
+		token.Type = metaESDT
+		token.Decimals = big.NewInt(0).SetBytes(args[2]).Uint64()
+	}
+
+	token.Name = string(args[0])
+	token.Ticker = string(args[1])
+
+	return token, true
+}
+
+// getIssuedTokenIdentifier extracts the identifier of an issued token from the results of the system ESDT smart
+// contract. The identifier is either sent together with the initial supply or returned after the ok return code
+func getIssuedTokenIdentifier(scrs []data.ScResult, encodedESDTSCAddress string) string {
+	for _, scr := range scrs {
+		if scr.Sender != encodedESDTSCAddress {
+			continue
+		}
+
+		function, args, ok := decodeCallArguments(scr.Data)
+		if !ok || len(args) == 0 {
+			continue
+		}
+
+		if function == core.BuiltInFunctionESDTTransfer {
+			return string(args[0])
+		}
+		if function == "" && isScResultSuccessful(scr.Data) && len(args) > 1 {
+			return string(args[1])
+		}
+	}
+
+	return ""
+}
+
+func isESDTCallSuccessful(scrs []data.ScResult, encodedESDTSCAddress string) bool {
+	for _, scr := range scrs {
+		if scr.Sender != encodedESDTSCAddress {
+			continue
+		}
+
+		isRoleSet := strings.HasPrefix(string(scr.Data), core.BuiltInFunctionSetESDTRole)
+		if isRoleSet || isScResultSuccessful(scr.Data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeCallArguments splits the data field of a smart contract call in the called function and its hex decoded
+// arguments
+func decodeCallArguments(txData []byte) (string, [][]byte, bool) {
+	tokens := strings.Split(string(txData), dataArgumentSeparator)
+	args := make([][]byte, 0, len(tokens)-1)
+	for _, token := range tokens[1:] {
+		arg, err := hex.DecodeString(token)
+		if err != nil {
+			return "", nil, false
+		}
+
+		args = append(args, arg)
+	}
+
+	return tokens[0], args, true
+}
+
+// putTokenRegistryOperation adds the bulk operation that applies the provided registry operation
+func putTokenRegistryOperation(operation *tokenRegistryOperation, buffSlice bulkBuffer) error {
+	switch operation.function {
+	case issueFungibleFunction, issueSemiFungibleFunction, issueNonFungibleFunction, registerMetaESDTFunction:
+		serializedToken, err := json.Marshal(operation.token)
+		if err != nil {
+			return err
+		}
+
+		return buffSlice.PutIndex(operation.identifier, serializedToken)
+	case setSpecialRoleFunction:
+		return putUpdateScript(operation.identifier, setRolesScript, objectsMap{"address": operation.address, "roles": operation.roles}, buffSlice)
+	case transferOwnershipFunction:
+		return putUpdateScript(operation.identifier, setOwnerScript, objectsMap{"address": operation.address}, buffSlice)
+	case pauseFunction, unPauseFunction:
+		return putUpdateScript(operation.identifier, setPausedScript, objectsMap{"paused": operation.function == pauseFunction}, buffSlice)
+	case freezeFunction:
+		return putUpdateScript(operation.identifier, freezeScript, objectsMap{"address": operation.address}, buffSlice)
+	case unFreezeFunction:
+		return putUpdateScript(operation.identifier, unFreezeScript, objectsMap{"address": operation.address}, buffSlice)
+	}
+
+	return nil
+}
+
+// putTokenRegistryRevert adds the bulk operation that undoes the provided registry operation. The system ESDT smart
+// contract rejects the calls that would not change the registry, so every operation can be undone without knowing
+// the previous state of the token
+func putTokenRegistryRevert(operation *tokenRegistryOperation, buffSlice bulkBuffer) error {
+	switch operation.function {
+	case issueFungibleFunction, issueSemiFungibleFunction, issueNonFungibleFunction, registerMetaESDTFunction:
+		return buffSlice.PutDelete(operation.identifier)
+	case setSpecialRoleFunction:
+		return putUpdateScript(operation.identifier, unsetRolesScript, objectsMap{"address": operation.address, "roles": operation.roles}, buffSlice)
+	case transferOwnershipFunction:
+		// only the owner of the token can transfer the ownership
+		return putUpdateScript(operation.identifier, setOwnerScript, objectsMap{"address": operation.sender}, buffSlice)
+	case pauseFunction, unPauseFunction:
+		return putUpdateScript(operation.identifier, setPausedScript, objectsMap{"paused": operation.function != pauseFunction}, buffSlice)
+	case freezeFunction:
+		return putUpdateScript(operation.identifier, unFreezeScript, objectsMap{"address": operation.address}, buffSlice)
+	case unFreezeFunction:
+		return putUpdateScript(operation.identifier, freezeScript, objectsMap{"address": operation.address}, buffSlice)
+	}
+
+	return nil
+}
+
+func putUpdateScript(identifier string, source string, params objectsMap, buffSlice bulkBuffer) error {
+	script, err := json.Marshal(objectsMap{
+		"source": source,
+		"lang":   "painless",
+		"params": params,
+	})
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpdateScript(identifier, script)
+}
@@ -0,0 +1,221 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func createESDTCallData(function string, args ...[]byte) []byte {
+	txData := function
+	for _, arg := range args {
+		txData += "@" + hex.EncodeToString(arg)
+	}
+
+	return []byte(txData)
+}
+
+func createTokensRegistryTxPool() map[string]coreData.TransactionHandler {
+	owner := []byte("owner")
+	return map[string]coreData.TransactionHandler{
+		"issue": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: esdtSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(issueFungibleFunction, []byte("Token"), []byte("TKN"), big.NewInt(1000).Bytes(), big.NewInt(18).Bytes()),
+		},
+		"issueFailed": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: esdtSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(issueNonFungibleFunction, []byte("Other"), []byte("OTH")),
+		},
+		"setRole": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: esdtSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(setSpecialRoleFunction, []byte("TKN-abcdef"), []byte("holder"), []byte(core.ESDTRoleLocalMint)),
+		},
+		"transfer": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: []byte("receiver"),
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(pauseFunction, []byte("TKN-abcdef")),
+		},
+		"scr1": &smartContractResult.SmartContractResult{
+			SndAddr:        esdtSCAddress,
+			RcvAddr:        owner,
+			OriginalTxHash: []byte("issue"),
+			Value:          big.NewInt(0),
+			Data:           createESDTCallData(core.BuiltInFunctionESDTTransfer, []byte("TKN-abcdef"), big.NewInt(1000).Bytes()),
+		},
+		"scr2": &smartContractResult.SmartContractResult{
+			SndAddr:        esdtSCAddress,
+			RcvAddr:        owner,
+			OriginalTxHash: []byte("issue"),
+			Value:          big.NewInt(0),
+			Data:           []byte("@6f6b"),
+		},
+		"scr3": &smartContractResult.SmartContractResult{
+			SndAddr:        esdtSCAddress,
+			RcvAddr:        owner,
+			OriginalTxHash: []byte("issueFailed"),
+			Value:          big.NewInt(0),
+			Data:           createESDTCallData("", []byte("ticker not valid")),
+		},
+		"scr4": &smartContractResult.SmartContractResult{
+			SndAddr:        esdtSCAddress,
+			RcvAddr:        []byte("holder"),
+			OriginalTxHash: []byte("setRole"),
+			Value:          big.NewInt(0),
+			Data:           createESDTCallData(core.BuiltInFunctionSetESDTRole, []byte("TKN-abcdef"), []byte(core.ESDTRoleLocalMint)),
+		},
+		"scr5": &smartContractResult.SmartContractResult{
+			SndAddr:        esdtSCAddress,
+			RcvAddr:        owner,
+			OriginalTxHash: []byte("setRole"),
+			Value:          big.NewInt(0),
+			Data:           []byte("@6f6b"),
+		},
+	}
+}
+
+func TestTxDatabaseProcessor_GetTokensRegistryOperations(t *testing.T) {
+	t.Parallel()
+
+	body := createSystemSCCallsBody("issue", "issueFailed", "setRole", "transfer")
+	results := prepareSystemSCCalls(body, createTokensRegistryTxPool(), core.MetachainShardId)
+	require.Len(t, results.tokensRegistryOperations, 2)
+
+	issue := results.tokensRegistryOperations[0]
+	require.Equal(t, issueFungibleFunction, issue.function)
+	require.Equal(t, "TKN-abcdef", issue.identifier)
+	require.Equal(t, &data.TokenInfo{
+		Identifier: "TKN-abcdef",
+		Token:      "TKN-abcdef",
+		Name:       "Token",
+		Ticker:     "TKN",
+		Type:       core.FungibleESDT,
+		Owner:      hex.EncodeToString([]byte("owner")),
+		Decimals:   18,
+		Roles:      []*data.TokenRoles{},
+		Frozen:     []string{},
+	}, issue.token)
+
+	setRole := results.tokensRegistryOperations[1]
+	require.Equal(t, setSpecialRoleFunction, setRole.function)
+	require.Equal(t, "TKN-abcdef", setRole.identifier)
+	require.Equal(t, hex.EncodeToString([]byte("holder")), setRole.address)
+	require.Equal(t, []string{core.ESDTRoleLocalMint}, setRole.roles)
+}
+
+func TestTxDatabaseProcessor_GetTokensRegistryOperationsNotInMetachain(t *testing.T) {
+	t.Parallel()
+
+	body := createSystemSCCallsBody("issue", "issueFailed", "setRole", "transfer")
+	results := prepareSystemSCCalls(body, createTokensRegistryTxPool(), 0)
+	require.Len(t, results.tokensRegistryOperations, 0)
+}
+
+func TestPutTokenRegistryOperationAndRevert(t *testing.T) {
+	t.Parallel()
+
+	operations := []*tokenRegistryOperation{
+		{function: issueNonFungibleFunction, identifier: "NFT-abcdef", token: &data.TokenInfo{Identifier: "NFT-abcdef"}},
+		{function: setSpecialRoleFunction, identifier: "NFT-abcdef", address: "holder", roles: []string{core.ESDTRoleNFTCreate}},
+		{function: transferOwnershipFunction, identifier: "NFT-abcdef", sender: "owner", address: "new owner"},
+		{function: pauseFunction, identifier: "NFT-abcdef"},
+		{function: freezeFunction, identifier: "NFT-abcdef", address: "frozen"},
+	}
+
+	buffSlice := data.NewBufferSlice()
+	for _, operation := range operations {
+		require.Nil(t, putTokenRegistryOperation(operation, buffSlice))
+	}
+	applied := buffSlice.Buffers()[0].String()
+	require.True(t, strings.HasPrefix(applied, `{"index":{"_id":"NFT-abcdef"}}`))
+	require.Contains(t, applied, `"params":{"address":"holder","roles":["ESDTRoleNFTCreate"]}`)
+	require.Contains(t, applied, `"params":{"address":"new owner"}`)
+	require.Contains(t, applied, `"params":{"paused":true}`)
+	require.Contains(t, applied, `"params":{"address":"frozen"}`)
+
+	buffSlice = data.NewBufferSlice()
+	for _, operation := range operations {
+		require.Nil(t, putTokenRegistryRevert(operation, buffSlice))
+	}
+	reverted := buffSlice.Buffers()[0].String()
+	require.True(t, strings.HasPrefix(reverted, `{"delete":{"_id":"NFT-abcdef"}}`))
+	require.Contains(t, reverted, `"params":{"address":"owner"}`)
+	require.Contains(t, reverted, `"params":{"paused":false}`)
+	require.Contains(t, reverted, `ctx._source.frozen.remove(idx)`)
+}
+
+func TestElasticProcessor_RevertTokens(t *testing.T) {
+	t.Parallel()
+
+	encodedESDTSCAddress := hex.EncodeToString(esdtSCAddress)
+	issueTx := &data.Transaction{
+		Sender:   hex.EncodeToString([]byte("owner")),
+		Receiver: encodedESDTSCAddress,
+		Data:     createESDTCallData(registerMetaESDTFunction, []byte("Meta"), []byte("MTA"), big.NewInt(6).Bytes()),
+		Status:   transaction.TxStatusSuccess.String(),
+		SmartContractResults: []data.ScResult{
+			{Sender: encodedESDTSCAddress, Data: createESDTCallData("", []byte("ok"), []byte("MTA-abcdef"))},
+		},
+	}
+	serializedTx, _ := json.Marshal(issueTx)
+	source := make(map[string]interface{})
+	_ = json.Unmarshal(serializedTx, &source)
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[tokensIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, txIndex, index)
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": hex.EncodeToString([]byte("issue")), "found": true, "_source": source},
+					map[string]interface{}{"_id": hex.EncodeToString([]byte("missing")), "found": false},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	args.ShardCoordinator = &mock.ShardCoordinatorMock{SelfID: core.MetachainShardId}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	body := &dataBlock.Body{
+		MiniBlocks: []*dataBlock.MiniBlock{
+			{
+				Type:            dataBlock.TxBlock,
+				ReceiverShardID: core.MetachainShardId,
+				TxHashes:        [][]byte{[]byte("issue"), []byte("missing")},
+			},
+		},
+	}
+
+	err = elasticProc.RevertTokens(&dataBlock.Header{}, body)
+	require.Nil(t, err)
+	require.Empty(t, bulkRequests)
+
+	err = elasticProc.RevertTokens(&dataBlock.MetaBlock{}, body)
+	require.Nil(t, err)
+	require.Equal(t, `{"delete":{"_id":"MTA-abcdef"}}`+"\n", bulkRequests[tokensIndex])
+}
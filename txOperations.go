@@ -0,0 +1,220 @@
+package indexer
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+)
+
+const (
+	operationTransfer = "transfer"
+	operationSCDeploy = "scDeploy"
+	operationSCCall   = "scCall"
+	operationRelayed  = "relayed"
+	operationReward   = "reward"
+	operationInvalid  = "invalid"
+
+	minArgsESDTTransfer         = 2
+	minArgsESDTNFTTransfer      = 4
+	minArgsMultiESDTNFTTransfer = 2
+	argsPerMultiTransferToken   = 3
+	numArgsRelayedTxV2          = 4
+)
+
+// builtInFunctions holds the built-in functions that can be called on user accounts. The transfers that cannot be
+// decoded are classified as calls of these functions
+var builtInFunctions = map[string]struct{}{
+	core.BuiltInFunctionESDTTransfer:              {},
+	core.BuiltInFunctionESDTNFTTransfer:           {},
+	core.BuiltInFunctionMultiESDTNFTTransfer:      {},
+	core.BuiltInFunctionClaimDeveloperRewards:     {},
+	core.BuiltInFunctionChangeOwnerAddress:        {},
+	core.BuiltInFunctionSetUserName:               {},
+	core.BuiltInFunctionSaveKeyValue:              {},
+	core.BuiltInFunctionESDTBurn:                  {},
+	core.BuiltInFunctionESDTFreeze:                {},
+	core.BuiltInFunctionESDTUnFreeze:              {},
+	core.BuiltInFunctionESDTWipe:                  {},
+	core.BuiltInFunctionESDTPause:                 {},
+	core.BuiltInFunctionESDTUnPause:               {},
+	core.BuiltInFunctionSetESDTRole:               {},
+	core.BuiltInFunctionUnSetESDTRole:             {},
+	core.BuiltInFunctionESDTLocalMint:             {},
+	core.BuiltInFunctionESDTLocalBurn:             {},
+	core.BuiltInFunctionESDTNFTCreate:             {},
+	core.BuiltInFunctionESDTNFTAddQuantity:        {},
+	core.BuiltInFunctionESDTNFTCreateRoleTransfer: {},
+	core.BuiltInFunctionESDTNFTBurn:               {},
+	core.BuiltInFunctionESDTNFTAddURI:             {},
+	core.BuiltInFunctionESDTNFTUpdateAttributes:   {},
+}
+
+// txOperation holds the information decoded from the data field of a transaction or of a smart contract result
+type txOperation struct {
+	operation  string
+	function   string
+	tokens     []string
+	esdtValues []string
+	receivers  []string
+}
+
+// parseTxOperation will classify a transaction or a smart contract result based on its receiver and on its data
+// field. The function is set only for the calls, the plain transfers with a message in the data field have none
+func (cm *commonProcessor) parseTxOperation(receiver []byte, txData []byte) *txOperation {
+	result := &txOperation{
+		operation: operationTransfer,
+	}
+	if len(txData) == 0 {
+		return result
+	}
+
+	if len(receiver) > 0 && core.IsEmptyAddress(receiver) {
+		result.operation = operationSCDeploy
+		return result
+	}
+
+	function := strings.Split(string(txData), dataArgumentSeparator)[0]
+	if function == core.RelayedTransaction || function == core.RelayedTransactionV2 {
+		result.operation = operationRelayed
+		return result
+	}
+
+	_, args, ok := decodeCallArguments(txData)
+	switch function {
+	case core.BuiltInFunctionESDTTransfer:
+		if ok && len(args) >= minArgsESDTTransfer {
+			result.operation = function
+			result.tokens = []string{string(args[0])}
+			result.esdtValues = []string{big.NewInt(0).SetBytes(args[1]).String()}
+			result.function = getCalledFunction(args, minArgsESDTTransfer)
+			return result
+		}
+	case core.BuiltInFunctionESDTNFTTransfer:
+		if ok && len(args) >= minArgsESDTNFTTransfer {
+			result.operation = function
+			nonce := big.NewInt(0).SetBytes(args[1]).Uint64()
+			result.tokens = []string{computeTokenIdentifier(string(args[0]), nonce)}
+			result.esdtValues = []string{big.NewInt(0).SetBytes(args[2]).String()}
+			result.receivers = []string{cm.addressPubkeyConverter.Encode(args[3])}
+			result.function = getCalledFunction(args, minArgsESDTNFTTransfer)
+			return result
+		}
+	case core.BuiltInFunctionMultiESDTNFTTransfer:
+		if ok && cm.parseMultiESDTNFTTransfer(args, result) {
+			return result
+		}
+	}
+
+	_, isBuiltInFunction := builtInFunctions[function]
+	if isBuiltInFunction || core.IsSmartContractAddress(receiver) {
+		result.operation = operationSCCall
+		result.function = function
+	}
+
+	return result
+}
+
+func (cm *commonProcessor) parseMultiESDTNFTTransfer(args [][]byte, result *txOperation) bool {
+	if len(args) < minArgsMultiESDTNFTTransfer {
+		return false
+	}
+
+	numTokens := big.NewInt(0).SetBytes(args[1])
+	if numTokens.Sign() == 0 || numTokens.Cmp(big.NewInt(int64(len(args)))) > 0 {
+		return false
+	}
+
+	lastTokenArg := uint64(minArgsMultiESDTNFTTransfer) + numTokens.Uint64()*argsPerMultiTransferToken
+	if lastTokenArg > uint64(len(args)) {
+		return false
+	}
+
+	result.operation = core.BuiltInFunctionMultiESDTNFTTransfer
+	result.receivers = []string{cm.addressPubkeyConverter.Encode(args[0])}
+	for idx := uint64(minArgsMultiESDTNFTTransfer); idx < lastTokenArg; idx += argsPerMultiTransferToken {
+		identifier := string(args[idx])
+		nonce := big.NewInt(0).SetBytes(args[idx+1]).Uint64()
+		if nonce > 0 {
+			identifier = computeTokenIdentifier(identifier, nonce)
+		}
+
+		result.tokens = append(result.tokens, identifier)
+		result.esdtValues = append(result.esdtValues, big.NewInt(0).SetBytes(args[idx+2]).String())
+	}
+	result.function = getCalledFunction(args, int(lastTokenArg))
+
+	return true
+}
+
+// getCalledFunction returns the name of the function called after the arguments of a transfer, if any
+func getCalledFunction(args [][]byte, numTransferArgs int) string {
+	if len(args) <= numTransferArgs {
+		return ""
+	}
+
+	return string(args[numTransferArgs])
+}
+
+// setRelayedInnerTx will decode the user transaction of a relayed transaction and will store its sender, receiver,
+// function, value and transfers on the provided relayed transaction
+func (cm *commonProcessor) setRelayedInnerTx(dbTx *data.Transaction, tx *transaction.Transaction) {
+	innerTx, ok := decodeRelayedInnerTx(tx)
+	if !ok {
+		log.Debug("indexer: cannot decode the inner transaction of a relayed transaction", "hash", dbTx.Hash)
+		return
+	}
+
+	innerValue := big.NewInt(0)
+	if innerTx.Value != nil {
+		innerValue = innerTx.Value
+	}
+
+	innerOperation := cm.parseTxOperation(innerTx.RcvAddr, innerTx.Data)
+	dbTx.InnerSender = cm.addressPubkeyConverter.Encode(innerTx.SndAddr)
+	dbTx.InnerReceiver = cm.addressPubkeyConverter.Encode(innerTx.RcvAddr)
+	dbTx.InnerFunction = innerOperation.function
+	dbTx.InnerValue = innerValue.String()
+	dbTx.Tokens = innerOperation.tokens
+	dbTx.ESDTValues = innerOperation.esdtValues
+	dbTx.Receivers = innerOperation.receivers
+}
+
+// decodeRelayedInnerTx extracts the user transaction from the data field of a relayed transaction. The first version
+// holds the whole user transaction serialized as JSON, while the second version holds only the receiver, the nonce,
+// the data and the signature of the user, who is the receiver of the relayed transaction
+func decodeRelayedInnerTx(tx *transaction.Transaction) (*transaction.Transaction, bool) {
+	function, args, ok := decodeCallArguments(tx.Data)
+	if !ok || len(args) == 0 {
+		return nil, false
+	}
+
+	switch function {
+	case core.RelayedTransaction:
+		innerTx := &transaction.Transaction{}
+		err := json.Unmarshal(args[0], innerTx)
+		if err != nil {
+			return nil, false
+		}
+
+		return innerTx, true
+	case core.RelayedTransactionV2:
+		if len(args) != numArgsRelayedTxV2 {
+			return nil, false
+		}
+
+		return &transaction.Transaction{
+			Nonce:     big.NewInt(0).SetBytes(args[1]).Uint64(),
+			Value:     big.NewInt(0),
+			RcvAddr:   args[0],
+			SndAddr:   tx.RcvAddr,
+			Data:      args[2],
+			Signature: args[3],
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,177 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommonProcessor_ParseTxOperation(t *testing.T) {
+	t.Parallel()
+
+	cp := createCommonProcessor()
+	userAddress := []byte("user address")
+	scAddress := append(make([]byte, 10), []byte("sc address")...)
+	deployAddress := make([]byte, 32)
+
+	tests := []struct {
+		name     string
+		receiver []byte
+		txData   []byte
+		expected *txOperation
+	}{
+		{
+			name:     "move balance",
+			receiver: userAddress,
+			expected: &txOperation{operation: operationTransfer},
+		},
+		{
+			name:     "move balance with message",
+			receiver: userAddress,
+			txData:   []byte("hello@world"),
+			expected: &txOperation{operation: operationTransfer},
+		},
+		{
+			name:     "deploy",
+			receiver: deployAddress,
+			txData:   []byte("0061736d@0500@0502"),
+			expected: &txOperation{operation: operationSCDeploy},
+		},
+		{
+			name:     "relayed",
+			receiver: userAddress,
+			txData:   []byte(core.RelayedTransaction + "@7b7d"),
+			expected: &txOperation{operation: operationRelayed},
+		},
+		{
+			name:     "sc call",
+			receiver: scAddress,
+			txData:   []byte("claim@01"),
+			expected: &txOperation{operation: operationSCCall, function: "claim"},
+		},
+		{
+			name:     "built-in function",
+			receiver: userAddress,
+			txData:   createESDTCallData(core.BuiltInFunctionESDTNFTCreate, []byte("NFT-abcdef")),
+			expected: &txOperation{operation: operationSCCall, function: core.BuiltInFunctionESDTNFTCreate},
+		},
+		{
+			name:     "esdt transfer",
+			receiver: userAddress,
+			txData:   createESDTCallData(core.BuiltInFunctionESDTTransfer, []byte("TKN-abcdef"), big.NewInt(100).Bytes()),
+			expected: &txOperation{
+				operation:  core.BuiltInFunctionESDTTransfer,
+				tokens:     []string{"TKN-abcdef"},
+				esdtValues: []string{"100"},
+			},
+		},
+		{
+			name:     "esdt transfer and execute",
+			receiver: scAddress,
+			txData:   createESDTCallData(core.BuiltInFunctionESDTTransfer, []byte("TKN-abcdef"), big.NewInt(100).Bytes(), []byte("stake")),
+			expected: &txOperation{
+				operation:  core.BuiltInFunctionESDTTransfer,
+				function:   "stake",
+				tokens:     []string{"TKN-abcdef"},
+				esdtValues: []string{"100"},
+			},
+		},
+		{
+			name:     "nft transfer",
+			receiver: userAddress,
+			txData:   createESDTCallData(core.BuiltInFunctionESDTNFTTransfer, []byte("NFT-abcdef"), big.NewInt(10).Bytes(), big.NewInt(1).Bytes(), []byte("destination")),
+			expected: &txOperation{
+				operation:  core.BuiltInFunctionESDTNFTTransfer,
+				tokens:     []string{"NFT-abcdef-0a"},
+				esdtValues: []string{"1"},
+				receivers:  []string{hex.EncodeToString([]byte("destination"))},
+			},
+		},
+		{
+			name:     "multi transfer",
+			receiver: userAddress,
+			txData: createESDTCallData(core.BuiltInFunctionMultiESDTNFTTransfer, []byte("destination"), big.NewInt(2).Bytes(),
+				[]byte("TKN-abcdef"), big.NewInt(0).Bytes(), big.NewInt(100).Bytes(),
+				[]byte("SFT-abcdef"), big.NewInt(2).Bytes(), big.NewInt(5).Bytes(),
+				[]byte("buy")),
+			expected: &txOperation{
+				operation:  core.BuiltInFunctionMultiESDTNFTTransfer,
+				function:   "buy",
+				tokens:     []string{"TKN-abcdef", "SFT-abcdef-02"},
+				esdtValues: []string{"100", "5"},
+				receivers:  []string{hex.EncodeToString([]byte("destination"))},
+			},
+		},
+		{
+			name:     "multi transfer with missing arguments",
+			receiver: userAddress,
+			txData:   createESDTCallData(core.BuiltInFunctionMultiESDTNFTTransfer, []byte("destination"), big.NewInt(2).Bytes(), []byte("TKN-abcdef")),
+			expected: &txOperation{operation: operationSCCall, function: core.BuiltInFunctionMultiESDTNFTTransfer},
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, cp.parseTxOperation(tt.receiver, tt.txData), tt.name)
+	}
+}
+
+func TestCommonProcessor_BuildRelayedTransaction(t *testing.T) {
+	t.Parallel()
+
+	cp := createCommonProcessor()
+	innerTx := &transaction.Transaction{
+		Nonce:   5,
+		Value:   big.NewInt(0),
+		SndAddr: []byte("user"),
+		RcvAddr: []byte("sc address"),
+		Data:    createESDTCallData(core.BuiltInFunctionESDTTransfer, []byte("TKN-abcdef"), big.NewInt(7).Bytes(), []byte("deposit")),
+	}
+	serializedInnerTx, err := json.Marshal(innerTx)
+	require.Nil(t, err)
+
+	relayedTx := &transaction.Transaction{
+		Value:   big.NewInt(0),
+		SndAddr: []byte("relayer"),
+		RcvAddr: []byte("user"),
+		Data:    createESDTCallData(core.RelayedTransaction, serializedInnerTx),
+	}
+
+	dbTx := cp.buildTransaction(relayedTx, []byte("hash"), []byte("mb"), &block.MiniBlock{}, &block.Header{}, "success")
+	require.Equal(t, operationRelayed, dbTx.Operation)
+	require.Equal(t, hex.EncodeToString([]byte("user")), dbTx.InnerSender)
+	require.Equal(t, hex.EncodeToString([]byte("sc address")), dbTx.InnerReceiver)
+	require.Equal(t, "deposit", dbTx.InnerFunction)
+	require.Equal(t, "0", dbTx.InnerValue)
+	require.Equal(t, []string{"TKN-abcdef"}, dbTx.Tokens)
+	require.Equal(t, []string{"7"}, dbTx.ESDTValues)
+}
+
+func TestDecodeRelayedInnerTx(t *testing.T) {
+	t.Parallel()
+
+	relayedTxV2 := &transaction.Transaction{
+		SndAddr: []byte("relayer"),
+		RcvAddr: []byte("user"),
+		Data:    createESDTCallData(core.RelayedTransactionV2, []byte("sc address"), big.NewInt(3).Bytes(), []byte("claim"), []byte("signature")),
+	}
+	innerTx, ok := decodeRelayedInnerTx(relayedTxV2)
+	require.True(t, ok)
+	require.Equal(t, []byte("user"), innerTx.SndAddr)
+	require.Equal(t, []byte("sc address"), innerTx.RcvAddr)
+	require.Equal(t, uint64(3), innerTx.Nonce)
+	require.Equal(t, []byte("claim"), innerTx.Data)
+	require.Equal(t, big.NewInt(0), innerTx.Value)
+
+	relayedTxV2.Data = createESDTCallData(core.RelayedTransactionV2, []byte("sc address"))
+	_, ok = decodeRelayedInnerTx(relayedTxV2)
+	require.False(t, ok)
+
+	_, ok = decodeRelayedInnerTx(&transaction.Transaction{Data: []byte("relayedTx@blablabllablalba")})
+	require.False(t, ok)
+}
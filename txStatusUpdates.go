@@ -0,0 +1,140 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	vmcommon "github.com/ElrondNetwork/elrond-vm-common"
+)
+
+const (
+	signalErrorFunction = "signalError"
+	// gasRefundForRelayerMessage is the return message of the smart contract results that refund the relayer of a
+	// relayed transaction, which do not signal an error
+	gasRefundForRelayerMessage = "gas refund for relayer"
+
+	updateTxStatusScript = `if (ctx._source.scResults == null) { ctx._source.scResults = new ArrayList(); } ` +
+		`for (scr in params.scResults) { boolean found = false; ` +
+		`for (existing in ctx._source.scResults) { if (existing.hash == scr.hash) { found = true; break; } } ` +
+		`if (!found) { ctx._source.scResults.add(scr); } } ` +
+		`ctx._source.status = params.status; ctx._source.gasUsed = params.gasUsed; ctx._source.fee = params.fee; ` +
+		`ctx._source.feeNum = params.feeNum; ctx._source.feeScaled = params.feeScaled;`
+)
+
+// txStatusUpdate holds the changes of an already indexed transaction caused by smart contract results that were
+// executed in a later block
+type txStatusUpdate struct {
+	hash      string
+	status    string
+	gasUsed   uint64
+	fee       string
+	feeNum    float64
+	feeScaled *int64
+	scResults []data.ScResult
+}
+
+// groupScrsOfPreviousTxs converts the smart contract results that do not belong to a transaction of the current block
+// and groups them by the raw hash of their original transaction
+func (tdp *txDatabaseProcessor) groupScrsOfPreviousTxs(scrs map[string]*smartContractResult.SmartContractResult) map[string][]data.ScResult {
+	scrsOfPreviousTxs := make(map[string][]data.ScResult)
+	for scHash, scr := range scrs {
+		if len(scr.OriginalTxHash) == 0 {
+			continue
+		}
+
+		originalTxHash := string(scr.OriginalTxHash)
+		scrsOfPreviousTxs[originalTxHash] = append(scrsOfPreviousTxs[originalTxHash], tdp.convertScResultInDatabaseScr(scHash, scr))
+	}
+
+	return scrsOfPreviousTxs
+}
+
+// prepareTxStatusUpdate computes the status, the gas used and the fee of an indexed transaction after adding the
+// provided smart contract results. It returns false if none of the results was unknown for the transaction
+func (tdp *txDatabaseProcessor) prepareTxStatusUpdate(tx *data.Transaction, scrs []data.ScResult) (*txStatusUpdate, bool) {
+	existingScrs := make(map[string]struct{}, len(tx.SmartContractResults))
+	for _, scr := range tx.SmartContractResults {
+		existingScrs[scr.Hash] = struct{}{}
+	}
+
+	update := &txStatusUpdate{
+		hash:      tx.Hash,
+		scResults: make([]data.ScResult, 0, len(scrs)),
+	}
+	for _, scr := range scrs {
+		_, exists := existingScrs[scr.Hash]
+		if exists {
+			continue
+		}
+		existingScrs[scr.Hash] = struct{}{}
+		update.scResults = append(update.scResults, scr)
+
+		// the status and the gas fields of invalid transactions were already set
+		if tx.Status == transaction.TxStatusInvalid.String() {
+			continue
+		}
+
+		if isSCRForSenderWithRefund(scr, tx) {
+			refundValue := stringValueToBigInt(scr.Value)
+			gasUsed, fee := tdp.txFeeCalculator.ComputeGasUsedAndFeeBasedOnRefundValue(tx, refundValue)
+			tx.GasUsed = gasUsed
+			tx.Fee = fee.String()
+		}
+
+		if isScResultFailed(scr) {
+			tx.Status = transaction.TxStatusFail.String()
+			tx.GasUsed = tx.GasLimit
+			tx.Fee = tdp.txFeeCalculator.ComputeTxFeeBasedOnGasUsed(tx, tx.GasUsed).String()
+		}
+	}
+	if len(update.scResults) == 0 {
+		return nil, false
+	}
+
+	update.status = tx.Status
+	update.gasUsed = tx.GasUsed
+	update.fee = tx.Fee
+
+	return update, true
+}
+
+// isScResultFailed returns true if the provided smart contract result signals an error of the execution
+func isScResultFailed(scr data.ScResult) bool {
+	if strings.HasPrefix(string(scr.Data), signalErrorFunction) {
+		return true
+	}
+
+	userErrorReturnData := []byte(dataArgumentSeparator + hex.EncodeToString([]byte(vmcommon.UserError.String())))
+	if bytes.Contains(scr.Data, userErrorReturnData) {
+		return true
+	}
+
+	hasErrorMessage := scr.ReturnMessage != "" && scr.ReturnMessage != gasRefundForRelayerMessage
+
+	return hasErrorMessage && !isScResultSuccessful(scr.Data)
+}
+
+func putTxStatusUpdate(update *txStatusUpdate, buffSlice bulkBuffer) error {
+	script, err := json.Marshal(objectsMap{
+		"source": updateTxStatusScript,
+		"lang":   "painless",
+		"params": objectsMap{
+			"scResults": update.scResults,
+			"status":    update.status,
+			"gasUsed":   update.gasUsed,
+			"fee":       update.fee,
+			"feeNum":    update.feeNum,
+			"feeScaled": update.feeScaled,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpdateScript(update.hash, script)
+}
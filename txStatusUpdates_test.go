@@ -0,0 +1,149 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/smartContractResult"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsScResultFailed(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isScResultFailed(data.ScResult{Data: []byte("signalError@757365722065")}))
+	require.True(t, isScResultFailed(data.ScResult{Data: []byte("@" + hex.EncodeToString([]byte("user error")))}))
+	require.True(t, isScResultFailed(data.ScResult{ReturnMessage: "out of funds"}))
+	require.False(t, isScResultFailed(data.ScResult{ReturnMessage: gasRefundForRelayerMessage}))
+	require.False(t, isScResultFailed(data.ScResult{Data: []byte("@6f6b"), ReturnMessage: "ok"}))
+	require.False(t, isScResultFailed(data.ScResult{Data: []byte("@6f6b@01")}))
+}
+
+func TestTxDatabaseProcessor_GroupScrsOfPreviousTxs(t *testing.T) {
+	t.Parallel()
+
+	txDbProc := createTestTxDatabaseProcessor(&mock.ShardCoordinatorMock{})
+	pool := map[string]coreData.TransactionHandler{
+		"tx": &transaction.Transaction{
+			Value:   big.NewInt(0),
+			SndAddr: []byte("sender"),
+			RcvAddr: []byte("receiver"),
+		},
+		"scrOfCurrentTx": &smartContractResult.SmartContractResult{
+			Value:          big.NewInt(0),
+			OriginalTxHash: []byte("tx"),
+		},
+		"scrOfPreviousTx": &smartContractResult.SmartContractResult{
+			Value:          big.NewInt(0),
+			OriginalTxHash: []byte("previousTx"),
+			Data:           []byte("@" + hex.EncodeToString([]byte("user error"))),
+		},
+	}
+
+	body := &block.Body{
+		MiniBlocks: []*block.MiniBlock{
+			{
+				TxHashes: [][]byte{[]byte("tx")},
+				Type:     block.TxBlock,
+			},
+		},
+	}
+
+	results := txDbProc.prepareTransactionsForDatabase(body, &block.Header{}, pool, 0)
+	require.Len(t, results.scrsOfPreviousTxs, 1)
+	require.Len(t, results.scrsOfPreviousTxs["previousTx"], 1)
+	require.Equal(t, hex.EncodeToString([]byte("scrOfPreviousTx")), results.scrsOfPreviousTxs["previousTx"][0].Hash)
+}
+
+func TestTxDatabaseProcessor_PrepareTxStatusUpdate(t *testing.T) {
+	t.Parallel()
+
+	txDbProc := newTxDatabaseProcessor(
+		&mock.HasherMock{},
+		&mock.MarshalizerMock{},
+		mock.NewPubkeyConverterMock(32),
+		mock.NewPubkeyConverterMock(32),
+		&mock.EconomicsHandlerStub{
+			ComputeTxFeeBasedOnGasUsedCalled: func(tx coreData.TransactionWithFeeHandler, gasUsed uint64) *big.Int {
+				return big.NewInt(0).SetUint64(gasUsed * tx.GetGasPrice())
+			},
+		},
+		false,
+		&mock.ShardCoordinatorMock{},
+	)
+
+	tx := &data.Transaction{
+		Hash:                 "tx",
+		GasLimit:             100,
+		GasPrice:             2,
+		GasUsed:              50,
+		Fee:                  "100",
+		Status:               transaction.TxStatusSuccess.String(),
+		SmartContractResults: []data.ScResult{{Hash: "known"}},
+	}
+
+	_, ok := txDbProc.prepareTxStatusUpdate(tx, []data.ScResult{{Hash: "known"}})
+	require.False(t, ok)
+
+	update, ok := txDbProc.prepareTxStatusUpdate(tx, []data.ScResult{
+		{Hash: "known"},
+		{Hash: "error", ReturnMessage: "function not found"},
+	})
+	require.True(t, ok)
+	require.Equal(t, &txStatusUpdate{
+		hash:      "tx",
+		status:    transaction.TxStatusFail.String(),
+		gasUsed:   100,
+		fee:       "200",
+		scResults: []data.ScResult{{Hash: "error", ReturnMessage: "function not found"}},
+	}, update)
+}
+
+func TestElasticProcessor_UpdatePreviousTransactions(t *testing.T) {
+	t.Parallel()
+
+	previousTx := &data.Transaction{
+		GasLimit: 100,
+		Status:   transaction.TxStatusSuccess.String(),
+	}
+	serializedTx, _ := json.Marshal(previousTx)
+	source := make(map[string]interface{})
+	_ = json.Unmarshal(serializedTx, &source)
+
+	bulkRequests := make(map[string]string)
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, txIndex, index)
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": hex.EncodeToString([]byte("previousTx")), "found": true, "_source": source},
+				},
+			}, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	err = elasticProc.updatePreviousTransactions(map[string][]data.ScResult{
+		"previousTx": {{Hash: "scr", Data: []byte("@" + hex.EncodeToString([]byte("user error")))}},
+	})
+	require.Nil(t, err)
+	require.Contains(t, bulkRequests[txIndex], `{"update":{"_id":"`+hex.EncodeToString([]byte("previousTx"))+`"}}`)
+	require.Contains(t, bulkRequests[txIndex], `"status":"fail"`)
+	require.Contains(t, bulkRequests[txIndex], `"gasUsed":100`)
+	require.Contains(t, bulkRequests[txIndex], `"hash":"scr"`)
+}
@@ -0,0 +1,255 @@
+package indexer
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	"github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+)
+
+const (
+	stakeFunction   = "stake"
+	unStakeFunction = "unStake"
+	unJailFunction  = "unJail"
+	unBondFunction  = "unBond"
+	jailFunction    = "jail"
+
+	eligibleList = "eligible"
+	waitingList  = "waiting"
+	jailedList   = "jailed"
+	leavingList  = "leaving"
+	inactiveList = "inactive"
+
+	// validatorDocType is the type of the documents that hold the state of a validator, which share the validators
+	// index with the lists of the validators public keys of every shard and epoch
+	validatorDocType = "validator"
+
+	// setValidatorFieldsScript sets the fields of a validator. The list is changed only if the validator is in one of
+	// the lists from which the operation can move it, when such lists are provided
+	setValidatorFieldsScript = `for (entry in params.fields.entrySet()) { ` +
+		`if (entry.getKey() == 'list' && params.fromLists != null && !params.fromLists.contains(ctx._source.list)) { continue; } ` +
+		`ctx._source[entry.getKey()] = entry.getValue(); }`
+	// leaveEligibleListScript moves a validator that is not eligible anymore in the list set by its last call to the
+	// validator system smart contract, or in the waiting list if there is no such call. The epoch in which the
+	// validator left the eligible list is recorded, so it can be moved back if the epoch is reverted
+	leaveEligibleListScript = `ctx._source.list = ctx._source.operationList != null ? ctx._source.operationList : params.list; ` +
+		`ctx._source.leftEligibleInEpoch = params.epoch;`
+	// enterEligibleListScript marks a validator as eligible in an epoch. The state of the validator before the epoch is
+	// recorded once, so it can be restored if the epoch is reverted
+	enterEligibleListScript = `if (ctx._source.epoch != params.fields.epoch) { ` +
+		`ctx._source.previousState = ['list': ctx._source.list, 'shardId': ctx._source.shardId, 'epoch': ctx._source.epoch]; } ` +
+		`for (entry in params.fields.entrySet()) { ctx._source[entry.getKey()] = entry.getValue(); }`
+	// revertEpochValidatorsScript restores the validators changed by the start of an abandoned epoch. The validators
+	// made eligible before their previous state was recorded are left unchanged
+	revertEpochValidatorsScript = `if (ctx._source.epoch == params.epoch && ctx._source.previousState != null) { ` +
+		`for (entry in ctx._source.previousState.entrySet()) { ` +
+		`if (entry.getValue() == null) { ctx._source.remove(entry.getKey()); } else { ctx._source[entry.getKey()] = entry.getValue(); } } ` +
+		`ctx._source.remove('previousState'); } ` +
+		`if (ctx._source.leftEligibleInEpoch == params.epoch) { ctx._source.list = params.list; ctx._source.remove('leftEligibleInEpoch'); }`
+	// addTopUpScript records the hashes of the applied top-ups, so a block that is indexed again does not add them twice
+	addTopUpScript = `if (ctx._source.topUpTxHashes == null) { ctx._source.topUpTxHashes = new ArrayList(); } ` +
+		`BigInteger topUp = ctx._source.topUp == null ? BigInteger.ZERO : new BigInteger(ctx._source.topUp); ` +
+		`for (entry in params.topUps) { if (ctx._source.topUpTxHashes.contains(entry.txHash)) { continue; } ` +
+		`topUp = topUp.add(new BigInteger(entry.value)); ctx._source.topUpTxHashes.add(entry.txHash); } ` +
+		`ctx._source.topUp = topUp.toString();`
+)
+
+// validatorSCAddress is the address of the system smart contract that handles the staking of the validators
+var validatorSCAddress = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 255, 255}
+
+// validatorUpdate holds the fields that have to be set on the document of a validator, identified by its BLS key. The
+// from lists, if any, are the only lists from which the update can move the validator
+type validatorUpdate struct {
+	blsKey    string
+	fields    objectsMap
+	fromLists []string
+}
+
+// topUpOperation holds the value added to the stake of an owner by a transaction
+type topUpOperation struct {
+	txHash string
+	value  *big.Int
+}
+
+// validatorsOperations holds the changes made by the calls to the validator system smart contract of a block. The
+// top-ups are keyed by the owners addresses, as they are not bound to a BLS key
+type validatorsOperations struct {
+	updates []*validatorUpdate
+	topUps  map[string][]*topUpOperation
+}
+
+// getValidatorsOperations returns the changes made by the successful calls to the validator system smart contract in
+// the execution order of the provided block. These calls are executed only in metachain, so the other shards do not
+// return any change. The transactions map has to be keyed by the raw transaction hashes
+func (tdp *txDatabaseProcessor) getValidatorsOperations(
+	body *block.Body,
+	transactions map[string]*data.Transaction,
+	selfShardID uint32,
+) *validatorsOperations {
+	operations := &validatorsOperations{
+		updates: make([]*validatorUpdate, 0),
+		topUps:  make(map[string][]*topUpOperation),
+	}
+	if selfShardID != core.MetachainShardId || body == nil {
+		return operations
+	}
+
+	encodedValidatorSCAddress := tdp.addressPubkeyConverter.Encode(validatorSCAddress)
+	for _, mb := range body.MiniBlocks {
+		if mb.Type != block.TxBlock || mb.ReceiverShardID != core.MetachainShardId {
+			continue
+		}
+
+		for _, txHash := range mb.TxHashes {
+			tx, ok := transactions[string(txHash)]
+			if !ok || tx.Receiver != encodedValidatorSCAddress {
+				continue
+			}
+
+			tdp.parseValidatorsOperation(tx, operations)
+		}
+	}
+
+	return operations
+}
+
+func (tdp *txDatabaseProcessor) parseValidatorsOperation(tx *data.Transaction, operations *validatorsOperations) {
+	if tx.Status == transaction.TxStatusInvalid.String() || tx.Status == transaction.TxStatusFail.String() {
+		return
+	}
+
+	function, args, ok := decodeCallArguments(tx.Data)
+	if !ok {
+		return
+	}
+
+	switch function {
+	case stakeFunction:
+		tdp.addStakeOperation(tx, args, operations)
+	case unStakeFunction:
+		tdp.addListChange(args, leavingList, nil, operations)
+	case unJailFunction:
+		// only a jailed validator goes back to the waiting list
+		tdp.addListChange(args, waitingList, []string{jailedList}, operations)
+	case jailFunction:
+		tdp.addListChange(args, jailedList, nil, operations)
+	case unBondFunction:
+		for _, blsKey := range args {
+			operations.updates = append(operations.updates, &validatorUpdate{
+				blsKey: tdp.validatorPubkeyConverter.Encode(blsKey),
+				fields: objectsMap{"list": inactiveList, "operationList": inactiveList, "stake": "0"},
+			})
+		}
+	}
+}
+
+// addStakeOperation handles the stake@numNodes@blsKey1@signature1@... calls. The staked value is split equally
+// between the new nodes, while a call without nodes tops up the stake of the owner
+func (tdp *txDatabaseProcessor) addStakeOperation(tx *data.Transaction, args [][]byte, operations *validatorsOperations) {
+	value := stringValueToBigInt(tx.Value)
+
+	numNodes := uint64(0)
+	if len(args) > 0 {
+		numNodes = big.NewInt(0).SetBytes(args[0]).Uint64()
+	}
+	if numNodes == 0 {
+		operations.topUps[tx.Sender] = append(operations.topUps[tx.Sender], &topUpOperation{txHash: tx.Hash, value: value})
+		return
+	}
+
+	if uint64(len(args)) < 1+2*numNodes {
+		log.Debug("indexer: invalid stake arguments", "hash", tx.Hash, "num nodes", numNodes)
+		return
+	}
+
+	stakePerNode := big.NewInt(0).Div(value, big.NewInt(0).SetUint64(numNodes))
+	for i := uint64(0); i < numNodes; i++ {
+		blsKey := args[1+2*i]
+		operations.updates = append(operations.updates, &validatorUpdate{
+			blsKey: tdp.validatorPubkeyConverter.Encode(blsKey),
+			fields: objectsMap{"list": waitingList, "operationList": waitingList, "owner": tx.Sender, "stake": stakePerNode.String()},
+		})
+	}
+}
+
+func serializeTopUps(topUps []*topUpOperation) []objectsMap {
+	serializedTopUps := make([]objectsMap, 0, len(topUps))
+	for _, topUp := range topUps {
+		serializedTopUps = append(serializedTopUps, objectsMap{"txHash": topUp.txHash, "value": topUp.value.String()})
+	}
+
+	return serializedTopUps
+}
+
+// addListChange moves the validators in the provided list. The list is also recorded as the list set by the last
+// operation, so a validator that is still eligible joins it once it leaves the eligible list
+func (tdp *txDatabaseProcessor) addListChange(blsKeys [][]byte, list string, fromLists []string, operations *validatorsOperations) {
+	for _, blsKey := range blsKeys {
+		operations.updates = append(operations.updates, &validatorUpdate{
+			blsKey:    tdp.validatorPubkeyConverter.Encode(blsKey),
+			fields:    objectsMap{"list": list, "operationList": list},
+			fromLists: fromLists,
+		})
+	}
+}
+
+// putValidatorUpdate adds the bulk operation that sets the fields of the validator document, creating the document
+// if the validator is not indexed yet
+func putValidatorUpdate(update *validatorUpdate, buffSlice bulkBuffer) error {
+	params := objectsMap{"fields": update.fields}
+	if len(update.fromLists) > 0 {
+		params["fromLists"] = update.fromLists
+	}
+	script, err := json.Marshal(objectsMap{
+		"source": setValidatorFieldsScript,
+		"lang":   "painless",
+		"params": params,
+	})
+	if err != nil {
+		return err
+	}
+
+	doc := objectsMap{"blsKey": update.blsKey, "type": validatorDocType}
+	for field, value := range update.fields {
+		doc[field] = value
+	}
+	serializedDoc, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpsert(update.blsKey, script, serializedDoc)
+}
+
+// putEligibleValidatorUpdate adds the bulk operation that marks the validator as eligible in the shard and epoch,
+// creating the document if the validator is not indexed yet
+func putEligibleValidatorUpdate(blsKey string, shardID uint32, epoch uint32, buffSlice bulkBuffer) error {
+	fields := objectsMap{"list": eligibleList, "shardId": shardID, "epoch": epoch}
+	script, err := json.Marshal(objectsMap{
+		"source": enterEligibleListScript,
+		"lang":   "painless",
+		"params": objectsMap{"fields": fields},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the validator had no state before the epoch, so the fields are removed if the epoch is reverted
+	doc := objectsMap{
+		"blsKey":        blsKey,
+		"type":          validatorDocType,
+		"previousState": objectsMap{"list": nil, "shardId": nil, "epoch": nil},
+	}
+	for field, value := range fields {
+		doc[field] = value
+	}
+	serializedDoc, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return buffSlice.PutUpsert(blsKey, script, serializedDoc)
+}
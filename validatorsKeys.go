@@ -0,0 +1,316 @@
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+)
+
+const (
+	// maxCachedValidatorsLists is the number of shard and epoch validators lists kept in memory. The lists of the
+	// current epochs are the only ones needed while following the chain, so the cache is simply reset when full
+	maxCachedValidatorsLists = 100
+
+	// maxRevertibleRounds is the number of recent rounds kept in the validators statistics so they can be subtracted
+	// when a branch is abandoned
+	maxRevertibleRounds = 100
+
+	// addValidatorStatisticsScript adds the contributions of the rounds after the last counted one, so the rounds that
+	// are saved again are not counted twice
+	addValidatorStatisticsScript = `if (ctx._source.recentRounds == null) { ctx._source.recentRounds = new HashMap(); } ` +
+		`for (def contribution : params.rounds) { ` +
+		`if (ctx._source.lastRound != null && contribution.round <= ctx._source.lastRound) { continue; } ` +
+		`ctx._source.proposed += contribution.proposed; ` +
+		`ctx._source.missed += contribution.missed; ` +
+		`ctx._source.signed += contribution.signed; ` +
+		`ctx._source.recentRounds.put(String.valueOf(contribution.round), contribution); ` +
+		`ctx._source.lastRound = contribution.round; } ` +
+		`long minRound = ctx._source.lastRound - params.maxRounds; ` +
+		`ctx._source.recentRounds.keySet().removeIf(round -> Long.parseLong(round) <= minRound);`
+
+	// revertValidatorStatisticsScript subtracts the contributions of the rounds starting with the provided one
+	revertValidatorStatisticsScript = `if (ctx._source.recentRounds != null) { ` +
+		`for (def it = ctx._source.recentRounds.entrySet().iterator(); it.hasNext();) { ` +
+		`def entry = it.next(); ` +
+		`if (Long.parseLong(entry.getKey()) < params.round) { continue; } ` +
+		`ctx._source.proposed -= entry.getValue().proposed; ` +
+		`ctx._source.missed -= entry.getValue().missed; ` +
+		`ctx._source.signed -= entry.getValue().signed; ` +
+		`it.remove(); } } ` +
+		`ctx._source.lastRound = params.round - 1;`
+)
+
+// validatorsKeysHolder keeps the ordered lists of the validators public keys of every shard and epoch, which are
+// needed to resolve the signers indexes, the last epoch seen in the blocks of every shard and the timestamps of the
+// epoch start blocks
+type validatorsKeysHolder struct {
+	mutex                sync.RWMutex
+	lists                map[string][]string
+	epochs               map[uint32]uint32
+	epochStartTimestamps map[uint32]uint64
+}
+
+func newValidatorsKeysHolder() *validatorsKeysHolder {
+	return &validatorsKeysHolder{
+		lists:                make(map[string][]string),
+		epochs:               make(map[uint32]uint32),
+		epochStartTimestamps: make(map[uint32]uint64),
+	}
+}
+
+func (vkh *validatorsKeysHolder) addList(shardID uint32, epoch uint32, publicKeys []string) {
+	vkh.mutex.Lock()
+	defer vkh.mutex.Unlock()
+
+	if len(vkh.lists) >= maxCachedValidatorsLists {
+		vkh.lists = make(map[string][]string)
+	}
+	vkh.lists[computeValidatorsListID(shardID, epoch)] = publicKeys
+}
+
+func (vkh *validatorsKeysHolder) getList(shardID uint32, epoch uint32) ([]string, bool) {
+	vkh.mutex.RLock()
+	defer vkh.mutex.RUnlock()
+
+	publicKeys, ok := vkh.lists[computeValidatorsListID(shardID, epoch)]
+	return publicKeys, ok
+}
+
+// removeEpoch drops the cached validators lists of the provided epoch. The blocks of the abandoned epoch are reverted
+// as well, so the shards that reached it are moved back in the previous epoch
+func (vkh *validatorsKeysHolder) removeEpoch(epoch uint32) {
+	vkh.mutex.Lock()
+	defer vkh.mutex.Unlock()
+
+	suffix := fmt.Sprintf("_%d", epoch)
+	for id := range vkh.lists {
+		if strings.HasSuffix(id, suffix) {
+			delete(vkh.lists, id)
+		}
+	}
+	delete(vkh.epochStartTimestamps, epoch)
+
+	if epoch == 0 {
+		return
+	}
+	for shardID, shardEpoch := range vkh.epochs {
+		if shardEpoch >= epoch {
+			vkh.epochs[shardID] = epoch - 1
+		}
+	}
+}
+
+// setEpochStartTimestamp records the timestamp of the block that started the epoch. The first epoch start block
+// seen for an epoch is kept
+func (vkh *validatorsKeysHolder) setEpochStartTimestamp(epoch uint32, timestamp uint64) {
+	vkh.mutex.Lock()
+	defer vkh.mutex.Unlock()
+
+	if _, ok := vkh.epochStartTimestamps[epoch]; ok {
+		return
+	}
+	if len(vkh.epochStartTimestamps) >= maxCachedValidatorsLists {
+		vkh.epochStartTimestamps = make(map[uint32]uint64)
+	}
+	vkh.epochStartTimestamps[epoch] = timestamp
+}
+
+func (vkh *validatorsKeysHolder) getEpochStartTimestamp(epoch uint32) (uint64, bool) {
+	vkh.mutex.RLock()
+	defer vkh.mutex.RUnlock()
+
+	timestamp, ok := vkh.epochStartTimestamps[epoch]
+	return timestamp, ok
+}
+
+func (vkh *validatorsKeysHolder) setEpoch(shardID uint32, epoch uint32) {
+	vkh.mutex.Lock()
+	vkh.epochs[shardID] = epoch
+	vkh.mutex.Unlock()
+}
+
+func (vkh *validatorsKeysHolder) getEpoch(shardID uint32) (uint32, bool) {
+	vkh.mutex.RLock()
+	defer vkh.mutex.RUnlock()
+
+	epoch, ok := vkh.epochs[shardID]
+	return epoch, ok
+}
+
+// computeValidatorsListID returns the ID of the document that holds the validators public keys of a shard in an epoch
+func computeValidatorsListID(shardID uint32, epoch uint32) string {
+	return fmt.Sprintf("%d_%d", shardID, epoch)
+}
+
+// resolveSignersKeys returns the public keys found at the provided indexes of the validators list. Nothing is
+// returned if any of the indexes is outside the list, as the list does not belong to the signers epoch
+func resolveSignersKeys(publicKeys []string, signersIndexes []uint64) []string {
+	if len(publicKeys) == 0 || len(signersIndexes) == 0 {
+		return nil
+	}
+
+	signersKeys := make([]string, 0, len(signersIndexes))
+	for _, index := range signersIndexes {
+		if index >= uint64(len(publicKeys)) {
+			log.Debug("indexer: signer index outside the validators list", "index", index, "num validators", len(publicKeys))
+			return nil
+		}
+
+		signersKeys = append(signersKeys, publicKeys[index])
+	}
+
+	return signersKeys
+}
+
+// getValidatorsStatistics counts the blocks proposed, missed and signed by every validator in the provided rounds.
+// The first signer of a round is its leader, who either proposed or missed the block. The statistics are keyed by
+// the public key, the shard and the epoch and hold the contribution of every round
+func getValidatorsStatistics(infos []*data.RoundInfo) map[string]*data.ValidatorStatistics {
+	statistics := make(map[string]*data.ValidatorStatistics)
+	getContribution := func(info *data.RoundInfo, blsKey string) *data.RoundContribution {
+		id := fmt.Sprintf("%s_%d_%d", blsKey, info.ShardId, info.Epoch)
+		stats, ok := statistics[id]
+		if !ok {
+			stats = &data.ValidatorStatistics{
+				BlsKey:       blsKey,
+				ShardID:      info.ShardId,
+				Epoch:        info.Epoch,
+				RecentRounds: make(map[string]*data.RoundContribution),
+			}
+			statistics[id] = stats
+		}
+
+		roundKey := strconv.FormatUint(info.Index, 10)
+		contribution, ok := stats.RecentRounds[roundKey]
+		if !ok {
+			contribution = &data.RoundContribution{Round: info.Index}
+			stats.RecentRounds[roundKey] = contribution
+		}
+
+		return contribution
+	}
+
+	for _, info := range infos {
+		if len(info.SignersBlsKeys) == 0 {
+			continue
+		}
+
+		if !info.BlockWasProposed {
+			getContribution(info, info.SignersBlsKeys[0]).Missed++
+			continue
+		}
+
+		getContribution(info, info.SignersBlsKeys[0]).Proposed++
+		for _, blsKey := range info.SignersBlsKeys {
+			getContribution(info, blsKey).Signed++
+		}
+	}
+
+	for _, stats := range statistics {
+		sumRoundContributions(stats)
+	}
+
+	return statistics
+}
+
+// sumRoundContributions sets the counters and the last round of the statistics from the contributions of the rounds
+// and keeps only the contributions of the last maxRevertibleRounds rounds
+func sumRoundContributions(stats *data.ValidatorStatistics) {
+	for _, contribution := range stats.RecentRounds {
+		stats.Proposed += contribution.Proposed
+		stats.Missed += contribution.Missed
+		stats.Signed += contribution.Signed
+		if contribution.Round > stats.LastRound {
+			stats.LastRound = contribution.Round
+		}
+	}
+
+	for roundKey, contribution := range stats.RecentRounds {
+		if contribution.Round+maxRevertibleRounds <= stats.LastRound {
+			delete(stats.RecentRounds, roundKey)
+		}
+	}
+}
+
+// getSortedRoundContributions returns the contributions of the rounds in the order in which they have to be counted
+func getSortedRoundContributions(stats *data.ValidatorStatistics) []*data.RoundContribution {
+	contributions := make([]*data.RoundContribution, 0, len(stats.RecentRounds))
+	for _, contribution := range stats.RecentRounds {
+		contributions = append(contributions, contribution)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		return contributions[i].Round < contributions[j].Round
+	})
+
+	return contributions
+}
+
+func serializeValidatorsStatistics(statistics map[string]*data.ValidatorStatistics, buffSlice bulkBuffer) error {
+	for id, stats := range statistics {
+		script, err := json.Marshal(objectsMap{
+			"source": addValidatorStatisticsScript,
+			"lang":   "painless",
+			"params": objectsMap{
+				"rounds":    getSortedRoundContributions(stats),
+				"maxRounds": maxRevertibleRounds,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		serializedStats, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		err = buffSlice.PutUpsert(id, script, serializedStats)
+		if err != nil {
+			log.Warn("elastic search: serialize bulk validators statistics, write", "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDecodedValidatorsPublicKeys returns the validators public keys of the first document found by a multi get request
+func getDecodedValidatorsPublicKeys(response objectsMap) []string {
+	interfaceSlice, ok := response["docs"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, element := range interfaceSlice {
+		obj, ok := element.(objectsMap)
+		if !ok {
+			continue
+		}
+
+		found, _ := obj["found"].(bool)
+		if !found {
+			continue
+		}
+
+		serializedKeys, err := json.Marshal(obj["_source"])
+		if err != nil {
+			continue
+		}
+
+		validatorsKeys := &data.ValidatorsPublicKeys{}
+		err = json.Unmarshal(serializedKeys, validatorsKeys)
+		if err != nil {
+			log.Debug("indexer: cannot decode validators public keys", "id", obj["_id"], "error", err)
+			continue
+		}
+
+		return validatorsKeys.PublicKeys
+	}
+
+	return nil
+}
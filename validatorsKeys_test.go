@@ -0,0 +1,225 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/ElrondNetwork/elrond-go-core/data/indexer"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSignersKeys(t *testing.T) {
+	t.Parallel()
+
+	publicKeys := []string{"pk0", "pk1", "pk2"}
+	require.Equal(t, []string{"pk2", "pk0"}, resolveSignersKeys(publicKeys, []uint64{2, 0}))
+	require.Nil(t, resolveSignersKeys(publicKeys, []uint64{1, 3}))
+	require.Nil(t, resolveSignersKeys(nil, []uint64{0}))
+	require.Nil(t, resolveSignersKeys(publicKeys, nil))
+}
+
+func TestGetValidatorsStatistics(t *testing.T) {
+	t.Parallel()
+
+	statistics := getValidatorsStatistics([]*data.RoundInfo{
+		{Index: 1, ShardId: 1, Epoch: 2, BlockWasProposed: true, SignersBlsKeys: []string{"pk0", "pk1"}},
+		{Index: 2, ShardId: 1, Epoch: 2, BlockWasProposed: false, SignersBlsKeys: []string{"pk1", "pk0"}},
+		{Index: 3, ShardId: 1, Epoch: 2, BlockWasProposed: true, SignersBlsKeys: []string{"pk1", "pk0"}},
+		{Index: 4, ShardId: 1, Epoch: 2, BlockWasProposed: true},
+	})
+
+	require.Equal(t, map[string]*data.ValidatorStatistics{
+		"pk0_1_2": {BlsKey: "pk0", ShardID: 1, Epoch: 2, Proposed: 1, Missed: 0, Signed: 2, LastRound: 3,
+			RecentRounds: map[string]*data.RoundContribution{
+				"1": {Round: 1, Proposed: 1, Signed: 1},
+				"3": {Round: 3, Signed: 1},
+			}},
+		"pk1_1_2": {BlsKey: "pk1", ShardID: 1, Epoch: 2, Proposed: 1, Missed: 1, Signed: 2, LastRound: 3,
+			RecentRounds: map[string]*data.RoundContribution{
+				"1": {Round: 1, Signed: 1},
+				"2": {Round: 2, Missed: 1},
+				"3": {Round: 3, Proposed: 1, Signed: 1},
+			}},
+	}, statistics)
+}
+
+func TestGetValidatorsStatisticsKeepsOnlyTheRecentRounds(t *testing.T) {
+	t.Parallel()
+
+	statistics := getValidatorsStatistics([]*data.RoundInfo{
+		{Index: 1, ShardId: 1, Epoch: 2, BlockWasProposed: true, SignersBlsKeys: []string{"pk0"}},
+		{Index: 1 + maxRevertibleRounds, ShardId: 1, Epoch: 2, BlockWasProposed: true, SignersBlsKeys: []string{"pk0"}},
+	})
+
+	stats := statistics["pk0_1_2"]
+	require.Equal(t, uint64(2), stats.Proposed)
+	require.Equal(t, uint64(1+maxRevertibleRounds), stats.LastRound)
+	require.Len(t, stats.RecentRounds, 1)
+	require.Len(t, getSortedRoundContributions(stats), 1)
+}
+
+func TestElasticProcessor_RemoveRoundsInfoRevertsValidatorsStatistics(t *testing.T) {
+	t.Parallel()
+
+	updateQueries := make(map[string]objectsMap)
+	deleteQueries := make(map[string]objectsMap)
+	args := createMockElasticProcessorArgs()
+	delete(args.EnabledIndexes, roundIndex)
+	args.EnabledIndexes[validatorsStatisticsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoUpdateByQueryCalled: func(query map[string]interface{}, index string) error {
+			updateQueries[index] = query
+			return nil
+		},
+		DoDeleteByQueryCalled: func(query map[string]interface{}, index string) error {
+			deleteQueries[index] = query
+			return nil
+		},
+	}
+
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.RemoveRoundsInfo(&dataBlock.Header{ShardID: 1, Round: 7})
+	require.Nil(t, err)
+	require.Equal(t, map[string]objectsMap{validatorsStatisticsIndex: getValidatorsStatisticsFromRoundQuery(1, 7)}, updateQueries)
+	require.Empty(t, deleteQueries)
+}
+
+func TestElasticProcessor_SaveRoundsInfoResolvesSigners(t *testing.T) {
+	t.Parallel()
+
+	bulkRequests := make(map[string]string)
+	serializedBlock := ""
+	args := createMockElasticProcessorArgs()
+	args.EnabledIndexes[validatorsStatisticsIndex] = struct{}{}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			bulkRequests[index] = buff.String()
+			return nil
+		},
+		DoRequestCalled: func(req *esapi.IndexRequest) error {
+			blockBytes, _ := ioutil.ReadAll(req.Body)
+			serializedBlock = string(blockBytes)
+			return nil
+		},
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, validatorsIndex, index)
+			require.Equal(t, getDocumentsByIDsQuery([]string{"1_2"}, true), objectsMap(query))
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": "1_2", "found": true, "_source": map[string]interface{}{"publicKeys": []interface{}{"pk0", "pk1"}}},
+				},
+			}, nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	err = elasticProc.SaveHeader(&dataBlock.Header{ShardID: 1, Epoch: 2}, []uint64{1, 0}, &dataBlock.Body{}, nil, &indexer.Pool{}, 0)
+	require.Nil(t, err)
+	require.Contains(t, serializedBlock, `"proposerBlsKey":"pk1","validatorsBlsKeys":["pk1","pk0"]`)
+
+	roundInfo := &data.RoundInfo{Index: 5, ShardId: 1, BlockWasProposed: false, SignersIndexes: []uint64{0, 1}}
+	err = elasticProc.SaveRoundsInfo([]*data.RoundInfo{roundInfo})
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), roundInfo.Epoch)
+	require.Equal(t, []string{"pk0", "pk1"}, roundInfo.SignersBlsKeys)
+
+	stats := &data.ValidatorStatistics{}
+	statsLines := bytes.Split([]byte(bulkRequests[validatorsStatisticsIndex]), []byte("\n"))
+	require.Equal(t, `{"update":{"_id":"pk0_1_2"}}`, string(statsLines[0]))
+	upsert := statsLines[1][bytes.Index(statsLines[1], []byte(`"upsert" : `))+len(`"upsert" : `) : len(statsLines[1])-2]
+	err = json.Unmarshal(upsert, stats)
+	require.Nil(t, err)
+	require.Equal(t, &data.ValidatorStatistics{BlsKey: "pk0", ShardID: 1, Epoch: 2, Missed: 1, LastRound: 5,
+		RecentRounds: map[string]*data.RoundContribution{"5": {Round: 5, Missed: 1}}}, stats)
+	require.Contains(t, string(statsLines[1]), `"params":{"maxRounds":100,"rounds":[{"round":5,"proposed":0,"missed":1,"signed":0}]}`)
+}
+
+func TestElasticProcessor_SaveRoundsInfoAfterRestartShouldReadTheEpochFromTheLastBlock(t *testing.T) {
+	t.Parallel()
+
+	searches := 0
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoSearchCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, blockIndex, index)
+			require.Equal(t, getLastShardBlockQuery(1), objectsMap(query))
+			searches++
+			return map[string]interface{}{
+				"hits": map[string]interface{}{
+					"hits": []interface{}{
+						map[string]interface{}{"_id": "hash", "_source": map[string]interface{}{"shardId": 1, "epoch": 2, "nonce": 10}},
+					},
+				},
+			}, nil
+		},
+		DoMultiGetCalled: func(query map[string]interface{}, index string) (map[string]interface{}, error) {
+			require.Equal(t, getDocumentsByIDsQuery([]string{"1_2"}, true), objectsMap(query))
+			return map[string]interface{}{
+				"docs": []interface{}{
+					map[string]interface{}{"_id": "1_2", "found": true, "_source": map[string]interface{}{"publicKeys": []interface{}{"pk0", "pk1"}}},
+				},
+			}, nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	roundInfo := &data.RoundInfo{Index: 5, ShardId: 1, BlockWasProposed: true, SignersIndexes: []uint64{1}}
+	err = elasticProc.SaveRoundsInfo([]*data.RoundInfo{roundInfo})
+	require.Nil(t, err)
+	require.Equal(t, uint32(2), roundInfo.Epoch)
+	require.Equal(t, []string{"pk1"}, roundInfo.SignersBlsKeys)
+
+	err = elasticProc.SaveRoundsInfo([]*data.RoundInfo{{Index: 6, ShardId: 1, BlockWasProposed: true}})
+	require.Nil(t, err)
+	require.Equal(t, 1, searches)
+}
+
+func TestElasticProcessor_SaveRoundsInfoShouldErrWhenTheLastBlockCanNotBeRead(t *testing.T) {
+	t.Parallel()
+
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoSearchCalled: func(_ map[string]interface{}, _ string) (map[string]interface{}, error) {
+			return nil, errors.New("search error")
+		},
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string, _ string) error {
+			require.Fail(t, "the rounds should not be saved without their signers")
+			return nil
+		},
+	}
+	elasticProc, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+
+	err = elasticProc.SaveRoundsInfo([]*data.RoundInfo{{Index: 5, ShardId: 1, BlockWasProposed: true}})
+	require.EqualError(t, err, "search error")
+}
+
+func TestValidatorsKeysHolder_EpochStartTimestamp(t *testing.T) {
+	t.Parallel()
+
+	holder := newValidatorsKeysHolder()
+	_, ok := holder.getEpochStartTimestamp(3)
+	require.False(t, ok)
+
+	holder.setEpochStartTimestamp(3, 100)
+	holder.setEpochStartTimestamp(3, 200)
+	timestamp, ok := holder.getEpochStartTimestamp(3)
+	require.True(t, ok)
+	require.Equal(t, uint64(100), timestamp)
+
+	holder.removeEpoch(3)
+	_, ok = holder.getEpochStartTimestamp(3)
+	require.False(t, ok)
+}
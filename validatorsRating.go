@@ -0,0 +1,82 @@
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+)
+
+const ratingIndexIDSeparator = "_"
+
+// parseRatingIndexID extracts the shard ID and the epoch from a validators rating index ID with the shard_epoch format
+func parseRatingIndexID(indexID string) (uint32, uint32, error) {
+	parts := strings.Split(indexID, ratingIndexIDSeparator)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidRatingIndexID, indexID)
+	}
+
+	shardID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidRatingIndexID, indexID)
+	}
+	epoch, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidRatingIndexID, indexID)
+	}
+
+	return uint32(shardID), uint32(epoch), nil
+}
+
+// serializeValidatorsRating will serialize a document for every validator, identified by the public key, the shard
+// and the epoch
+func serializeValidatorsRating(ratings []*data.ValidatorRatingInfo, buffSlice bulkBuffer) error {
+	for _, rating := range ratings {
+		err := putValidatorRating(computeValidatorRatingID(rating), rating, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serializeValidatorsRatingHistory will serialize the ratings timestamped with the moment of the save, which is
+// included in the documents IDs as well, so every save of the ratings adds a new entry
+func serializeValidatorsRatingHistory(ratings []*data.ValidatorRatingInfo, saveTimestamp time.Duration, buffSlice bulkBuffer) error {
+	for _, rating := range ratings {
+		entry := *rating
+		entry.Timestamp = saveTimestamp
+
+		id := fmt.Sprintf("%s_%d", computeValidatorRatingID(rating), saveTimestamp)
+		err := putValidatorRating(id, &entry, buffSlice)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func computeValidatorRatingID(rating *data.ValidatorRatingInfo) string {
+	return fmt.Sprintf("%s_%d_%d", rating.PublicKey, rating.ShardID, rating.Epoch)
+}
+
+func putValidatorRating(id string, rating *data.ValidatorRatingInfo, buffSlice bulkBuffer) error {
+	serializedData, err := json.Marshal(rating)
+	if err != nil {
+		log.Warn("cannot prepare serializes validator rating", "public key", rating.PublicKey, "error", err)
+		return err
+	}
+
+	err = buffSlice.PutIndex(id, serializedData)
+	if err != nil {
+		log.Warn("elastic search: serialize bulk validators rating, write", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
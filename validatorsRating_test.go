@@ -0,0 +1,53 @@
+package indexer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRatingIndexID(t *testing.T) {
+	t.Parallel()
+
+	shardID, epoch, err := parseRatingIndexID("4294967295_10")
+	require.Nil(t, err)
+	require.Equal(t, uint32(4294967295), shardID)
+	require.Equal(t, uint32(10), epoch)
+
+	for _, indexID := range []string{"", "0", "0_1_2", "a_1", "0_b", "-1_1"} {
+		_, _, err = parseRatingIndexID(indexID)
+		require.True(t, errors.Is(err, ErrInvalidRatingIndexID), indexID)
+	}
+}
+
+func TestSerializeValidatorsRating(t *testing.T) {
+	t.Parallel()
+
+	ratings := []*data.ValidatorRatingInfo{
+		{PublicKey: "pk1", ShardID: 1, Epoch: 2, Rating: 50.5, Timestamp: 1000},
+		{PublicKey: "pk2", ShardID: 1, Epoch: 2, Rating: 100, Timestamp: 1000},
+	}
+
+	buffSlice := data.NewBufferSlice()
+	err := serializeValidatorsRating(ratings, buffSlice)
+	require.Nil(t, err)
+	require.Len(t, buffSlice.Buffers(), 1)
+	expected := `{"index":{"_id":"pk1_1_2"}}` + "\n" +
+		`{"publicKey":"pk1","shardId":1,"epoch":2,"rating":50.5,"timestamp":1000}` + "\n" +
+		`{"index":{"_id":"pk2_1_2"}}` + "\n" +
+		`{"publicKey":"pk2","shardId":1,"epoch":2,"rating":100,"timestamp":1000}` + "\n"
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+
+	buffSlice = data.NewBufferSlice()
+	err = serializeValidatorsRatingHistory(ratings, 1500, buffSlice)
+	require.Nil(t, err)
+	expected = `{"index":{"_id":"pk1_1_2_1500"}}` + "\n" +
+		`{"publicKey":"pk1","shardId":1,"epoch":2,"rating":50.5,"timestamp":1500}` + "\n" +
+		`{"index":{"_id":"pk2_1_2_1500"}}` + "\n" +
+		`{"publicKey":"pk2","shardId":1,"epoch":2,"rating":100,"timestamp":1500}` + "\n"
+	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+	require.Equal(t, time.Duration(1000), ratings[0].Timestamp)
+}
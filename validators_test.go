@@ -0,0 +1,153 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elrond-go-core/core"
+	coreData "github.com/ElrondNetwork/elrond-go-core/data"
+	"github.com/ElrondNetwork/elrond-go-core/data/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidatorsTxPool() map[string]coreData.TransactionHandler {
+	owner := []byte("owner")
+	return map[string]coreData.TransactionHandler{
+		"stake": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(5000),
+			Data:    createESDTCallData(stakeFunction, big.NewInt(2).Bytes(), []byte("key1"), []byte("sig1"), []byte("key2"), []byte("sig2")),
+		},
+		"topUp": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(100),
+			Data:    []byte(stakeFunction),
+		},
+		"unStake": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(unStakeFunction, []byte("key1")),
+		},
+		"unBond": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(unBondFunction, []byte("key1")),
+		},
+		"unJail": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(0),
+			Data:    createESDTCallData(unJailFunction, []byte("key2")),
+		},
+		"invalidStake": &transaction.Transaction{
+			SndAddr: owner,
+			RcvAddr: validatorSCAddress,
+			Value:   big.NewInt(2500),
+			Data:    createESDTCallData(stakeFunction, big.NewInt(2).Bytes(), []byte("key3")),
+		},
+	}
+}
+
+func TestTxDatabaseProcessor_GetValidatorsOperations(t *testing.T) {
+	t.Parallel()
+
+	body := createSystemSCCallsBody("stake", "topUp", "unStake", "unBond", "unJail", "invalidStake")
+	results := prepareSystemSCCalls(body, createValidatorsTxPool(), core.MetachainShardId)
+	operations := results.validatorsOperations
+
+	encodedOwner := hex.EncodeToString([]byte("owner"))
+	encodedKey1 := hex.EncodeToString([]byte("key1"))
+	encodedKey2 := hex.EncodeToString([]byte("key2"))
+	require.Equal(t, []*validatorUpdate{
+		{blsKey: encodedKey1, fields: objectsMap{"list": waitingList, "operationList": waitingList, "owner": encodedOwner, "stake": "2500"}},
+		{blsKey: encodedKey2, fields: objectsMap{"list": waitingList, "operationList": waitingList, "owner": encodedOwner, "stake": "2500"}},
+		{blsKey: encodedKey1, fields: objectsMap{"list": leavingList, "operationList": leavingList}},
+		{blsKey: encodedKey1, fields: objectsMap{"list": inactiveList, "operationList": inactiveList, "stake": "0"}},
+		{blsKey: encodedKey2, fields: objectsMap{"list": waitingList, "operationList": waitingList}, fromLists: []string{jailedList}},
+	}, operations.updates)
+	require.Equal(t, map[string][]*topUpOperation{encodedOwner: {{txHash: hex.EncodeToString([]byte("topUp")), value: big.NewInt(100)}}}, operations.topUps)
+
+	results = prepareSystemSCCalls(body, createValidatorsTxPool(), 0)
+	require.Len(t, results.validatorsOperations.updates, 0)
+	require.Len(t, results.validatorsOperations.topUps, 0)
+}
+
+func TestElasticProcessor_IndexValidatorsOperations(t *testing.T) {
+	t.Parallel()
+
+	bulkRequest := ""
+	updateByQueryIndexes := make([]string, 0)
+	topUps := []*topUpOperation{{txHash: "topUpHash", value: big.NewInt(100)}}
+	args := createMockElasticProcessorArgs()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string, _ string) error {
+			require.Equal(t, validatorsIndex, index)
+			bulkRequest = buff.String()
+			return nil
+		},
+		DoUpdateByQueryCalled: func(query map[string]interface{}, index string) error {
+			updateByQueryIndexes = append(updateByQueryIndexes, index)
+			require.Equal(t, getValidatorsTopUpQuery("owner", topUps), objectsMap(query))
+			return nil
+		},
+	}
+	epInt, err := NewElasticProcessor(args)
+	require.Nil(t, err)
+	elasticProc := epInt.(*elasticProcessor)
+
+	err = elasticProc.indexValidatorsOperations(&validatorsOperations{
+		updates: []*validatorUpdate{
+			{blsKey: "key1", fields: objectsMap{"list": leavingList}},
+			{blsKey: "key2", fields: objectsMap{"list": waitingList}, fromLists: []string{jailedList}},
+		},
+		topUps: map[string][]*topUpOperation{"owner": topUps},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{validatorsIndex}, updateByQueryIndexes)
+	serializedScript := strings.Replace(setValidatorFieldsScript, "&", `\u0026`, -1)
+	expected := `{"update":{"_id":"key1"}}` + "\n" +
+		`{ "script" : {"lang":"painless","params":{"fields":{"list":"leaving"}},"source":"` + serializedScript + `"}, ` +
+		`"upsert" : {"blsKey":"key1","list":"leaving","type":"validator"} }` + "\n" +
+		`{"update":{"_id":"key2"}}` + "\n" +
+		`{ "script" : {"lang":"painless","params":{"fields":{"list":"waiting"},"fromLists":["jailed"]},"source":"` + serializedScript + `"}, ` +
+		`"upsert" : {"blsKey":"key2","list":"waiting","type":"validator"} }` + "\n"
+	require.Equal(t, expected, bulkRequest)
+}
+
+func TestGetValidatorsTopUpQuery(t *testing.T) {
+	t.Parallel()
+
+	query := getValidatorsTopUpQuery("owner", []*topUpOperation{
+		{txHash: "hash1", value: big.NewInt(100)},
+		{txHash: "hash2", value: big.NewInt(5)},
+	})
+
+	script := query["script"].(objectsMap)
+	require.Equal(t, addTopUpScript, script["source"])
+	require.Equal(t, objectsMap{"topUps": []objectsMap{
+		{"txHash": "hash1", "value": "100"},
+		{"txHash": "hash2", "value": "5"},
+	}}, script["params"])
+}
+
+func TestValidatorsQueriesShouldMatchOnlyTheValidatorsDocuments(t *testing.T) {
+	t.Parallel()
+
+	queries := []objectsMap{
+		getValidatorsTopUpQuery("owner", []*topUpOperation{{txHash: "hash", value: big.NewInt(1)}}),
+		getPreviousEligibleValidatorsQuery(1, 2),
+		getRevertEpochValidatorsQuery(2),
+	}
+	for _, query := range queries {
+		filter := query["query"].(objectsMap)["bool"].(objectsMap)["filter"].([]objectsMap)
+		require.Contains(t, filter, objectsMap{"term": objectsMap{"type": validatorDocType}})
+	}
+}
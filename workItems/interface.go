@@ -14,9 +14,19 @@ type WorkItemHandler interface {
 }
 
 type saveBlockIndexer interface {
-	SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, txsSize int) error
+	SaveHeader(header coreData.HeaderHandler, signersIndexes []uint64, body *block.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error
+	UpdateNotarizedTransactions(header coreData.HeaderHandler, notarizedHeadersHashes []string) error
 	SaveMiniblocks(header coreData.HeaderHandler, body *block.Body) (map[string]bool, error)
 	SaveTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool, mbsInDb map[string]bool) error
+	FlushBatch(force bool) error
+}
+
+type flushBatchIndexer interface {
+	FlushBatch(force bool) error
+}
+
+type flushNotifierIndexer interface {
+	NotifyWhenFlushed(handler func())
 }
 
 type saveRatingIndexer interface {
@@ -26,6 +36,16 @@ type saveRatingIndexer interface {
 type removeIndexer interface {
 	RemoveHeader(header coreData.HeaderHandler) error
 	RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
+	RevertTokens(header coreData.HeaderHandler, body *block.Body) error
+	RevertDelegations(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccounts(header coreData.HeaderHandler, body *block.Body) error
+	RevertAccountsHistory(header coreData.HeaderHandler) error
+	RevertAddressActivity(header coreData.HeaderHandler) error
+}
+
+type removeRoundsIndexer interface {
+	RemoveRoundsInfo(header coreData.HeaderHandler) error
+	RemoveEpochData(header coreData.HeaderHandler) error
 }
 
 type saveRounds interface {
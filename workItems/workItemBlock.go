@@ -54,14 +54,20 @@ func (wib *itemBlock) Save() error {
 	}
 
 	txsSizeInBytes := ComputeSizeOfTxs(wib.marshalizer, wib.argsSaveBlock.TransactionsPool)
-	err := wib.indexer.SaveHeader(wib.argsSaveBlock.Header, wib.argsSaveBlock.SignersIndexes, body, wib.argsSaveBlock.NotarizedHeadersHashes, txsSizeInBytes)
+	err := wib.indexer.SaveHeader(wib.argsSaveBlock.Header, wib.argsSaveBlock.SignersIndexes, body, wib.argsSaveBlock.NotarizedHeadersHashes, wib.argsSaveBlock.TransactionsPool, txsSizeInBytes)
 	if err != nil {
 		return fmt.Errorf("%w when saving header block, hash %s, nonce %d",
 			err, wib.argsSaveBlock.HeaderHash, wib.argsSaveBlock.Header.GetNonce())
 	}
 
+	err = wib.indexer.UpdateNotarizedTransactions(wib.argsSaveBlock.Header, wib.argsSaveBlock.NotarizedHeadersHashes)
+	if err != nil {
+		return fmt.Errorf("%w when updating notarized transactions, block hash %s, nonce %d",
+			err, wib.argsSaveBlock.HeaderHash, wib.argsSaveBlock.Header.GetNonce())
+	}
+
 	if len(body.MiniBlocks) == 0 {
-		return nil
+		return wib.flushBatch()
 	}
 
 	mbsInDb, err := wib.indexer.SaveMiniblocks(wib.argsSaveBlock.Header, body)
@@ -76,6 +82,16 @@ func (wib *itemBlock) Save() error {
 			err, wib.argsSaveBlock.HeaderHash, wib.argsSaveBlock.Header.GetNonce())
 	}
 
+	return wib.flushBatch()
+}
+
+func (wib *itemBlock) flushBatch() error {
+	err := wib.indexer.FlushBatch(false)
+	if err != nil {
+		return fmt.Errorf("%w when flushing the blocks batch, block hash %s, nonce %d",
+			err, wib.argsSaveBlock.HeaderHash, wib.argsSaveBlock.Header.GetNonce())
+	}
+
 	return nil
 }
 
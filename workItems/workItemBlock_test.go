@@ -54,7 +54,7 @@ func TestItemBlock_SaveHeaderShouldErr(t *testing.T) {
 	localErr := errors.New("local err")
 	itemBlock := workItems.NewItemBlock(
 		&mock.ElasticProcessorStub{
-			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, txsSize int) error {
+			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error {
 				return localErr
 			},
 		},
@@ -75,7 +75,7 @@ func TestItemBlock_SaveNoMiniblocksShouldCallSaveHeader(t *testing.T) {
 	countCalled := 0
 	itemBlock := workItems.NewItemBlock(
 		&mock.ElasticProcessorStub{
-			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, txsSize int) error {
+			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error {
 				countCalled++
 				return nil
 			},
@@ -123,6 +123,27 @@ func TestItemBlock_SaveMiniblocksShouldErr(t *testing.T) {
 	require.True(t, errors.Is(err, localErr))
 }
 
+func TestItemBlock_UpdateNotarizedTransactionsShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			UpdateNotarizedTransactionsCalled: func(header data.HeaderHandler, notarizedHeadersHashes []string) error {
+				return localErr
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			Header:                 &dataBlock.MetaBlock{},
+			Body:                   &dataBlock.Body{},
+			NotarizedHeadersHashes: []string{"hash"},
+		},
+	)
+	require.False(t, itemBlock.IsInterfaceNil())
+
+	err := itemBlock.Save()
+	require.True(t, errors.Is(err, localErr))
+}
+
 func TestItemBlock_SaveTransactionsShouldErr(t *testing.T) {
 	localErr := errors.New("local err")
 	itemBlock := workItems.NewItemBlock(
@@ -148,7 +169,7 @@ func TestItemBlock_SaveShouldWork(t *testing.T) {
 	countCalled := 0
 	itemBlock := workItems.NewItemBlock(
 		&mock.ElasticProcessorStub{
-			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, txsSize int) error {
+			SaveHeaderCalled: func(header data.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, pool *indexer.Pool, txsSize int) error {
 				countCalled++
 				return nil
 			},
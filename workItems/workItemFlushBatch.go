@@ -0,0 +1,28 @@
+package workItems
+
+type itemFlushBatch struct {
+	indexer flushBatchIndexer
+}
+
+// NewItemFlushBatch will create a new instance of itemFlushBatch
+func NewItemFlushBatch(indexer flushBatchIndexer) WorkItemHandler {
+	return &itemFlushBatch{
+		indexer: indexer,
+	}
+}
+
+// Save will send the accumulated blocks batch to the elasticsearch database, if the batch reached one of its limits
+func (wifb *itemFlushBatch) Save() error {
+	err := wifb.indexer.FlushBatch(false)
+	if err != nil {
+		log.Warn("itemFlushBatch.Save", "could not flush blocks batch", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wifb *itemFlushBatch) IsInterfaceNil() bool {
+	return wifb == nil
+}
@@ -0,0 +1,42 @@
+package workItems_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elastic-indexer-go/workItems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemFlushBatch_Save(t *testing.T) {
+	called := false
+	itemFlush := workItems.NewItemFlushBatch(
+		&mock.ElasticProcessorStub{
+			FlushBatchCalled: func(force bool) error {
+				called = true
+				require.False(t, force)
+				return nil
+			},
+		},
+	)
+	require.False(t, itemFlush.IsInterfaceNil())
+
+	err := itemFlush.Save()
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestItemFlushBatch_SaveShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemFlush := workItems.NewItemFlushBatch(
+		&mock.ElasticProcessorStub{
+			FlushBatchCalled: func(force bool) error {
+				return localErr
+			},
+		},
+	)
+
+	err := itemFlush.Save()
+	require.Equal(t, localErr, err)
+}
@@ -29,7 +29,8 @@ func (wirb *itemRemoveBlock) IsInterfaceNil() bool {
 	return wirb == nil
 }
 
-// Save will remove a block and miniblocks from elasticsearch database
+// Save will remove a block and miniblocks from elasticsearch database and will revert the changes of the tokens registry,
+// of the delegations, of the accounts, of the accounts balances history and of the address activity
 func (wirb *itemRemoveBlock) Save() error {
 	err := wirb.indexer.RemoveHeader(wirb.headerHandler)
 	if err != nil {
@@ -43,6 +44,36 @@ func (wirb *itemRemoveBlock) Save() error {
 		return ErrBodyTypeAssertion
 	}
 
+	err = wirb.indexer.RevertTokens(wirb.headerHandler, body)
+	if err != nil {
+		log.Warn("itemRemoveBlock.Save could not revert tokens", "error", err.Error())
+		return err
+	}
+
+	err = wirb.indexer.RevertDelegations(wirb.headerHandler, body)
+	if err != nil {
+		log.Warn("itemRemoveBlock.Save could not revert delegations", "error", err.Error())
+		return err
+	}
+
+	err = wirb.indexer.RevertAccounts(wirb.headerHandler, body)
+	if err != nil {
+		log.Warn("itemRemoveBlock.Save could not revert accounts", "error", err.Error())
+		return err
+	}
+
+	err = wirb.indexer.RevertAccountsHistory(wirb.headerHandler)
+	if err != nil {
+		log.Warn("itemRemoveBlock.Save could not revert accounts history", "error", err.Error())
+		return err
+	}
+
+	err = wirb.indexer.RevertAddressActivity(wirb.headerHandler)
+	if err != nil {
+		log.Warn("itemRemoveBlock.Save could not revert address activity", "error", err.Error())
+		return err
+	}
+
 	err = wirb.indexer.RemoveMiniblocks(wirb.headerHandler, body)
 	if err != nil {
 		log.Warn("itemRemoveBlock.Save could not remove miniblocks", "error", err.Error())
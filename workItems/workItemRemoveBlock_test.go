@@ -67,3 +67,136 @@ func TestItemRemoveBlock_SaveRemoveMiniblocksShouldErr(t *testing.T) {
 	err := itemRemove.Save()
 	require.Equal(t, localErr, err)
 }
+
+func TestItemRemoveBlock_SaveRevertTokensShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	removeMiniblocksCalled := false
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertTokensCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				return localErr
+			},
+			RemoveMiniblocksCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				removeMiniblocksCalled = true
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.MetaBlock{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.False(t, removeMiniblocksCalled)
+}
+
+func TestItemRemoveBlock_SaveRevertDelegationsShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	revertTokensCalled := false
+	removeMiniblocksCalled := false
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertTokensCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				revertTokensCalled = true
+				return nil
+			},
+			RevertDelegationsCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				return localErr
+			},
+			RemoveMiniblocksCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				removeMiniblocksCalled = true
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.MetaBlock{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.True(t, revertTokensCalled)
+	require.False(t, removeMiniblocksCalled)
+}
+
+func TestItemRemoveBlock_SaveRevertAccountsHistoryShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	revertDelegationsCalled := false
+	removeMiniblocksCalled := false
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertDelegationsCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				revertDelegationsCalled = true
+				return nil
+			},
+			RevertAccountsHistoryCalled: func(header data.HeaderHandler) error {
+				return localErr
+			},
+			RemoveMiniblocksCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				removeMiniblocksCalled = true
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.Header{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.True(t, revertDelegationsCalled)
+	require.False(t, removeMiniblocksCalled)
+}
+
+func TestItemRemoveBlock_SaveRevertAccountsShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	revertAccountsHistoryCalled := false
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertAccountsCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				return localErr
+			},
+			RevertAccountsHistoryCalled: func(header data.HeaderHandler) error {
+				revertAccountsHistoryCalled = true
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.Header{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.False(t, revertAccountsHistoryCalled)
+}
+
+func TestItemRemoveBlock_SaveRevertAddressActivityShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	revertAccountsHistoryCalled := false
+	removeMiniblocksCalled := false
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertAccountsHistoryCalled: func(header data.HeaderHandler) error {
+				revertAccountsHistoryCalled = true
+				return nil
+			},
+			RevertAddressActivityCalled: func(header data.HeaderHandler) error {
+				return localErr
+			},
+			RemoveMiniblocksCalled: func(header data.HeaderHandler, body *dataBlock.Body) error {
+				removeMiniblocksCalled = true
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.Header{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.True(t, revertAccountsHistoryCalled)
+	require.False(t, removeMiniblocksCalled)
+}
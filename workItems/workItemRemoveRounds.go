@@ -0,0 +1,44 @@
+package workItems
+
+import (
+	"github.com/ElrondNetwork/elrond-go-core/data"
+)
+
+type itemRemoveRounds struct {
+	indexer       removeRoundsIndexer
+	headerHandler data.HeaderHandler
+}
+
+// NewItemRemoveRounds will create a new instance of itemRemoveRounds
+func NewItemRemoveRounds(
+	indexer removeRoundsIndexer,
+	headerHandler data.HeaderHandler,
+) WorkItemHandler {
+	return &itemRemoveRounds{
+		indexer:       indexer,
+		headerHandler: headerHandler,
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wirr *itemRemoveRounds) IsInterfaceNil() bool {
+	return wirr == nil
+}
+
+// Save will remove from elasticsearch database the rounds of the abandoned branch and, when the reverted block started
+// an epoch, the documents of that epoch
+func (wirr *itemRemoveRounds) Save() error {
+	err := wirr.indexer.RemoveRoundsInfo(wirr.headerHandler)
+	if err != nil {
+		log.Warn("itemRemoveRounds.Save could not remove rounds", "error", err.Error())
+		return err
+	}
+
+	err = wirr.indexer.RemoveEpochData(wirr.headerHandler)
+	if err != nil {
+		log.Warn("itemRemoveRounds.Save could not remove epoch data", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package workItems_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elastic-indexer-go/workItems"
+	"github.com/ElrondNetwork/elrond-go-core/data"
+	dataBlock "github.com/ElrondNetwork/elrond-go-core/data/block"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemRemoveRounds_Save(t *testing.T) {
+	removeRoundsCalled := false
+	removeEpochCalled := false
+	itemRemove := workItems.NewItemRemoveRounds(
+		&mock.ElasticProcessorStub{
+			RemoveRoundsInfoCalled: func(header data.HeaderHandler) error {
+				removeRoundsCalled = true
+				return nil
+			},
+			RemoveEpochDataCalled: func(header data.HeaderHandler) error {
+				removeEpochCalled = true
+				return nil
+			},
+		},
+		&dataBlock.MetaBlock{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.NoError(t, err)
+	require.True(t, removeRoundsCalled)
+	require.True(t, removeEpochCalled)
+}
+
+func TestItemRemoveRounds_SaveRemoveRoundsShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	removeEpochCalled := false
+	itemRemove := workItems.NewItemRemoveRounds(
+		&mock.ElasticProcessorStub{
+			RemoveRoundsInfoCalled: func(header data.HeaderHandler) error {
+				return localErr
+			},
+			RemoveEpochDataCalled: func(header data.HeaderHandler) error {
+				removeEpochCalled = true
+				return nil
+			},
+		},
+		&dataBlock.MetaBlock{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+	require.False(t, removeEpochCalled)
+}
+
+func TestItemRemoveRounds_SaveRemoveEpochDataShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemRemove := workItems.NewItemRemoveRounds(
+		&mock.ElasticProcessorStub{
+			RemoveEpochDataCalled: func(header data.HeaderHandler) error {
+				return localErr
+			},
+		},
+		&dataBlock.MetaBlock{},
+	)
+	require.False(t, itemRemove.IsInterfaceNil())
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
+}
@@ -0,0 +1,27 @@
+package workItems
+
+type itemSavedNotifier struct {
+	indexer flushNotifierIndexer
+	handler func()
+}
+
+// NewItemSavedNotifier will create a new instance of itemSavedNotifier. As the items are saved in order, the provided
+// handler is called once all the items added before this one were saved, including the blocks that wait in the batch
+func NewItemSavedNotifier(indexer flushNotifierIndexer, handler func()) WorkItemHandler {
+	return &itemSavedNotifier{
+		indexer: indexer,
+		handler: handler,
+	}
+}
+
+// Save will call the handler after the next flush of the blocks batch, without forcing it
+func (wisn *itemSavedNotifier) Save() error {
+	wisn.indexer.NotifyWhenFlushed(wisn.handler)
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wisn *itemSavedNotifier) IsInterfaceNil() bool {
+	return wisn == nil
+}
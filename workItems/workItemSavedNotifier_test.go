@@ -0,0 +1,36 @@
+package workItems_test
+
+import (
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elastic-indexer-go/workItems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemSavedNotifier_Save(t *testing.T) {
+	var flushHandler func()
+	handlerCalled := false
+	itemNotifier := workItems.NewItemSavedNotifier(
+		&mock.ElasticProcessorStub{
+			FlushBatchCalled: func(_ bool) error {
+				require.Fail(t, "the batch should not be flushed by the notifier")
+				return nil
+			},
+			NotifyWhenFlushedCalled: func(handler func()) {
+				flushHandler = handler
+			},
+		},
+		func() {
+			handlerCalled = true
+		},
+	)
+	require.False(t, itemNotifier.IsInterfaceNil())
+
+	err := itemNotifier.Save()
+	require.NoError(t, err)
+	require.False(t, handlerCalled)
+
+	flushHandler()
+	require.True(t, handlerCalled)
+}
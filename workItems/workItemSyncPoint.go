@@ -0,0 +1,33 @@
+package workItems
+
+type itemSyncPoint struct {
+	indexer flushBatchIndexer
+	chDone  chan struct{}
+}
+
+// NewItemSyncPoint will create a new instance of itemSyncPoint. As the items are saved in order, the provided channel is
+// closed once all the items added before the sync point were saved
+func NewItemSyncPoint(indexer flushBatchIndexer, chDone chan struct{}) WorkItemHandler {
+	return &itemSyncPoint{
+		indexer: indexer,
+		chDone:  chDone,
+	}
+}
+
+// Save will send the blocks that wait in the batch and will signal that all the previous items were saved
+func (wisp *itemSyncPoint) Save() error {
+	err := wisp.indexer.FlushBatch(true)
+	if err != nil {
+		log.Warn("itemSyncPoint.Save", "could not flush blocks batch", err.Error())
+		return err
+	}
+
+	close(wisp.chDone)
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wisp *itemSyncPoint) IsInterfaceNil() bool {
+	return wisp == nil
+}
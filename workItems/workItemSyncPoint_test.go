@@ -0,0 +1,52 @@
+package workItems_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElrondNetwork/elastic-indexer-go/mock"
+	"github.com/ElrondNetwork/elastic-indexer-go/workItems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemSyncPoint_Save(t *testing.T) {
+	chDone := make(chan struct{})
+	itemSync := workItems.NewItemSyncPoint(
+		&mock.ElasticProcessorStub{
+			FlushBatchCalled: func(force bool) error {
+				require.True(t, force)
+				return nil
+			},
+		},
+		chDone,
+	)
+	require.False(t, itemSync.IsInterfaceNil())
+
+	err := itemSync.Save()
+	require.NoError(t, err)
+
+	_, isOpen := <-chDone
+	require.False(t, isOpen)
+}
+
+func TestItemSyncPoint_SaveShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	chDone := make(chan struct{})
+	itemSync := workItems.NewItemSyncPoint(
+		&mock.ElasticProcessorStub{
+			FlushBatchCalled: func(force bool) error {
+				return localErr
+			},
+		},
+		chDone,
+	)
+
+	err := itemSync.Save()
+	require.Equal(t, localErr, err)
+
+	select {
+	case <-chDone:
+		require.Fail(t, "the sync point should not signal before the batch is flushed")
+	default:
+	}
+}